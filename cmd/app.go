@@ -60,6 +60,20 @@ func Run() error {
 		comps.WebhookSender.Run(ctx)
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("🚀 webhookRetryScheduler goroutine launched")
+		comps.WebhookRetries.Run(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("🚀 outboxRelay goroutine launched")
+		comps.OutboxRelay.Run(ctx)
+	}()
+
 	// 3. Запуск Воркера 2
 	wg.Add(1)
 	go func() {
@@ -67,6 +81,34 @@ func Run() error {
 		logger.Info("🚀 locationChecker goroutine launched")
 		comps.LocationChecker.Run(ctx)
 	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("🚀 incidentScheduler goroutine launched")
+		comps.IncidentScheduler.Run(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("🚀 statsRollup goroutine launched")
+		comps.StatsRollup.Run(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("🚀 checksRetention goroutine launched")
+		comps.ChecksRetention.Run(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("🚀 deliveryLogRetention goroutine launched")
+		comps.DeliveryRetention.Run(ctx)
+	}()
 	// Graceful shutdown
 	quitChan := make(chan os.Signal, 1)
 	signal.Notify(quitChan, syscall.SIGINT, syscall.SIGTERM)
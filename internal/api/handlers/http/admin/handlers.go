@@ -6,8 +6,10 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"redCollar/internal/domain"
+	"redCollar/pkg/validator"
 
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
@@ -17,7 +19,7 @@ import (
 //go:generate mockgen -source=handlers.go -destination=mocks/mock.go
 type AdminIncidents interface {
 	Create(ctx context.Context, req domain.CreateIncidentRequest) (uuid.UUID, error)
-	List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error)
+	List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error)
 	Get(ctx context.Context, id uuid.UUID) (*domain.Incident, error)
 	Update(ctx context.Context, id uuid.UUID, req domain.UpdateIncidentRequest) error
 	Delete(ctx context.Context, id uuid.UUID) error
@@ -29,6 +31,36 @@ type LocationChecker interface {
 
 type StatsGetter interface {
 	GetStats(ctx context.Context, req domain.StatsRequest) (*domain.IncidentStats, error)
+	GetSeries(ctx context.Context, req domain.StatsSeriesRequest) (*domain.IncidentStats, error)
+	GetIncidentStats(ctx context.Context, id uuid.UUID, req domain.IncidentStatsRequest) (*domain.IncidentHitStats, error)
+}
+
+type CheckHistory interface {
+	ListChecks(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error)
+	IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error)
+	IncidentOccupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error)
+	UserTimeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error)
+}
+
+type DeadLetters interface {
+	ListDeadLetters(ctx context.Context, req domain.ListDeadLettersRequest) (domain.DeadLetterPage, error)
+	GetDeadLetter(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id uuid.UUID) error
+	DeleteDeadLetter(ctx context.Context, id uuid.UUID) error
+	PurgeDeadLetters(ctx context.Context) (int64, error)
+}
+
+type WebhookSubscriptions interface {
+	CreateSubscription(ctx context.Context, req domain.CreateWebhookSubscriptionRequest) (domain.CreateWebhookSubscriptionResponse, error)
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id uuid.UUID, req domain.UpdateWebhookSubscriptionRequest) error
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	RotateSecret(ctx context.Context, id uuid.UUID, req domain.RotateWebhookSecretRequest) (domain.RotateWebhookSecretResponse, error)
+}
+
+type WebhookDeliveries interface {
+	ListDeliveries(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error)
 }
 
 type Handler struct {
@@ -36,14 +68,22 @@ type Handler struct {
 	Admin           AdminIncidents
 	Stats           StatsGetter
 	LocationChecker LocationChecker
+	Checks          CheckHistory
+	DeadLetters     DeadLetters
+	Subscriptions   WebhookSubscriptions
+	Deliveries      WebhookDeliveries
 }
 
-func NewHandler(logger *slog.Logger, admin AdminIncidents, stats StatsGetter, locationChecker LocationChecker) *Handler {
+func NewHandler(logger *slog.Logger, admin AdminIncidents, stats StatsGetter, locationChecker LocationChecker, checks CheckHistory, deadLetters DeadLetters, subscriptions WebhookSubscriptions, deliveries WebhookDeliveries) *Handler {
 	return &Handler{
 		logger:          logger,
 		Admin:           admin,
 		Stats:           stats,
 		LocationChecker: locationChecker,
+		Checks:          checks,
+		DeadLetters:     deadLetters,
+		Subscriptions:   subscriptions,
+		Deliveries:      deliveries,
 	}
 }
 
@@ -70,6 +110,9 @@ func (h *Handler) AdminIncidentCreate(w http.ResponseWriter, r *http.Request) {
 		slog.Float64("lat", req.Lat),
 		slog.Float64("lng", req.Lng),
 		slog.Float64("radius_km", req.RadiusKM),
+		slog.Bool("polygon", req.Geometry != nil),
+		slog.String("severity", string(req.Severity)),
+		slog.String("category", string(req.Category)),
 		slog.String("status", string(req.Status)),
 	)
 
@@ -87,26 +130,40 @@ func (h *Handler) AdminIncidentList(w http.ResponseWriter, r *http.Request) {
 	l := h.log(r)
 	l.Debug("AdminIncidentList", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
 
-	page := parseInt(r.URL.Query().Get("page"), 1)
-	limit := parseInt(r.URL.Query().Get("limit"), 20)
-	if limit > 100 {
-		limit = 100
-		l.Warn("limit capped", slog.Int("limit", limit))
+	req, err := parseListIncidentsRequest(r.URL.Query())
+	if err != nil {
+		l.Warn("invalid query", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if parseInt(r.URL.Query().Get("limit"), 20) > req.Limit {
+		l.Warn("limit capped", slog.Int("limit", req.Limit))
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
 	}
 
-	incidents, total, err := h.Admin.List(r.Context(), page, limit)
+	res, err := h.Admin.List(r.Context(), req)
 	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
 
-	l.Info("incidents listed", slog.Int("count", len(incidents)), slog.Int64("total", total))
-	h.writeJSON(w, http.StatusOK, map[string]any{
-		"incidents": incidents,
-		"total":     total,
-		"page":      page,
-		"limit":     limit,
-	})
+	l.Info("incidents listed", slog.Int("count", len(res.Items)), slog.Int64("total", res.Total))
+
+	resp := domain.ListIncidentsResponse{
+		Incidents:  res.Items,
+		Total:      res.Total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		NextCursor: res.NextCursor,
+	}
+	if req.CursorMode {
+		resp.Page = 0
+	}
+	h.writeJSON(w, http.StatusOK, resp)
 }
 
 func (h *Handler) AdminIncidentGet(w http.ResponseWriter, r *http.Request) {
@@ -203,3 +260,494 @@ func (h *Handler) AdminStats(w http.ResponseWriter, r *http.Request) {
 	l.Info("stats success", slog.Int("minutes", minutes))
 	h.writeJSON(w, http.StatusOK, stats)
 }
+
+func (h *Handler) AdminStatsSeries(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminStatsSeries", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
+
+	req, err := parseStatsSeriesRequest(r.URL.Query(), time.Now().UTC())
+	if err != nil {
+		l.Warn("invalid query", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.Stats.GetSeries(r.Context(), req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	l.Info("stats series success", slog.String("bucket", string(req.Bucket)), slog.Int("points", len(stats.Series)))
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) AdminIncidentStats(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminIncidentStats", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	var req domain.IncidentStatsRequest
+	q := r.URL.Query()
+	if req.From, err = parseTime(q, "from"); err == nil {
+		req.To, err = parseTime(q, "to")
+	}
+	if err != nil {
+		l.Warn("invalid query", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.Stats.GetIncidentStats(r.Context(), id, req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) AdminCheckList(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminCheckList", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
+
+	req, err := parseListChecksRequest(r.URL.Query())
+	if err != nil {
+		l.Warn("invalid query", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Checks.ListChecks(r.Context(), req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	l.Info("checks listed", slog.Int("count", len(res.Items)))
+	h.writeJSON(w, http.StatusOK, domain.ListChecksResponse{
+		Checks:     res.Items,
+		Limit:      req.Limit,
+		NextCursor: res.NextCursor,
+	})
+}
+
+func (h *Handler) AdminIncidentChecks(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminIncidentChecks", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	req, err := parseIncidentVisitorsRequest(r.URL.Query())
+	if err != nil {
+		l.Warn("invalid query", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Checks.IncidentVisitors(r.Context(), id, req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, domain.IncidentVisitorsResponse{
+		IncidentID: id,
+		Users:      res.Items,
+		Limit:      req.Limit,
+		NextCursor: res.NextCursor,
+	})
+}
+
+func (h *Handler) AdminIncidentOccupants(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminIncidentOccupants", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	req, err := parseOccupantsRequest(r.URL.Query())
+	if err != nil {
+		l.Warn("invalid query", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Checks.IncidentOccupants(r.Context(), id, req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, domain.OccupantsResponse{
+		IncidentID: id,
+		Occupants:  res.Items,
+		Limit:      req.Limit,
+		NextCursor: res.NextCursor,
+	})
+}
+
+func (h *Handler) AdminUserTimeline(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminUserTimeline", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	req, err := parseTimelineRequest(r.URL.Query())
+	if err != nil {
+		l.Warn("invalid query", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Checks.UserTimeline(r.Context(), id, req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, domain.TimelineResponse{
+		UserID:     id,
+		Events:     res.Items,
+		Limit:      req.Limit,
+		NextCursor: res.NextCursor,
+	})
+}
+
+func (h *Handler) AdminDeliveryList(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminDeliveryList", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
+
+	req, err := parseListDeliveriesRequest(r.URL.Query())
+	if err != nil {
+		l.Warn("invalid query", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Deliveries.ListDeliveries(r.Context(), req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	l.Info("webhook deliveries listed", slog.Int("count", len(res.Items)))
+	h.writeJSON(w, http.StatusOK, domain.ListWebhookDeliveriesResponse{
+		Deliveries: res.Items,
+		Limit:      req.Limit,
+		NextCursor: res.NextCursor,
+	})
+}
+
+func (h *Handler) AdminDeadLetterList(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminDeadLetterList", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
+
+	q := r.URL.Query()
+	req := domain.ListDeadLettersRequest{
+		Page:  parseInt(q.Get("page"), 1),
+		Limit: parseInt(q.Get("limit"), 20),
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	res, err := h.DeadLetters.ListDeadLetters(r.Context(), req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	l.Info("dead letters listed", slog.Int("count", len(res.Items)), slog.Int64("total", res.Total))
+	h.writeJSON(w, http.StatusOK, domain.ListDeadLettersResponse{
+		DeadLetters: res.Items,
+		Total:       res.Total,
+		Page:        req.Page,
+		Limit:       req.Limit,
+	})
+}
+
+func (h *Handler) AdminDeadLetterGet(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminDeadLetterGet", slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	dl, err := h.DeadLetters.GetDeadLetter(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dl)
+}
+
+func (h *Handler) AdminDeadLetterReplay(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminDeadLetterReplay", slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	if err := h.DeadLetters.ReplayDeadLetter(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	l.Info("dead letter replayed", slog.String("id", id.String()))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) AdminDeadLetterDelete(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminDeadLetterDelete", slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	if err := h.DeadLetters.DeleteDeadLetter(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) AdminDeadLetterPurge(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminDeadLetterPurge", slog.String("remote", r.RemoteAddr))
+
+	n, err := h.DeadLetters.PurgeDeadLetters(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	l.Info("dead letters purged", slog.Int64("count", n))
+	h.writeJSON(w, http.StatusOK, map[string]int64{"purged": n})
+}
+
+func (h *Handler) AdminSubscriptionCreate(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminSubscriptionCreate", slog.String("remote", r.RemoteAddr))
+
+	var req domain.CreateWebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		l.Warn("invalid JSON", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Subscriptions.CreateSubscription(r.Context(), req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	l.Info("webhook subscription created", slog.String("id", res.ID.String()))
+	h.writeJSON(w, http.StatusCreated, res)
+}
+
+func (h *Handler) AdminSubscriptionList(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminSubscriptionList", slog.String("remote", r.RemoteAddr))
+
+	subs, err := h.Subscriptions.ListSubscriptions(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{"subscriptions": subs})
+}
+
+func (h *Handler) AdminSubscriptionGet(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminSubscriptionGet", slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	sub, err := h.Subscriptions.GetSubscription(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, sub)
+}
+
+func (h *Handler) AdminSubscriptionUpdate(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminSubscriptionUpdate", slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	var req domain.UpdateWebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		l.Warn("invalid JSON", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if err := h.Subscriptions.UpdateSubscription(r.Context(), id, req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) AdminSubscriptionDelete(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminSubscriptionDelete", slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	if err := h.Subscriptions.DeleteSubscription(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) AdminSubscriptionRotateSecret(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("AdminSubscriptionRotateSecret", slog.String("remote", r.RemoteAddr))
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		l.Warn("invalid id", slog.String("id", idStr), slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+
+	var req domain.RotateWebhookSecretRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			l.Warn("invalid JSON", slog.String("error", err.Error()))
+			h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Subscriptions.RotateSecret(r.Context(), id, req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	l.Info("webhook secret rotated", slog.String("id", id.String()))
+	h.writeJSON(w, http.StatusOK, res)
+}
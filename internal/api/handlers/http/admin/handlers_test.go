@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"log/slog"
 
@@ -18,6 +20,8 @@ import (
 	"redCollar/internal/api/handlers/http/admin"
 	mock_admin "redCollar/internal/api/handlers/http/admin/mocks"
 	"redCollar/internal/domain"
+	"redCollar/pkg/cursor"
+	"redCollar/pkg/e"
 )
 
 func newTestLogger() *slog.Logger {
@@ -50,7 +54,7 @@ func TestAdminIncidentCreate_OK(t *testing.T) {
 	statsSvc := mock_admin.NewMockStatsGetter(ctrl)
 	locSvc := mock_admin.NewMockLocationChecker(ctrl)
 
-	h := admin.NewHandler(newTestLogger(), adminSvc, statsSvc, locSvc)
+	h := admin.NewHandler(newTestLogger(), adminSvc, statsSvc, locSvc, mock_admin.NewMockCheckHistory(ctrl), mock_admin.NewMockDeadLetters(ctrl), mock_admin.NewMockWebhookSubscriptions(ctrl), mock_admin.NewMockWebhookDeliveries(ctrl))
 
 	reqBody := `{"lat":55.75,"lng":37.61,"radius_km":1}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/incidents/", bytes.NewBufferString(reqBody))
@@ -86,6 +90,10 @@ func TestAdminIncidentCreate_InvalidJSON_400(t *testing.T) {
 		mock_admin.NewMockAdminIncidents(ctrl),
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/incidents/", bytes.NewBufferString("{bad json"))
@@ -108,6 +116,10 @@ func TestAdminIncidentCreate_ServiceError_500or4xx(t *testing.T) {
 	h := admin.NewHandler(newTestLogger(), adminSvc,
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	reqBody := `{"lat":55.75,"lng":37.61,"radius_km":1}`
@@ -136,14 +148,18 @@ func TestAdminIncidentList_Defaults_OK(t *testing.T) {
 	h := admin.NewHandler(newTestLogger(), adminSvc,
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/", nil)
 	rr := httptest.NewRecorder()
 
 	adminSvc.EXPECT().
-		List(gomock.Any(), 1, 20).
-		Return([]*domain.Incident{}, int64(0), nil).
+		List(gomock.Any(), domain.ListIncidentsRequest{Page: 1, Limit: 20}).
+		Return(domain.IncidentPage{Items: []*domain.Incident{}}, nil).
 		Times(1)
 
 	h.AdminIncidentList(rr, req)
@@ -168,14 +184,18 @@ func TestAdminIncidentList_LimitClampedTo100(t *testing.T) {
 	h := admin.NewHandler(newTestLogger(), adminSvc,
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/?page=2&limit=500", nil)
 	rr := httptest.NewRecorder()
 
 	adminSvc.EXPECT().
-		List(gomock.Any(), 2, 100).
-		Return([]*domain.Incident{}, int64(0), nil).
+		List(gomock.Any(), domain.ListIncidentsRequest{Page: 2, Limit: 100}).
+		Return(domain.IncidentPage{Items: []*domain.Incident{}}, nil).
 		Times(1)
 
 	h.AdminIncidentList(rr, req)
@@ -185,6 +205,141 @@ func TestAdminIncidentList_LimitClampedTo100(t *testing.T) {
 	}
 }
 
+func TestAdminIncidentList_Filters_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	adminSvc := mock_admin.NewMockAdminIncidents(ctrl)
+	h := admin.NewHandler(newTestLogger(), adminSvc,
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/admin/incidents/?status=active&created_from=2025-01-01T00:00:00Z&created_to=2025-02-01T00:00:00Z"+
+			"&radius_min=1&radius_max=5&bbox=37.5,55.7,37.7,55.8&near_lat=55.75&near_lng=37.61&within_km=3"+
+			"&sort_by=severity&sort_dir=ASC", nil)
+	rr := httptest.NewRecorder()
+
+	want := domain.ListIncidentsRequest{
+		Page:        1,
+		Limit:       20,
+		Status:      domain.IncidentActive,
+		CreatedFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedTo:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		RadiusMin:   1,
+		RadiusMax:   5,
+		BBox:        &domain.BBox{MinLng: 37.5, MinLat: 55.7, MaxLng: 37.7, MaxLat: 55.8},
+		Near:        &domain.NearFilter{Lat: 55.75, Lng: 37.61, WithinKM: 3},
+		SortBy:      domain.SortBySeverity,
+		SortDir:     domain.SortAsc,
+	}
+
+	adminSvc.EXPECT().
+		List(gomock.Any(), want).
+		Return(domain.IncidentPage{Items: []*domain.Incident{}}, nil).
+		Times(1)
+
+	h.AdminIncidentList(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminIncidentList_CursorMode_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	adminSvc := mock_admin.NewMockAdminIncidents(ctrl)
+	h := admin.NewHandler(newTestLogger(), adminSvc,
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	after := cursor.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), uuid.New())
+	next := cursor.New(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), uuid.New()).Encode()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/?limit=10&cursor="+after.Encode(), nil)
+	rr := httptest.NewRecorder()
+
+	adminSvc.EXPECT().
+		List(gomock.Any(), domain.ListIncidentsRequest{Page: 1, Limit: 10, CursorMode: true, After: after}).
+		Return(domain.IncidentPage{Items: []*domain.Incident{}, Total: 42, NextCursor: next}, nil).
+		Times(1)
+
+	h.AdminIncidentList(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	resp := decodeJSON[map[string]any](t, rr)
+	if resp["next_cursor"] != next {
+		t.Fatalf("unexpected next_cursor: %+v", resp)
+	}
+	if _, ok := resp["page"]; ok {
+		t.Fatalf("page must be omitted in cursor mode: %+v", resp)
+	}
+}
+
+func TestAdminIncidentList_InvalidFilters_400(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"unknown status":    "status=deleted",
+		"bad date":          "created_from=yesterday",
+		"reversed dates":    "created_from=2025-02-01T00:00:00Z&created_to=2025-01-01T00:00:00Z",
+		"reversed radius":   "radius_min=5&radius_max=1",
+		"bbox arity":        "bbox=1,2,3",
+		"bbox reversed":     "bbox=37.7,55.7,37.5,55.8",
+		"near incomplete":   "near_lat=55.75&near_lng=37.61",
+		"near out of range": "near_lat=95&near_lng=37.61&within_km=1",
+		"unknown sort":      "sort_by=lat",
+		"bad direction":     "sort_dir=up",
+		"bad cursor":        "cursor=bm9wZQ",
+		"cursor with sort":  "cursor=&sort_by=title",
+	}
+
+	for name, query := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := admin.NewHandler(newTestLogger(),
+				mock_admin.NewMockAdminIncidents(ctrl),
+				mock_admin.NewMockStatsGetter(ctrl),
+				mock_admin.NewMockLocationChecker(ctrl),
+				mock_admin.NewMockCheckHistory(ctrl),
+				mock_admin.NewMockDeadLetters(ctrl),
+				mock_admin.NewMockWebhookSubscriptions(ctrl),
+				mock_admin.NewMockWebhookDeliveries(ctrl),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/?"+query, nil)
+			rr := httptest.NewRecorder()
+
+			h.AdminIncidentList(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
 func TestAdminIncidentGet_InvalidID_400(t *testing.T) {
 	t.Parallel()
 
@@ -195,6 +350,10 @@ func TestAdminIncidentGet_InvalidID_400(t *testing.T) {
 		mock_admin.NewMockAdminIncidents(ctrl),
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/bad/", nil)
@@ -218,6 +377,10 @@ func TestAdminIncidentGet_OK(t *testing.T) {
 	h := admin.NewHandler(newTestLogger(), adminSvc,
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	id := uuid.New()
@@ -254,6 +417,10 @@ func TestAdminIncidentUpdate_InvalidID_400(t *testing.T) {
 		mock_admin.NewMockAdminIncidents(ctrl),
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/incidents/bad/", bytes.NewBufferString(`{}`))
@@ -277,6 +444,10 @@ func TestAdminIncidentUpdate_InvalidJSON_400(t *testing.T) {
 		mock_admin.NewMockAdminIncidents(ctrl),
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	id := uuid.New()
@@ -301,6 +472,10 @@ func TestAdminIncidentUpdate_OK_204(t *testing.T) {
 	h := admin.NewHandler(newTestLogger(), adminSvc,
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	id := uuid.New()
@@ -336,6 +511,10 @@ func TestAdminIncidentDelete_InvalidID_400(t *testing.T) {
 		mock_admin.NewMockAdminIncidents(ctrl),
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/incidents/bad/", nil)
@@ -359,6 +538,10 @@ func TestAdminIncidentDelete_OK_204(t *testing.T) {
 	h := admin.NewHandler(newTestLogger(), adminSvc,
 		mock_admin.NewMockStatsGetter(ctrl),
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	id := uuid.New()
@@ -389,6 +572,10 @@ func TestAdminStats_OK(t *testing.T) {
 		mock_admin.NewMockAdminIncidents(ctrl),
 		statsSvc,
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/stats?minutes=60", nil)
@@ -423,6 +610,10 @@ func TestAdminStats_DefaultMinutes_60(t *testing.T) {
 		mock_admin.NewMockAdminIncidents(ctrl),
 		statsSvc,
 		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
 	)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/stats", nil)
@@ -439,3 +630,870 @@ func TestAdminStats_DefaultMinutes_60(t *testing.T) {
 		t.Fatalf("expected %d got %d", http.StatusOK, rr.Code)
 	}
 }
+
+func TestAdminCheckList_Filters_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checks := mock_admin.NewMockCheckHistory(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		checks,
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	userID := uuid.New()
+	incidentID := uuid.New()
+	after := cursor.New(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), uuid.New())
+	next := cursor.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), uuid.New()).Encode()
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/admin/checks?user_id="+userID.String()+"&incident_id="+incidentID.String()+
+			"&had_hits=true&from=2025-01-01T00:00:00Z&to=2025-01-03T00:00:00Z&bbox=37.5,55.7,37.7,55.8"+
+			"&limit=50&cursor="+after.Encode(), nil)
+	rr := httptest.NewRecorder()
+
+	checks.EXPECT().
+		ListChecks(gomock.Any(), domain.ListChecksRequest{
+			Limit:      50,
+			UserID:     userID,
+			From:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:         time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+			IncidentID: incidentID,
+			HadHits:    true,
+			BBox:       &domain.BBox{MinLng: 37.5, MinLat: 55.7, MaxLng: 37.7, MaxLat: 55.8},
+			After:      after,
+		}).
+		Return(domain.CheckPage{Items: []*domain.LocationCheck{{ID: uuid.New(), UserID: userID}}, NextCursor: next}, nil).
+		Times(1)
+
+	h.AdminCheckList(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	resp := decodeJSON[domain.ListChecksResponse](t, rr)
+	if len(resp.Checks) != 1 || resp.NextCursor != next || resp.Limit != 50 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminCheckList_InvalidFilters_400(t *testing.T) {
+	t.Parallel()
+
+	for _, query := range []string{
+		"user_id=nope",
+		"incident_id=nope",
+		"had_hits=maybe",
+		"from=2025-01-03T00:00:00Z&to=2025-01-01T00:00:00Z",
+		"bbox=1,2",
+		"cursor=bm9wZQ",
+	} {
+		t.Run(query, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := admin.NewHandler(newTestLogger(),
+				mock_admin.NewMockAdminIncidents(ctrl),
+				mock_admin.NewMockStatsGetter(ctrl),
+				mock_admin.NewMockLocationChecker(ctrl),
+				mock_admin.NewMockCheckHistory(ctrl),
+				mock_admin.NewMockDeadLetters(ctrl),
+				mock_admin.NewMockWebhookSubscriptions(ctrl),
+				mock_admin.NewMockWebhookDeliveries(ctrl),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/checks?"+query, nil)
+			rr := httptest.NewRecorder()
+
+			h.AdminCheckList(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminIncidentChecks_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checks := mock_admin.NewMockCheckHistory(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		checks,
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	id := uuid.New()
+	visitor := domain.IncidentVisitor{
+		UserID:      uuid.New(),
+		FirstSeenAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		LastSeenAt:  time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC),
+		Checks:      3,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/"+id.String()+"/checks", nil)
+	req = addChiURLParam(req, "id", id.String())
+	rr := httptest.NewRecorder()
+
+	checks.EXPECT().
+		IncidentVisitors(gomock.Any(), id, domain.IncidentVisitorsRequest{Limit: 20}).
+		Return(domain.VisitorPage{Items: []domain.IncidentVisitor{visitor}}, nil).
+		Times(1)
+
+	h.AdminIncidentChecks(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	resp := decodeJSON[domain.IncidentVisitorsResponse](t, rr)
+	if resp.IncidentID != id || len(resp.Users) != 1 || resp.Users[0].Checks != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminIncidentChecks_InvalidID_400(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/bad/checks", nil)
+	req = addChiURLParam(req, "id", "bad")
+	rr := httptest.NewRecorder()
+
+	h.AdminIncidentChecks(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminIncidentOccupants_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checks := mock_admin.NewMockCheckHistory(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		checks,
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	id := uuid.New()
+	occupant := domain.Occupant{
+		UserID:     uuid.New(),
+		EnteredAt:  time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		LastSeenAt: time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/"+id.String()+"/occupants?limit=5", nil)
+	req = addChiURLParam(req, "id", id.String())
+	rr := httptest.NewRecorder()
+
+	checks.EXPECT().
+		IncidentOccupants(gomock.Any(), id, domain.OccupantsRequest{Limit: 5}).
+		Return(domain.OccupantPage{Items: []domain.Occupant{occupant}, NextCursor: "next"}, nil).
+		Times(1)
+
+	h.AdminIncidentOccupants(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	resp := decodeJSON[domain.OccupantsResponse](t, rr)
+	if resp.IncidentID != id || len(resp.Occupants) != 1 || resp.Occupants[0].UserID != occupant.UserID || resp.NextCursor != "next" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminUserTimeline_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checks := mock_admin.NewMockCheckHistory(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		checks,
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	userID := uuid.New()
+	incidentID := uuid.New()
+	event := domain.OccupancyTransition{
+		ID:         uuid.New(),
+		UserID:     userID,
+		IncidentID: incidentID,
+		Kind:       domain.OccupancyEntered,
+		At:         time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/"+userID.String()+"/timeline?incident_id="+incidentID.String(), nil)
+	req = addChiURLParam(req, "id", userID.String())
+	rr := httptest.NewRecorder()
+
+	checks.EXPECT().
+		UserTimeline(gomock.Any(), userID, domain.TimelineRequest{Limit: 20, IncidentID: incidentID}).
+		Return(domain.TimelinePage{Items: []domain.OccupancyTransition{event}}, nil).
+		Times(1)
+
+	h.AdminUserTimeline(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	resp := decodeJSON[domain.TimelineResponse](t, rr)
+	if resp.UserID != userID || len(resp.Events) != 1 || resp.Events[0].Kind != domain.OccupancyEntered {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminUserTimeline_InvalidRange_400(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	userID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/"+userID.String()+"/timeline?from=2025-01-02T00:00:00Z&to=2025-01-01T00:00:00Z", nil)
+	req = addChiURLParam(req, "id", userID.String())
+	rr := httptest.NewRecorder()
+
+	h.AdminUserTimeline(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminStatsSeries_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statsSvc := mock_admin.NewMockStatsGetter(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		statsSvc,
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/admin/stats/series?from=2025-01-01T00:00:00Z&to=2025-01-02T00:00:00Z&bucket=minute&top=3", nil)
+	rr := httptest.NewRecorder()
+
+	statsSvc.EXPECT().
+		GetSeries(gomock.Any(), domain.StatsSeriesRequest{From: from, To: to, Bucket: domain.BucketMinute, Top: 3}).
+		Return(&domain.IncidentStats{TotalChecks: 5, Bucket: domain.BucketMinute}, nil).
+		Times(1)
+
+	h.AdminStatsSeries(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminStatsSeries_Invalid_400(t *testing.T) {
+	t.Parallel()
+
+	for _, query := range []string{
+		"bucket=week",
+		"from=2025-01-02T00:00:00Z&to=2025-01-01T00:00:00Z",
+		"top=1000",
+		"to=tomorrow",
+	} {
+		t.Run(query, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := admin.NewHandler(newTestLogger(),
+				mock_admin.NewMockAdminIncidents(ctrl),
+				mock_admin.NewMockStatsGetter(ctrl),
+				mock_admin.NewMockLocationChecker(ctrl),
+				mock_admin.NewMockCheckHistory(ctrl),
+				mock_admin.NewMockDeadLetters(ctrl),
+				mock_admin.NewMockWebhookSubscriptions(ctrl),
+				mock_admin.NewMockWebhookDeliveries(ctrl),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/stats/series?"+query, nil)
+			rr := httptest.NewRecorder()
+
+			h.AdminStatsSeries(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminIncidentStats_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statsSvc := mock_admin.NewMockStatsGetter(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		statsSvc,
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	id := uuid.New()
+	hour := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/"+id.String()+"/stats", nil)
+	req = addChiURLParam(req, "id", id.String())
+	rr := httptest.NewRecorder()
+
+	statsSvc.EXPECT().
+		GetIncidentStats(gomock.Any(), id, domain.IncidentStatsRequest{}).
+		Return(&domain.IncidentHitStats{
+			IncidentID:  id,
+			TotalHits:   4,
+			UniqueUsers: 2,
+			FirstHitAt:  &hour,
+			LastHitAt:   &hour,
+			Hourly:      []domain.HourlyHits{{Hour: hour, Hits: 4, UniqueUsers: 2}},
+		}, nil).
+		Times(1)
+
+	h.AdminIncidentStats(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	resp := decodeJSON[domain.IncidentHitStats](t, rr)
+	if resp.TotalHits != 4 || resp.UniqueUsers != 2 || len(resp.Hourly) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminIncidentStats_NotFound_404(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statsSvc := mock_admin.NewMockStatsGetter(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		statsSvc,
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/incidents/"+id.String()+"/stats", nil)
+	req = addChiURLParam(req, "id", id.String())
+	rr := httptest.NewRecorder()
+
+	statsSvc.EXPECT().
+		GetIncidentStats(gomock.Any(), id, gomock.Any()).
+		Return(nil, fmt.Errorf("postgres: %w", e.ErrNotFound)).
+		Times(1)
+
+	h.AdminIncidentStats(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected %d got %d body=%s", http.StatusNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminDeadLetterList_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dls := mock_admin.NewMockDeadLetters(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		dls,
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/webhooks/dead-letters/?page=2&limit=5", nil)
+	rr := httptest.NewRecorder()
+
+	dl := domain.DeadLetter{
+		WebhookMessage: domain.WebhookMessage{ID: uuid.New(), Attempts: 3},
+		LastError:      "502 Bad Gateway",
+	}
+	dls.EXPECT().
+		ListDeadLetters(gomock.Any(), domain.ListDeadLettersRequest{Page: 2, Limit: 5}).
+		Return(domain.DeadLetterPage{Items: []domain.DeadLetter{dl}, Total: 6}, nil).
+		Times(1)
+
+	h.AdminDeadLetterList(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	resp := decodeJSON[domain.ListDeadLettersResponse](t, rr)
+	if resp.Total != 6 || len(resp.DeadLetters) != 1 || resp.DeadLetters[0].ID != dl.ID {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminDeadLetterList_InvalidLimit_400(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/webhooks/dead-letters/?limit=500", nil)
+	rr := httptest.NewRecorder()
+
+	h.AdminDeadLetterList(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminDeadLetterReplay_NotFound_404(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dls := mock_admin.NewMockDeadLetters(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		dls,
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/webhooks/dead-letters/"+id.String()+"/replay", nil)
+	req = addChiURLParam(req, "id", id.String())
+	rr := httptest.NewRecorder()
+
+	dls.EXPECT().
+		ReplayDeadLetter(gomock.Any(), id).
+		Return(e.ErrNotFound).
+		Times(1)
+
+	h.AdminDeadLetterReplay(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected %d got %d body=%s", http.StatusNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminDeadLetterReplay_Accepted(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dls := mock_admin.NewMockDeadLetters(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		dls,
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/webhooks/dead-letters/"+id.String()+"/replay", nil)
+	req = addChiURLParam(req, "id", id.String())
+	rr := httptest.NewRecorder()
+
+	dls.EXPECT().ReplayDeadLetter(gomock.Any(), id).Return(nil).Times(1)
+
+	h.AdminDeadLetterReplay(rr, req)
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected %d got %d body=%s", http.StatusAccepted, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminDeadLetterPurge_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dls := mock_admin.NewMockDeadLetters(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		dls,
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/webhooks/dead-letters/", nil)
+	rr := httptest.NewRecorder()
+
+	dls.EXPECT().PurgeDeadLetters(gomock.Any()).Return(int64(7), nil).Times(1)
+
+	h.AdminDeadLetterPurge(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if resp := decodeJSON[map[string]int64](t, rr); resp["purged"] != 7 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminSubscriptionCreate_Created(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	subs := mock_admin.NewMockWebhookSubscriptions(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		subs,
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	body := `{"url":"https://example.com/hook","event_types":["location.hit"],"severities":["high","critical"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/webhooks/subscriptions/", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+
+	id := uuid.New()
+	subs.EXPECT().
+		CreateSubscription(gomock.Any(), domain.CreateWebhookSubscriptionRequest{
+			URL:        "https://example.com/hook",
+			EventTypes: []domain.WebhookEventType{domain.WebhookEventLocationHit},
+			Severities: []domain.IncidentSeverity{domain.SeverityHigh, domain.SeverityCritical},
+		}).
+		Return(domain.CreateWebhookSubscriptionResponse{ID: id, Secret: "s3cr3t"}, nil).
+		Times(1)
+
+	h.AdminSubscriptionCreate(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected %d got %d body=%s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	if resp := decodeJSON[domain.CreateWebhookSubscriptionResponse](t, rr); resp.ID != id || resp.Secret != "s3cr3t" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminSubscriptionCreate_UnknownCategory_400(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	body := `{"url":"https://example.com/hook","categories":["meteor"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/webhooks/subscriptions/", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+
+	h.AdminSubscriptionCreate(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminSubscriptionGet_HidesSecret(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	subs := mock_admin.NewMockWebhookSubscriptions(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		subs,
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/webhooks/subscriptions/"+id.String(), nil)
+	req = addChiURLParam(req, "id", id.String())
+	rr := httptest.NewRecorder()
+
+	subs.EXPECT().
+		GetSubscription(gomock.Any(), id).
+		Return(&domain.WebhookSubscription{ID: id, URL: "https://example.com/hook", Secret: "s3cr3t", Active: true}, nil).
+		Times(1)
+
+	h.AdminSubscriptionGet(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if bytes.Contains(rr.Body.Bytes(), []byte("s3cr3t")) {
+		t.Fatalf("secret leaked: %s", rr.Body.String())
+	}
+}
+
+func TestAdminSubscriptionRotateSecret_EmptyBody(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	subs := mock_admin.NewMockWebhookSubscriptions(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		subs,
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/webhooks/subscriptions/"+id.String()+"/rotate-secret", nil)
+	req = addChiURLParam(req, "id", id.String())
+	rr := httptest.NewRecorder()
+
+	expires := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	subs.EXPECT().
+		RotateSecret(gomock.Any(), id, domain.RotateWebhookSecretRequest{}).
+		Return(domain.RotateWebhookSecretResponse{ID: id, Secret: "n3w", PreviousSecretExpiresAt: expires}, nil).
+		Times(1)
+
+	h.AdminSubscriptionRotateSecret(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	resp := decodeJSON[domain.RotateWebhookSecretResponse](t, rr)
+	if resp.Secret != "n3w" || !resp.PreviousSecretExpiresAt.Equal(expires) {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminSubscriptionRotateSecret_ShortSecret_400(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		mock_admin.NewMockWebhookDeliveries(ctrl),
+	)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/webhooks/subscriptions/"+id.String()+"/rotate-secret", bytes.NewBufferString(`{"secret":"short"}`))
+	req = addChiURLParam(req, "id", id.String())
+	rr := httptest.NewRecorder()
+
+	h.AdminSubscriptionRotateSecret(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminDeliveryList_Filters_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deliveries := mock_admin.NewMockWebhookDeliveries(ctrl)
+	h := admin.NewHandler(newTestLogger(),
+		mock_admin.NewMockAdminIncidents(ctrl),
+		mock_admin.NewMockStatsGetter(ctrl),
+		mock_admin.NewMockLocationChecker(ctrl),
+		mock_admin.NewMockCheckHistory(ctrl),
+		mock_admin.NewMockDeadLetters(ctrl),
+		mock_admin.NewMockWebhookSubscriptions(ctrl),
+		deliveries,
+	)
+
+	userID := uuid.New()
+	subID := uuid.Nil
+	next := cursor.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), uuid.New()).Encode()
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/admin/webhooks/deliveries?status=dead&user_id="+userID.String()+"&subscription_id="+subID.String()+
+			"&from=2025-01-01T00:00:00Z&to=2025-01-03T00:00:00Z&limit=10", nil)
+	rr := httptest.NewRecorder()
+
+	deliveries.EXPECT().
+		ListDeliveries(gomock.Any(), domain.ListWebhookDeliveriesRequest{
+			Limit:          10,
+			Status:         domain.WebhookDeliveryDead,
+			UserID:         userID,
+			SubscriptionID: &subID,
+			From:           time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:             time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+		}).
+		Return(domain.WebhookDeliveryPage{
+			Items: []*domain.WebhookDeliveryAttempt{{
+				ID:         uuid.New(),
+				UserID:     userID.String(),
+				Status:     domain.WebhookDeliveryDead,
+				StatusCode: http.StatusBadGateway,
+			}},
+			NextCursor: next,
+		}, nil).
+		Times(1)
+
+	h.AdminDeliveryList(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	resp := decodeJSON[domain.ListWebhookDeliveriesResponse](t, rr)
+	if len(resp.Deliveries) != 1 || resp.Deliveries[0].StatusCode != http.StatusBadGateway || resp.NextCursor != next {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminDeliveryList_InvalidFilters_400(t *testing.T) {
+	t.Parallel()
+
+	for _, query := range []string{
+		"status=lost",
+		"user_id=nope",
+		"subscription_id=nope",
+		"from=2025-01-03T00:00:00Z&to=2025-01-01T00:00:00Z",
+	} {
+		t.Run(query, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := admin.NewHandler(newTestLogger(),
+				mock_admin.NewMockAdminIncidents(ctrl),
+				mock_admin.NewMockStatsGetter(ctrl),
+				mock_admin.NewMockLocationChecker(ctrl),
+				mock_admin.NewMockCheckHistory(ctrl),
+				mock_admin.NewMockDeadLetters(ctrl),
+				mock_admin.NewMockWebhookSubscriptions(ctrl),
+				mock_admin.NewMockWebhookDeliveries(ctrl),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/webhooks/deliveries?"+query, nil)
+			rr := httptest.NewRecorder()
+
+			h.AdminDeliveryList(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
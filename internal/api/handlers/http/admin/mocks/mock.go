@@ -81,19 +81,18 @@ func (mr *MockAdminIncidentsMockRecorder) Get(ctx, id interface{}) *gomock.Call
 }
 
 // List mocks base method.
-func (m *MockAdminIncidents) List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error) {
+func (m *MockAdminIncidents) List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, page, limit)
-	ret0, _ := ret[0].([]*domain.Incident)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(domain.IncidentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockAdminIncidentsMockRecorder) List(ctx, page, limit interface{}) *gomock.Call {
+func (mr *MockAdminIncidentsMockRecorder) List(ctx, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAdminIncidents)(nil).List), ctx, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAdminIncidents)(nil).List), ctx, req)
 }
 
 // Update mocks base method.
@@ -171,6 +170,36 @@ func (m *MockStatsGetter) EXPECT() *MockStatsGetterMockRecorder {
 	return m.recorder
 }
 
+// GetIncidentStats mocks base method.
+func (m *MockStatsGetter) GetIncidentStats(ctx context.Context, id uuid.UUID, req domain.IncidentStatsRequest) (*domain.IncidentHitStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentStats", ctx, id, req)
+	ret0, _ := ret[0].(*domain.IncidentHitStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentStats indicates an expected call of GetIncidentStats.
+func (mr *MockStatsGetterMockRecorder) GetIncidentStats(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentStats", reflect.TypeOf((*MockStatsGetter)(nil).GetIncidentStats), ctx, id, req)
+}
+
+// GetSeries mocks base method.
+func (m *MockStatsGetter) GetSeries(ctx context.Context, req domain.StatsSeriesRequest) (*domain.IncidentStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeries", ctx, req)
+	ret0, _ := ret[0].(*domain.IncidentStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeries indicates an expected call of GetSeries.
+func (mr *MockStatsGetterMockRecorder) GetSeries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeries", reflect.TypeOf((*MockStatsGetter)(nil).GetSeries), ctx, req)
+}
+
 // GetStats mocks base method.
 func (m *MockStatsGetter) GetStats(ctx context.Context, req domain.StatsRequest) (*domain.IncidentStats, error) {
 	m.ctrl.T.Helper()
@@ -185,3 +214,331 @@ func (mr *MockStatsGetterMockRecorder) GetStats(ctx, req interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockStatsGetter)(nil).GetStats), ctx, req)
 }
+
+// MockCheckHistory is a mock of CheckHistory interface.
+type MockCheckHistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckHistoryMockRecorder
+}
+
+// MockCheckHistoryMockRecorder is the mock recorder for MockCheckHistory.
+type MockCheckHistoryMockRecorder struct {
+	mock *MockCheckHistory
+}
+
+// NewMockCheckHistory creates a new mock instance.
+func NewMockCheckHistory(ctrl *gomock.Controller) *MockCheckHistory {
+	mock := &MockCheckHistory{ctrl: ctrl}
+	mock.recorder = &MockCheckHistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckHistory) EXPECT() *MockCheckHistoryMockRecorder {
+	return m.recorder
+}
+
+// IncidentOccupants mocks base method.
+func (m *MockCheckHistory) IncidentOccupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncidentOccupants", ctx, incidentID, req)
+	ret0, _ := ret[0].(domain.OccupantPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncidentOccupants indicates an expected call of IncidentOccupants.
+func (mr *MockCheckHistoryMockRecorder) IncidentOccupants(ctx, incidentID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncidentOccupants", reflect.TypeOf((*MockCheckHistory)(nil).IncidentOccupants), ctx, incidentID, req)
+}
+
+// IncidentVisitors mocks base method.
+func (m *MockCheckHistory) IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncidentVisitors", ctx, incidentID, req)
+	ret0, _ := ret[0].(domain.VisitorPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncidentVisitors indicates an expected call of IncidentVisitors.
+func (mr *MockCheckHistoryMockRecorder) IncidentVisitors(ctx, incidentID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncidentVisitors", reflect.TypeOf((*MockCheckHistory)(nil).IncidentVisitors), ctx, incidentID, req)
+}
+
+// ListChecks mocks base method.
+func (m *MockCheckHistory) ListChecks(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChecks", ctx, req)
+	ret0, _ := ret[0].(domain.CheckPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChecks indicates an expected call of ListChecks.
+func (mr *MockCheckHistoryMockRecorder) ListChecks(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChecks", reflect.TypeOf((*MockCheckHistory)(nil).ListChecks), ctx, req)
+}
+
+// UserTimeline mocks base method.
+func (m *MockCheckHistory) UserTimeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserTimeline", ctx, userID, req)
+	ret0, _ := ret[0].(domain.TimelinePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserTimeline indicates an expected call of UserTimeline.
+func (mr *MockCheckHistoryMockRecorder) UserTimeline(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserTimeline", reflect.TypeOf((*MockCheckHistory)(nil).UserTimeline), ctx, userID, req)
+}
+
+// MockDeadLetters is a mock of DeadLetters interface.
+type MockDeadLetters struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLettersMockRecorder
+}
+
+// MockDeadLettersMockRecorder is the mock recorder for MockDeadLetters.
+type MockDeadLettersMockRecorder struct {
+	mock *MockDeadLetters
+}
+
+// NewMockDeadLetters creates a new mock instance.
+func NewMockDeadLetters(ctrl *gomock.Controller) *MockDeadLetters {
+	mock := &MockDeadLetters{ctrl: ctrl}
+	mock.recorder = &MockDeadLettersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetters) EXPECT() *MockDeadLettersMockRecorder {
+	return m.recorder
+}
+
+// DeleteDeadLetter mocks base method.
+func (m *MockDeadLetters) DeleteDeadLetter(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetter", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetter indicates an expected call of DeleteDeadLetter.
+func (mr *MockDeadLettersMockRecorder) DeleteDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetter", reflect.TypeOf((*MockDeadLetters)(nil).DeleteDeadLetter), ctx, id)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockDeadLetters) GetDeadLetter(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", ctx, id)
+	ret0, _ := ret[0].(*domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockDeadLettersMockRecorder) GetDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockDeadLetters)(nil).GetDeadLetter), ctx, id)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockDeadLetters) ListDeadLetters(ctx context.Context, req domain.ListDeadLettersRequest) (domain.DeadLetterPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, req)
+	ret0, _ := ret[0].(domain.DeadLetterPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockDeadLettersMockRecorder) ListDeadLetters(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockDeadLetters)(nil).ListDeadLetters), ctx, req)
+}
+
+// PurgeDeadLetters mocks base method.
+func (m *MockDeadLetters) PurgeDeadLetters(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeadLetters", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeadLetters indicates an expected call of PurgeDeadLetters.
+func (mr *MockDeadLettersMockRecorder) PurgeDeadLetters(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeadLetters", reflect.TypeOf((*MockDeadLetters)(nil).PurgeDeadLetters), ctx)
+}
+
+// ReplayDeadLetter mocks base method.
+func (m *MockDeadLetters) ReplayDeadLetter(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayDeadLetter", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayDeadLetter indicates an expected call of ReplayDeadLetter.
+func (mr *MockDeadLettersMockRecorder) ReplayDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetter", reflect.TypeOf((*MockDeadLetters)(nil).ReplayDeadLetter), ctx, id)
+}
+
+// MockWebhookSubscriptions is a mock of WebhookSubscriptions interface.
+type MockWebhookSubscriptions struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookSubscriptionsMockRecorder
+}
+
+// MockWebhookSubscriptionsMockRecorder is the mock recorder for MockWebhookSubscriptions.
+type MockWebhookSubscriptionsMockRecorder struct {
+	mock *MockWebhookSubscriptions
+}
+
+// NewMockWebhookSubscriptions creates a new mock instance.
+func NewMockWebhookSubscriptions(ctrl *gomock.Controller) *MockWebhookSubscriptions {
+	mock := &MockWebhookSubscriptions{ctrl: ctrl}
+	mock.recorder = &MockWebhookSubscriptionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookSubscriptions) EXPECT() *MockWebhookSubscriptionsMockRecorder {
+	return m.recorder
+}
+
+// CreateSubscription mocks base method.
+func (m *MockWebhookSubscriptions) CreateSubscription(ctx context.Context, req domain.CreateWebhookSubscriptionRequest) (domain.CreateWebhookSubscriptionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", ctx, req)
+	ret0, _ := ret[0].(domain.CreateWebhookSubscriptionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockWebhookSubscriptionsMockRecorder) CreateSubscription(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockWebhookSubscriptions)(nil).CreateSubscription), ctx, req)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockWebhookSubscriptions) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockWebhookSubscriptionsMockRecorder) DeleteSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockWebhookSubscriptions)(nil).DeleteSubscription), ctx, id)
+}
+
+// GetSubscription mocks base method.
+func (m *MockWebhookSubscriptions) GetSubscription(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", ctx, id)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockWebhookSubscriptionsMockRecorder) GetSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockWebhookSubscriptions)(nil).GetSubscription), ctx, id)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockWebhookSubscriptions) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", ctx)
+	ret0, _ := ret[0].([]*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockWebhookSubscriptionsMockRecorder) ListSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockWebhookSubscriptions)(nil).ListSubscriptions), ctx)
+}
+
+// RotateSecret mocks base method.
+func (m *MockWebhookSubscriptions) RotateSecret(ctx context.Context, id uuid.UUID, req domain.RotateWebhookSecretRequest) (domain.RotateWebhookSecretResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSecret", ctx, id, req)
+	ret0, _ := ret[0].(domain.RotateWebhookSecretResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSecret indicates an expected call of RotateSecret.
+func (mr *MockWebhookSubscriptionsMockRecorder) RotateSecret(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSecret", reflect.TypeOf((*MockWebhookSubscriptions)(nil).RotateSecret), ctx, id, req)
+}
+
+// UpdateSubscription mocks base method.
+func (m *MockWebhookSubscriptions) UpdateSubscription(ctx context.Context, id uuid.UUID, req domain.UpdateWebhookSubscriptionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscription", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSubscription indicates an expected call of UpdateSubscription.
+func (mr *MockWebhookSubscriptionsMockRecorder) UpdateSubscription(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscription", reflect.TypeOf((*MockWebhookSubscriptions)(nil).UpdateSubscription), ctx, id, req)
+}
+
+// MockWebhookDeliveries is a mock of WebhookDeliveries interface.
+type MockWebhookDeliveries struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveriesMockRecorder
+}
+
+// MockWebhookDeliveriesMockRecorder is the mock recorder for MockWebhookDeliveries.
+type MockWebhookDeliveriesMockRecorder struct {
+	mock *MockWebhookDeliveries
+}
+
+// NewMockWebhookDeliveries creates a new mock instance.
+func NewMockWebhookDeliveries(ctrl *gomock.Controller) *MockWebhookDeliveries {
+	mock := &MockWebhookDeliveries{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveries) EXPECT() *MockWebhookDeliveriesMockRecorder {
+	return m.recorder
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookDeliveries) ListDeliveries(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, req)
+	ret0, _ := ret[0].(domain.WebhookDeliveryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookDeliveriesMockRecorder) ListDeliveries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookDeliveries)(nil).ListDeliveries), ctx, req)
+}
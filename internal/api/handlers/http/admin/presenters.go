@@ -3,10 +3,18 @@ package admin
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"redCollar/internal/domain"
+	"redCollar/pkg/cursor"
 	"redCollar/pkg/e"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 func (h *Handler) handleError(w http.ResponseWriter, r *http.Request, err error) {
@@ -46,3 +54,307 @@ func parseInt(s string, def int) int {
 	}
 	return i
 }
+
+// parseListIncidentsRequest разбирает фильтры списка инцидентов. page/limit
+// по-прежнему мягкие (дефолты и потолок 100), остальные параметры при ошибке
+// формата отдают ошибку — молча игнорировать фильтр хуже, чем 400.
+func parseListIncidentsRequest(q url.Values) (domain.ListIncidentsRequest, error) {
+	req := domain.ListIncidentsRequest{
+		Page:    parseInt(q.Get("page"), 1),
+		Limit:   parseInt(q.Get("limit"), 20),
+		Status:  domain.IncidentStatus(q.Get("status")),
+		SortBy:  domain.IncidentSortField(q.Get("sort_by")),
+		SortDir: domain.SortDirection(strings.ToLower(q.Get("sort_dir"))),
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	var err error
+	if req.CreatedFrom, err = parseTime(q, "created_from"); err != nil {
+		return req, err
+	}
+	if req.CreatedTo, err = parseTime(q, "created_to"); err != nil {
+		return req, err
+	}
+	if req.RadiusMin, err = parseFloat(q, "radius_min"); err != nil {
+		return req, err
+	}
+	if req.RadiusMax, err = parseFloat(q, "radius_max"); err != nil {
+		return req, err
+	}
+
+	if req.BBox, err = parseBBox(q.Get("bbox")); err != nil {
+		return req, err
+	}
+
+	if q.Has("cursor") {
+		if req.SortBy != "" && req.SortBy != domain.SortByCreatedAt {
+			return req, fmt.Errorf("cursor pagination supports only sort_by=created_at")
+		}
+		req.CursorMode = true
+		if req.After, err = parseCursor(q.Get("cursor")); err != nil {
+			return req, err
+		}
+	}
+
+	if q.Has("near_lat") || q.Has("near_lng") || q.Has("within_km") {
+		var near domain.NearFilter
+		if near.Lat, err = parseFloat(q, "near_lat"); err != nil {
+			return req, err
+		}
+		if near.Lng, err = parseFloat(q, "near_lng"); err != nil {
+			return req, err
+		}
+		if near.WithinKM, err = parseFloat(q, "within_km"); err != nil {
+			return req, err
+		}
+		if !q.Has("near_lat") || !q.Has("near_lng") || !q.Has("within_km") {
+			return req, fmt.Errorf("near_lat, near_lng and within_km must be set together")
+		}
+		req.Near = &near
+	}
+
+	return req, nil
+}
+
+// parseListChecksRequest — фильтры истории проверок, всегда keyset-пагинация.
+func parseListChecksRequest(q url.Values) (domain.ListChecksRequest, error) {
+	req := domain.ListChecksRequest{Limit: parseInt(q.Get("limit"), 20)}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	var err error
+	if s := q.Get("user_id"); s != "" {
+		if req.UserID, err = uuid.Parse(s); err != nil {
+			return req, fmt.Errorf("invalid user_id")
+		}
+	}
+	if s := q.Get("incident_id"); s != "" {
+		if req.IncidentID, err = uuid.Parse(s); err != nil {
+			return req, fmt.Errorf("invalid incident_id")
+		}
+	}
+	if s := q.Get("had_hits"); s != "" {
+		if req.HadHits, err = strconv.ParseBool(s); err != nil {
+			return req, fmt.Errorf("had_hits must be a boolean")
+		}
+	}
+	if req.From, err = parseTime(q, "from"); err != nil {
+		return req, err
+	}
+	if req.To, err = parseTime(q, "to"); err != nil {
+		return req, err
+	}
+	if req.BBox, err = parseBBox(q.Get("bbox")); err != nil {
+		return req, err
+	}
+	if req.After, err = parseCursor(q.Get("cursor")); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func parseListDeliveriesRequest(q url.Values) (domain.ListWebhookDeliveriesRequest, error) {
+	req := domain.ListWebhookDeliveriesRequest{
+		Limit:  parseInt(q.Get("limit"), 20),
+		Status: domain.WebhookDeliveryStatus(q.Get("status")),
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	var err error
+	if s := q.Get("user_id"); s != "" {
+		if req.UserID, err = uuid.Parse(s); err != nil {
+			return req, fmt.Errorf("invalid user_id")
+		}
+	}
+	if s := q.Get("subscription_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return req, fmt.Errorf("invalid subscription_id")
+		}
+		req.SubscriptionID = &id
+	}
+	if s := q.Get("delivery_id"); s != "" {
+		if req.DeliveryID, err = uuid.Parse(s); err != nil {
+			return req, fmt.Errorf("invalid delivery_id")
+		}
+	}
+	if req.From, err = parseTime(q, "from"); err != nil {
+		return req, err
+	}
+	if req.To, err = parseTime(q, "to"); err != nil {
+		return req, err
+	}
+	if req.After, err = parseCursor(q.Get("cursor")); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func parseIncidentVisitorsRequest(q url.Values) (domain.IncidentVisitorsRequest, error) {
+	req := domain.IncidentVisitorsRequest{Limit: parseInt(q.Get("limit"), 20)}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	var err error
+	if req.From, err = parseTime(q, "from"); err != nil {
+		return req, err
+	}
+	if req.To, err = parseTime(q, "to"); err != nil {
+		return req, err
+	}
+	if req.After, err = parseCursor(q.Get("cursor")); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func parseOccupantsRequest(q url.Values) (domain.OccupantsRequest, error) {
+	req := domain.OccupantsRequest{Limit: parseInt(q.Get("limit"), 20)}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	var err error
+	if req.After, err = parseCursor(q.Get("cursor")); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func parseTimelineRequest(q url.Values) (domain.TimelineRequest, error) {
+	req := domain.TimelineRequest{Limit: parseInt(q.Get("limit"), 20)}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	var err error
+	if s := q.Get("incident_id"); s != "" {
+		if req.IncidentID, err = uuid.Parse(s); err != nil {
+			return req, fmt.Errorf("invalid incident_id")
+		}
+	}
+	if req.From, err = parseTime(q, "from"); err != nil {
+		return req, err
+	}
+	if req.To, err = parseTime(q, "to"); err != nil {
+		return req, err
+	}
+	if req.After, err = parseCursor(q.Get("cursor")); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+// parseStatsSeriesRequest: по умолчанию последние сутки по часам, top=10.
+func parseStatsSeriesRequest(q url.Values, now time.Time) (domain.StatsSeriesRequest, error) {
+	req := domain.StatsSeriesRequest{
+		Bucket: domain.StatsBucket(q.Get("bucket")),
+		Top:    parseInt(q.Get("top"), 10),
+	}
+	if req.Bucket == "" {
+		req.Bucket = domain.BucketHour
+	}
+
+	var err error
+	if req.From, err = parseTime(q, "from"); err != nil {
+		return req, err
+	}
+	if req.To, err = parseTime(q, "to"); err != nil {
+		return req, err
+	}
+	if req.To.IsZero() {
+		req.To = now
+	}
+	if req.From.IsZero() {
+		req.From = req.To.Add(-24 * time.Hour)
+	}
+
+	return req, nil
+}
+
+func parseCursor(s string) (*cursor.Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	c, err := cursor.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return c, nil
+}
+
+// parseBBox — minLng,minLat,maxLng,maxLat; пустая строка — без фильтра.
+func parseBBox(s string) (*domain.BBox, error) {
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("bbox must be minLng,minLat,maxLng,maxLat")
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, fmt.Errorf("bbox must be minLng,minLat,maxLng,maxLat")
+		}
+		v[i] = f
+	}
+	return &domain.BBox{MinLng: v[0], MinLat: v[1], MaxLng: v[2], MaxLat: v[3]}, nil
+}
+
+func parseTime(q url.Values, key string) (time.Time, error) {
+	s := q.Get(key)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be RFC3339", key)
+	}
+	return t.UTC(), nil
+}
+
+func parseFloat(q url.Values, key string) (float64, error) {
+	s := q.Get(key)
+	if s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", key)
+	}
+	return f, nil
+}
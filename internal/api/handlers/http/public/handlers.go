@@ -8,11 +8,20 @@ import (
 	"net/http"
 
 	"redCollar/internal/domain"
+	"redCollar/pkg/validator"
 )
 
+// maxBatchBodyBytes — с запасом на MaxCheckBatchPoints точек
+// или MaxRoutePositions вершин маршрута.
+const maxBatchBodyBytes = 1 << 20
+
 //go:generate mockgen -source=handlers.go -destination=mocks/mock.go
 type PublicHandler interface {
 	CheckLocation(ctx context.Context, req domain.LocationCheckRequest) (domain.LocationCheckResponse, error)
+	CheckLocationV2(ctx context.Context, req domain.LocationCheckRequest) (domain.LocationCheckV2Response, error)
+	CheckLocationBatch(ctx context.Context, req domain.LocationCheckBatchRequest) (domain.LocationCheckBatchResponse, error)
+	CheckRoute(ctx context.Context, req domain.RouteCheckRequest) (domain.RouteCheckResponse, error)
+	NearbyIncidents(ctx context.Context, req domain.NearbyRequest) (domain.NearbyResponse, error)
 }
 
 type Handler struct {
@@ -68,3 +77,154 @@ func (h *Handler) PublicLocationCheck(w http.ResponseWriter, r *http.Request) {
 	l.Info("check location success")
 	h.writeJSON(w, http.StatusOK, resp)
 }
+
+// PublicLocationCheckV2 — проверка точки с расстояниями до инцидентов и
+// зонами, к которым пользователь приближается.
+func (h *Handler) PublicLocationCheckV2(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+
+	l.Debug("PublicLocationCheckV2 called",
+		slog.String("remote", r.RemoteAddr),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+	)
+
+	var req domain.LocationCheckRequest
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&req); err != nil {
+		l.Warn("invalid JSON", slog.Any("error", err))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		l.Warn("extra data after JSON", slog.Any("error", err))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	resp, err := h.PublicHandler.CheckLocationV2(r.Context(), req)
+	if err != nil {
+		l.Error("check location failed", slog.Any("error", err))
+		h.handleError(w, err)
+		return
+	}
+
+	l.Info("check location success", slog.Int("inside", len(resp.Inside)), slog.Int("approaching", len(resp.Approaching)))
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) PublicLocationCheckBatch(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+
+	l.Debug("PublicLocationCheckBatch called",
+		slog.String("remote", r.RemoteAddr),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+	)
+
+	var req domain.LocationCheckBatchRequest
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&req); err != nil {
+		l.Warn("invalid JSON", slog.Any("error", err))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		l.Warn("extra data after JSON", slog.Any("error", err))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.PublicHandler.CheckLocationBatch(r.Context(), req)
+	if err != nil {
+		l.Error("batch check failed", slog.Any("error", err))
+		h.handleError(w, err)
+		return
+	}
+
+	l.Info("batch check success", slog.Int("points", len(req.Points)), slog.Int("accepted", resp.Accepted))
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) PublicRouteCheck(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+
+	l.Debug("PublicRouteCheck called",
+		slog.String("remote", r.RemoteAddr),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+	)
+
+	var req domain.RouteCheckRequest
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&req); err != nil {
+		l.Warn("invalid JSON", slog.Any("error", err))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		l.Warn("extra data after JSON", slog.Any("error", err))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.PublicHandler.CheckRoute(r.Context(), req)
+	if err != nil {
+		l.Warn("route check failed", slog.Any("error", err))
+		h.handleError(w, err)
+		return
+	}
+
+	l.Info("route check success", slog.Int("incidents", len(resp.Incidents)))
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) PublicNearbyIncidents(w http.ResponseWriter, r *http.Request) {
+	l := h.log(r)
+	l.Debug("PublicNearbyIncidents", slog.String("query", r.URL.RawQuery), slog.String("remote", r.RemoteAddr))
+
+	req, err := parseNearbyRequest(r.URL.Query())
+	if err != nil {
+		l.Warn("invalid query", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		l.Warn("validation failed", slog.String("error", err.Error()))
+		h.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.PublicHandler.NearbyIncidents(r.Context(), req)
+	if err != nil {
+		l.Error("nearby incidents failed", slog.Any("error", err))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, resp)
+}
@@ -12,6 +12,7 @@ import (
 	"log/slog"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 
 	"redCollar/internal/api/handlers/http/public"
 	mock_public "redCollar/internal/api/handlers/http/public/mocks"
@@ -51,8 +52,13 @@ func TestPublicLocationCheck_OK(t *testing.T) {
 		Lng:    37.61,
 	}
 	wantResp := domain.LocationCheckResponse{
-		Incidents: []string{
-			"11111111-1111-1111-1111-111111111111",
+		Incidents: []domain.IncidentSummary{
+			{
+				ID:       uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+				Title:    "Gas leak",
+				Severity: domain.SeverityHigh,
+				Category: domain.CategoryGasLeak,
+			},
 		},
 	}
 
@@ -181,3 +187,230 @@ func TestPublicLocationCheck_JSONWithExtraTrailingData_400(t *testing.T) {
 		t.Fatalf("unexpected status %d body=%s", rr.Code, rr.Body.String())
 	}
 }
+
+func TestPublicLocationCheckBatch_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock_public.NewMockPublicHandler(ctrl)
+	h := public.NewHandler(newTestLogger(), svc)
+
+	reqBody := `{"points":[{"user_id":"00000000-0000-0000-0000-000000000001","lat":55.75,"lng":37.61,"timestamp":"2025-01-01T10:00:00Z"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/location/check/batch", bytes.NewBufferString(reqBody))
+	rr := httptest.NewRecorder()
+
+	wantResp := domain.LocationCheckBatchResponse{
+		Results:  []domain.LocationCheckPointResult{{Index: 0, Incidents: []domain.IncidentSummary{}}},
+		Accepted: 1,
+	}
+
+	svc.EXPECT().
+		CheckLocationBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, got domain.LocationCheckBatchRequest) (domain.LocationCheckBatchResponse, error) {
+			if len(got.Points) != 1 || got.Points[0].Lat != 55.75 || got.Points[0].Timestamp.IsZero() {
+				t.Errorf("unexpected request: %+v", got)
+			}
+			return wantResp, nil
+		}).
+		Times(1)
+
+	h.PublicLocationCheckBatch(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	got := decodeJSON[domain.LocationCheckBatchResponse](t, rr)
+	if !reflect.DeepEqual(got, wantResp) {
+		t.Fatalf("unexpected response: got=%+v want=%+v", got, wantResp)
+	}
+}
+
+func TestPublicLocationCheckBatch_EmptyPoints_400(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock_public.NewMockPublicHandler(ctrl)
+	h := public.NewHandler(newTestLogger(), svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/location/check/batch", bytes.NewBufferString(`{"points":[]}`))
+	rr := httptest.NewRecorder()
+
+	h.PublicLocationCheckBatch(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
+
+func TestPublicRouteCheck_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock_public.NewMockPublicHandler(ctrl)
+	h := public.NewHandler(newTestLogger(), svc)
+
+	reqBody := `{"route":{"type":"LineString","coordinates":[[37.55,55.75],[37.67,55.75]]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/location/route", bytes.NewBufferString(reqBody))
+	rr := httptest.NewRecorder()
+
+	wantReq := domain.RouteCheckRequest{
+		Route: &domain.LineString{Coordinates: []domain.Position{{37.55, 55.75}, {37.67, 55.75}}},
+	}
+	wantResp := domain.RouteCheckResponse{
+		LengthKM: 7.5,
+		Incidents: []domain.RouteIncident{{
+			Incident:      domain.IncidentSummary{ID: uuid.MustParse("11111111-1111-1111-1111-111111111111"), Title: "Пожар"},
+			Intersections: []domain.RouteInterval{{StartKM: 2.75, EndKM: 4.75}},
+		}},
+	}
+
+	svc.EXPECT().
+		CheckRoute(gomock.Any(), wantReq).
+		Return(wantResp, nil).
+		Times(1)
+
+	h.PublicRouteCheck(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	got := decodeJSON[domain.RouteCheckResponse](t, rr)
+	if !reflect.DeepEqual(got, wantResp) {
+		t.Fatalf("unexpected response: got=%+v want=%+v", got, wantResp)
+	}
+}
+
+func TestPublicRouteCheck_WrongGeometryType_400(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock_public.NewMockPublicHandler(ctrl)
+	h := public.NewHandler(newTestLogger(), svc)
+
+	reqBody := `{"route":{"type":"Point","coordinates":[37.55,55.75]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/location/route", bytes.NewBufferString(reqBody))
+	rr := httptest.NewRecorder()
+
+	h.PublicRouteCheck(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d body=%s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
+
+func TestPublicNearbyIncidents_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock_public.NewMockPublicHandler(ctrl)
+	h := public.NewHandler(newTestLogger(), svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/incidents/nearby?lat=55.75&lng=37.61&radius_km=5&limit=10", nil)
+	rr := httptest.NewRecorder()
+
+	wantResp := domain.NearbyResponse{
+		Incidents: []domain.NearbyIncident{{
+			ID:         uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			Title:      "Gas leak",
+			Severity:   domain.SeverityHigh,
+			Category:   domain.CategoryGasLeak,
+			Lat:        55.76,
+			Lng:        37.6,
+			RadiusKM:   1,
+			DistanceKM: 1.3,
+		}},
+		RadiusKM: 5,
+		Limit:    10,
+	}
+
+	svc.EXPECT().
+		NearbyIncidents(gomock.Any(), domain.NearbyRequest{Lat: 55.75, Lng: 37.61, RadiusKM: 5, Limit: 10}).
+		Return(wantResp, nil).
+		Times(1)
+
+	h.PublicNearbyIncidents(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	got := decodeJSON[domain.NearbyResponse](t, rr)
+	if !reflect.DeepEqual(got, wantResp) {
+		t.Fatalf("unexpected response: got=%+v want=%+v", got, wantResp)
+	}
+}
+
+func TestPublicNearbyIncidents_BadQuery_400(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock_public.NewMockPublicHandler(ctrl)
+	h := public.NewHandler(newTestLogger(), svc)
+
+	for _, q := range []string{"lng=37.61", "lat=abc&lng=37.61", "lat=55.75&lng=37.61&radius_km=500", "lat=95&lng=37.61"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/incidents/nearby?"+q, nil)
+		rr := httptest.NewRecorder()
+
+		h.PublicNearbyIncidents(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected %d got %d body=%s", q, http.StatusBadRequest, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestPublicLocationCheckV2_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock_public.NewMockPublicHandler(ctrl)
+	h := public.NewHandler(newTestLogger(), svc)
+
+	reqBody := `{"user_id":"00000000-0000-0000-0000-000000000001","lat":55.75,"lng":37.61}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/location/check", bytes.NewBufferString(reqBody))
+	rr := httptest.NewRecorder()
+
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	wantResp := domain.LocationCheckV2Response{
+		Inside: []domain.IncidentProximity{},
+		Approaching: []domain.IncidentProximity{{
+			Incident:         domain.IncidentSummary{ID: id, Title: "Gas leak"},
+			DistanceKM:       1.3,
+			DistanceToEdgeKM: 0.3,
+			InBuffer:         true,
+		}},
+		BufferKM: 0.5,
+	}
+
+	svc.EXPECT().
+		CheckLocationV2(gomock.Any(), domain.LocationCheckRequest{UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61}).
+		Return(wantResp, nil).
+		Times(1)
+
+	h.PublicLocationCheckV2(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d body=%s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	got := decodeJSON[domain.LocationCheckV2Response](t, rr)
+	if !reflect.DeepEqual(got, wantResp) {
+		t.Fatalf("unexpected response: got=%+v want=%+v", got, wantResp)
+	}
+}
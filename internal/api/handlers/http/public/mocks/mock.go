@@ -49,3 +49,63 @@ func (mr *MockPublicHandlerMockRecorder) CheckLocation(ctx, req interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLocation", reflect.TypeOf((*MockPublicHandler)(nil).CheckLocation), ctx, req)
 }
+
+// CheckLocationBatch mocks base method.
+func (m *MockPublicHandler) CheckLocationBatch(ctx context.Context, req domain.LocationCheckBatchRequest) (domain.LocationCheckBatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLocationBatch", ctx, req)
+	ret0, _ := ret[0].(domain.LocationCheckBatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLocationBatch indicates an expected call of CheckLocationBatch.
+func (mr *MockPublicHandlerMockRecorder) CheckLocationBatch(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLocationBatch", reflect.TypeOf((*MockPublicHandler)(nil).CheckLocationBatch), ctx, req)
+}
+
+// CheckLocationV2 mocks base method.
+func (m *MockPublicHandler) CheckLocationV2(ctx context.Context, req domain.LocationCheckRequest) (domain.LocationCheckV2Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLocationV2", ctx, req)
+	ret0, _ := ret[0].(domain.LocationCheckV2Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLocationV2 indicates an expected call of CheckLocationV2.
+func (mr *MockPublicHandlerMockRecorder) CheckLocationV2(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLocationV2", reflect.TypeOf((*MockPublicHandler)(nil).CheckLocationV2), ctx, req)
+}
+
+// CheckRoute mocks base method.
+func (m *MockPublicHandler) CheckRoute(ctx context.Context, req domain.RouteCheckRequest) (domain.RouteCheckResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckRoute", ctx, req)
+	ret0, _ := ret[0].(domain.RouteCheckResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckRoute indicates an expected call of CheckRoute.
+func (mr *MockPublicHandlerMockRecorder) CheckRoute(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRoute", reflect.TypeOf((*MockPublicHandler)(nil).CheckRoute), ctx, req)
+}
+
+// NearbyIncidents mocks base method.
+func (m *MockPublicHandler) NearbyIncidents(ctx context.Context, req domain.NearbyRequest) (domain.NearbyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NearbyIncidents", ctx, req)
+	ret0, _ := ret[0].(domain.NearbyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NearbyIncidents indicates an expected call of NearbyIncidents.
+func (mr *MockPublicHandlerMockRecorder) NearbyIncidents(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NearbyIncidents", reflect.TypeOf((*MockPublicHandler)(nil).NearbyIncidents), ctx, req)
+}
@@ -3,8 +3,11 @@ package public
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"redCollar/internal/domain"
 	"redCollar/pkg/e"
 	"strconv"
 
@@ -16,7 +19,7 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, e.ErrNotFound):
 		status = http.StatusNotFound
-	case errors.Is(err, e.ErrInvalidInput):
+	case errors.Is(err, e.ErrInvalidInput), errors.Is(err, e.ErrInvalidCoordinates):
 		status = http.StatusBadRequest
 	case errors.Is(err, e.ErrConflict):
 		status = http.StatusConflict
@@ -37,6 +40,34 @@ func parseInt(s string, def int) int {
 	return i
 }
 
+func parseNearbyRequest(q url.Values) (domain.NearbyRequest, error) {
+	req := domain.NearbyRequest{Limit: parseInt(q.Get("limit"), 20)}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	if q.Get("lat") == "" || q.Get("lng") == "" {
+		return req, fmt.Errorf("lat and lng are required")
+	}
+	var err error
+	if req.Lat, err = strconv.ParseFloat(q.Get("lat"), 64); err != nil {
+		return req, fmt.Errorf("invalid lat")
+	}
+	if req.Lng, err = strconv.ParseFloat(q.Get("lng"), 64); err != nil {
+		return req, fmt.Errorf("invalid lng")
+	}
+	if s := q.Get("radius_km"); s != "" {
+		if req.RadiusKM, err = strconv.ParseFloat(s, 64); err != nil {
+			return req, fmt.Errorf("invalid radius_km")
+		}
+	}
+
+	return req, nil
+}
+
 func (h *Handler) log(r *http.Request) *slog.Logger {
 	reqID := chimw.GetReqID(r.Context())
 	if reqID == "" {
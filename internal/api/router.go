@@ -30,7 +30,7 @@ type Server struct {
 }
 
 func NewServer(cfg *config.Config, logger *slog.Logger, svc *service.Service) *Server {
-	adminHandler := admin.NewHandler(logger, svc.AdminIncidentService, svc.StatsService, svc.PublicIncidentService)
+	adminHandler := admin.NewHandler(logger, svc.AdminIncidentService, svc.StatsService, svc.PublicIncidentService, svc.CheckHistoryService, svc.DeadLetterService, svc.SubscriptionService, svc.DeliveryLogService)
 	publicHandler := public.NewHandler(logger, svc.PublicIncidentService)
 	systemHandler := system.NewHandler(logger)
 	wd, _ := os.Getwd()
@@ -69,6 +69,10 @@ func InitRouter(cfg *config.Config, adminHandler *admin.Handler, publicHandler *
 	r.Get("/admin", func(w http.ResponseWriter, r *http.Request) {
 		renderer.Render(w, "admin.html", nil)
 	})
+	// Проверки v1 и v2 делят один лимит: иначе клиент удваивает квоту,
+	// чередуя версии.
+	locationLimit := middleware.Limit(10, 20, 5*time.Minute, logger)
+
 	r.Route("/api/v1", func(api chi.Router) {
 
 		api.Route("/admin", func(ar chi.Router) {
@@ -76,6 +80,28 @@ func InitRouter(cfg *config.Config, adminHandler *admin.Handler, publicHandler *
 			ar.Use(middleware.Limit(2, 5, 10*time.Minute, logger))
 
 			ar.Get("/stats", adminHandler.AdminStats)
+			ar.Get("/stats/series", adminHandler.AdminStatsSeries)
+			ar.Get("/checks", adminHandler.AdminCheckList)
+			ar.Get("/users/{id}/timeline", adminHandler.AdminUserTimeline)
+
+			ar.Route("/webhooks/subscriptions", func(sr chi.Router) {
+				sr.Post("/", adminHandler.AdminSubscriptionCreate)
+				sr.Get("/", adminHandler.AdminSubscriptionList)
+				sr.Get("/{id}", adminHandler.AdminSubscriptionGet)
+				sr.Put("/{id}", adminHandler.AdminSubscriptionUpdate)
+				sr.Delete("/{id}", adminHandler.AdminSubscriptionDelete)
+				sr.Post("/{id}/rotate-secret", adminHandler.AdminSubscriptionRotateSecret)
+			})
+
+			ar.Route("/webhooks/dead-letters", func(dr chi.Router) {
+				dr.Get("/", adminHandler.AdminDeadLetterList)
+				dr.Delete("/", adminHandler.AdminDeadLetterPurge)
+				dr.Get("/{id}", adminHandler.AdminDeadLetterGet)
+				dr.Delete("/{id}", adminHandler.AdminDeadLetterDelete)
+				dr.Post("/{id}/replay", adminHandler.AdminDeadLetterReplay)
+			})
+
+			ar.Get("/webhooks/deliveries", adminHandler.AdminDeliveryList)
 
 			ar.Route("/incidents", func(ir chi.Router) {
 				ir.Post("/", adminHandler.AdminIncidentCreate)
@@ -85,18 +111,36 @@ func InitRouter(cfg *config.Config, adminHandler *admin.Handler, publicHandler *
 					rr.Get("/", adminHandler.AdminIncidentGet)
 					rr.Put("/", adminHandler.AdminIncidentUpdate)
 					rr.Delete("/", adminHandler.AdminIncidentDelete)
+					rr.Get("/checks", adminHandler.AdminIncidentChecks)
+					rr.Get("/stats", adminHandler.AdminIncidentStats)
+					rr.Get("/occupants", adminHandler.AdminIncidentOccupants)
 				})
 			})
 		})
 
 		api.Route("/location", func(pr chi.Router) {
-			pr.Use(middleware.Limit(10, 20, 5*time.Minute, logger))
+			pr.Use(locationLimit)
 			pr.Post("/check", publicHandler.PublicLocationCheck)
+			pr.Post("/check/batch", publicHandler.PublicLocationCheckBatch)
+			pr.Post("/route", publicHandler.PublicRouteCheck)
+		})
+
+		// Поиск идёт в Postgres, а не в кэш, поэтому лимит строже, чем у проверок.
+		api.Route("/incidents", func(nr chi.Router) {
+			nr.Use(middleware.Limit(5, 10, 5*time.Minute, logger))
+			nr.Get("/nearby", publicHandler.PublicNearbyIncidents)
 		})
 
 		api.Get("/health", systemHandler.SystemHealth)
 	})
 
+	r.Route("/api/v2", func(api chi.Router) {
+		api.Route("/location", func(pr chi.Router) {
+			pr.Use(locationLimit)
+			pr.Post("/check", publicHandler.PublicLocationCheckV2)
+		})
+	})
+
 	return r
 }
 func (s *Server) Run(ctx context.Context) error {
@@ -0,0 +1,77 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"redCollar/internal/domain"
+)
+
+// NDJSON пишет пачки удаляемых проверок в gzip-файлы по одной JSON-строке
+// на проверку. Файл сначала пишется во временный и переименовывается после
+// fsync, так что в каталоге не бывает обрезанных архивов.
+type NDJSON struct {
+	dir string
+	now func() time.Time
+}
+
+func NewNDJSON(dir string) (*NDJSON, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("archive.NewNDJSON: %w", err)
+	}
+	return &NDJSON{dir: dir, now: time.Now}, nil
+}
+
+// WriteChecks возвращает путь созданного файла.
+func (a *NDJSON) WriteChecks(checks []*domain.LocationCheck) (string, error) {
+	const op = "archive.NDJSON.WriteChecks"
+
+	if len(checks) == 0 {
+		return "", nil
+	}
+
+	name := fmt.Sprintf("location_checks_%s_%s_%d.ndjson.gz",
+		checks[0].CheckedAt.UTC().Format("20060102T150405Z"),
+		checks[len(checks)-1].CheckedAt.UTC().Format("20060102T150405Z"),
+		a.now().UnixNano(),
+	)
+	path := filepath.Join(a.dir, name)
+
+	tmp, err := os.CreateTemp(a.dir, ".tmp-"+name+"-*")
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := gzip.NewWriter(tmp)
+	bw := bufio.NewWriter(zw)
+	enc := json.NewEncoder(bw)
+	for _, c := range checks {
+		if err := enc.Encode(c); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,65 @@
+package archive_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"redCollar/internal/archive"
+	"redCollar/internal/domain"
+)
+
+func TestNDJSON_WriteChecks(t *testing.T) {
+	dir := t.TempDir()
+
+	a, err := archive.NewNDJSON(dir)
+	if err != nil {
+		t.Fatalf("NewNDJSON: %v", err)
+	}
+
+	at := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	checks := []*domain.LocationCheck{
+		{ID: uuid.New(), UserID: uuid.New(), Lat: 55.75, Lng: 37.61, IncidentIDs: []uuid.UUID{uuid.New()}, CheckedAt: at},
+		{ID: uuid.New(), UserID: uuid.New(), Lat: 55.76, Lng: 37.62, IncidentIDs: []uuid.UUID{}, CheckedAt: at.Add(time.Minute)},
+	}
+
+	path, err := a.WriteChecks(checks)
+	if err != nil {
+		t.Fatalf("WriteChecks: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || filepath.Join(dir, entries[0].Name()) != path {
+		t.Fatalf("expected only %s in dir, got %v", path, entries)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+
+	var got []domain.LocationCheck
+	sc := bufio.NewScanner(zr)
+	for sc.Scan() {
+		var c domain.LocationCheck
+		if err := json.Unmarshal(sc.Bytes(), &c); err != nil {
+			t.Fatalf("line %d: %v", len(got)+1, err)
+		}
+		got = append(got, c)
+	}
+	if len(got) != 2 || got[0].ID != checks[0].ID || got[1].Lat != 55.76 {
+		t.Fatalf("unexpected archive content: %+v", got)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"os"
 	"redCollar/internal/api"
+	"redCollar/internal/archive"
 	"redCollar/internal/config"
+	"redCollar/internal/domain"
 	redis2 "redCollar/internal/redis"
 	"redCollar/internal/service"
 	"redCollar/internal/storage/postgres"
@@ -16,13 +18,19 @@ import (
 )
 
 type Components struct {
-	logger          *slog.Logger
-	HttpServer      *api.Server
-	Postgres        *postgres.Postgres
-	Redis           *redis2.Redis
-	WebhookQ        *redis2.WebhookQueue
-	LocationChecker *workers.LocationChecker
-	WebhookSender   *service.WebhookSender // ← ДОБАВИЛИ!
+	logger            *slog.Logger
+	HttpServer        *api.Server
+	Postgres          *postgres.Postgres
+	Redis             *redis2.Redis
+	WebhookQ          *redis2.WebhookQueue
+	LocationChecker   *workers.LocationChecker
+	WebhookSender     *service.WebhookSender // ← ДОБАВИЛИ!
+	IncidentScheduler *workers.IncidentScheduler
+	StatsRollup       *workers.StatsRollup
+	ChecksRetention   *workers.ChecksRetention
+	DeliveryRetention *workers.DeliveryLogRetention
+	WebhookRetries    *workers.WebhookRetryScheduler
+	OutboxRelay       *workers.OutboxRelay
 }
 
 func InitComponents(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Components, error) {
@@ -43,32 +51,68 @@ func InitComponents(ctx context.Context, cfg *config.Config, logger *slog.Logger
 	}
 
 	webhookQueue := redis2.NewWebhookQueue(redisClient.Client, "webhooks:queue")
-	webhookSender := service.NewWebhookSender(logger, cfg.Webhook, webhookQueue)
+	webhookSender := service.NewWebhookSender(logger, cfg.Webhook, webhookQueue, storage.Subscriptions(), storage.Deliveries())
+
+	webhookRetries := workers.NewWebhookRetryScheduler(webhookQueue, logger, cfg.Webhook.RetryPollInterval, 100, cfg.Webhook.VisibilityTimeout)
+	outboxRelay := workers.NewOutboxRelay(storage.Outbox(), webhookQueue, logger,
+		cfg.Outbox.RelayInterval, cfg.Outbox.RelayBatch, cfg.Outbox.Retention)
 
 	logger.Info("🔥 Starting webhookSender",
 		slog.String("url", cfg.Webhook.URL),
 		slog.String("queue", "webhooks:queue")) // ← ИСПРАВИЛИ!
 
 	cache := redis2.NewIncidentCache(redisClient)
+	alerts := redis2.NewAlertTracker(redisClient.Client, "alerts:user", domain.AlertPolicy{
+		Window:     cfg.Alerts.SuppressWindow,
+		Renotify:   cfg.Alerts.Renotify,
+		NotifyExit: cfg.Alerts.NotifyExit,
+	})
 	adminSvc := service.NewAdminIncidentService(storage.AdminIncidents(), cache)
 	statsRepo := storage.Stats()
-	publicSvc := service.NewPublicIncidentService(cache, statsRepo, webhookQueue, logger, 1.0)
+	publicSvc := service.NewPublicIncidentService(cache, statsRepo, storage.PublicIncidents(), alerts, logger, 1.0, cfg.Alerts.WarningBufferKM)
 	statsSvc := service.NewStatsService(storage.Stats())
 	locationChecker := workers.NewLocationChecker(cache, 10)
+	incidentScheduler := workers.NewIncidentScheduler(adminSvc, logger, time.Minute)
+	statsRollup := workers.NewStatsRollup(storage.Stats(), logger, cfg.Stats.RollupInterval, cfg.Stats.RollupLag)
+
+	checksSvc := service.NewCheckHistoryService(storage.Checks())
+
+	var checkArchiver workers.CheckArchiver
+	if cfg.Checks.ArchiveDir != "" {
+		a, err := archive.NewNDJSON(cfg.Checks.ArchiveDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init checks archive: %w", err)
+		}
+		checkArchiver = a
+	}
+	checksRetention := workers.NewChecksRetention(storage.Checks(), checkArchiver, logger,
+		cfg.Checks.Retention, cfg.Checks.RetentionInterval, cfg.Checks.RetentionBatch)
+	deliveryRetention := workers.NewDeliveryLogRetention(storage.Deliveries(), logger, cfg.Webhook.DeliveryLogRetention)
+
+	deadLetterSvc := service.NewDeadLetterService(webhookQueue)
+	subscriptionSvc := service.NewWebhookSubscriptionService(storage.Subscriptions(), cfg.Webhook.SecretRotationGrace)
+
+	deliveryLogSvc := service.NewWebhookDeliveryLogService(storage.Deliveries())
 
-	srv := service.NewService(adminSvc, publicSvc, statsSvc)
+	srv := service.NewService(adminSvc, publicSvc, statsSvc, checksSvc, deadLetterSvc, subscriptionSvc, deliveryLogSvc)
 
 	httpServer := api.NewServer(cfg, logger, srv)
 	logger.Info("Initialized server")
 
 	return &Components{
-		logger:          logger,
-		HttpServer:      httpServer,
-		Postgres:        storage,
-		Redis:           redisClient,
-		WebhookQ:        webhookQueue,
-		LocationChecker: locationChecker,
-		WebhookSender:   webhookSender,
+		logger:            logger,
+		HttpServer:        httpServer,
+		Postgres:          storage,
+		Redis:             redisClient,
+		WebhookQ:          webhookQueue,
+		LocationChecker:   locationChecker,
+		WebhookSender:     webhookSender,
+		IncidentScheduler: incidentScheduler,
+		StatsRollup:       statsRollup,
+		ChecksRetention:   checksRetention,
+		DeliveryRetention: deliveryRetention,
+		WebhookRetries:    webhookRetries,
+		OutboxRelay:       outboxRelay,
 	}, nil
 }
 
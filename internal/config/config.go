@@ -3,10 +3,12 @@ package config
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -19,6 +21,10 @@ type Config struct {
 	Redis    RedisConfig    `json:"redis"`
 	APIKey   string         `json:"api_key,omitempty"`
 	Webhook  WebhookConfig  `json:"webhook"`
+	Stats    StatsConfig    `json:"stats"`
+	Checks   ChecksConfig   `json:"checks"`
+	Outbox   OutboxConfig   `json:"outbox"`
+	Alerts   AlertsConfig   `json:"alerts"`
 }
 
 type HttpConfig struct {
@@ -50,9 +56,73 @@ type RedisConfig struct {
 type WebhookConfig struct {
 	URL      string `json:"url"`
 	Disabled bool   `json:"disabled"`
+	// MaxAttempts — сколько раз пытаемся доставить, прежде чем отправить в dead letters.
+	MaxAttempts int `json:"max_attempts"`
+	// RetryBaseDelay/RetryMaxDelay — экспоненциальная задержка между попытками.
+	RetryBaseDelay time.Duration `json:"retry_base_delay"`
+	RetryMaxDelay  time.Duration `json:"retry_max_delay"`
+	// MaxAge — сколько доставка может ждать успеха с момента постановки; 0 — без ограничения.
+	MaxAge time.Duration `json:"max_age"`
+	// RetryPollInterval — как часто созревшие повторы возвращаются в очередь.
+	RetryPollInterval time.Duration `json:"retry_poll_interval"`
+	// Workers — сколько доставок идёт параллельно, PerHostLimit — из них к одному хосту.
+	Workers      int `json:"workers"`
+	PerHostLimit int `json:"per_host_limit"`
+	// После BreakerThreshold неудач подряд хост получателя ставится на паузу BreakerCooldown.
+	BreakerThreshold int           `json:"breaker_threshold"`
+	BreakerCooldown  time.Duration `json:"breaker_cooldown"`
+	// DrainTimeout — сколько при остановке ждём начатые отправки.
+	DrainTimeout time.Duration `json:"drain_timeout"`
+	// VisibilityTimeout — через сколько взятое, но не завершённое сообщение
+	// считается брошенным упавшим отправителем и возвращается в очередь.
+	VisibilityTimeout time.Duration `json:"visibility_timeout"`
+	// Secret/PreviousSecret подписывают доставки на WEBHOOK_URL; на время
+	// ротации заданы оба.
+	Secret         string `json:"-"`
+	PreviousSecret string `json:"-"`
+	// SecretRotationGrace — сколько старый секрет подписки действует после ротации.
+	SecretRotationGrace time.Duration `json:"secret_rotation_grace"`
+	// DeliveryLogRetention — сколько хранится журнал попыток webhook_deliveries; 0 — не удалять.
+	DeliveryLogRetention time.Duration `json:"delivery_log_retention"`
+}
+
+// StatsConfig — свёртка location_checks в агрегаты. Lag оставляет
+// последние минуты в сырых данных, чтобы не свернуть недописанную минуту.
+type StatsConfig struct {
+	RollupInterval time.Duration `json:"rollup_interval"`
+	RollupLag      time.Duration `json:"rollup_lag"`
+}
+
+// ChecksConfig — хранение location_checks. Retention=0 отключает очистку,
+// пустой ArchiveDir — удаление без выгрузки.
+type ChecksConfig struct {
+	Retention         time.Duration `json:"retention"`
+	RetentionInterval time.Duration `json:"retention_interval"`
+	RetentionBatch    int           `json:"retention_batch"`
+	ArchiveDir        string        `json:"archive_dir"`
+}
+
+// OutboxConfig — пересылка событий из outbox в очередь вебхуков.
+// Retention — сколько хранятся уже отправленные события; 0 — не удалять.
+type OutboxConfig struct {
+	RelayInterval time.Duration `json:"relay_interval"`
+	RelayBatch    int           `json:"relay_batch"`
+	Retention     time.Duration `json:"retention"`
+}
+
+// AlertsConfig — дедупликация алертов по паре (пользователь, инцидент).
+// SuppressWindow=0 — алерт на каждое попадание, как раньше. WarningBufferKM —
+// полоса снаружи зоны, в которой v2-проверка считает, что пользователь
+// приближается; 0 — отключено.
+type AlertsConfig struct {
+	SuppressWindow  time.Duration `json:"suppress_window"`
+	Renotify        bool          `json:"renotify"`
+	NotifyExit      bool          `json:"notify_exit"`
+	WarningBufferKM float64       `json:"warning_buffer_km"`
 }
 
 func Load(ctx context.Context) (*Config, error) {
+	envErrs = nil
 
 	stdLogger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
@@ -86,11 +156,58 @@ func Load(ctx context.Context) (*Config, error) {
 		},
 		APIKey: getEnv("API_KEY", "super-secret-key"),
 		Webhook: WebhookConfig{
-			URL:      getEnv("WEBHOOK_URL", "https://webhook.site/5fc9c082-7cf6-47c7-94b5-be7d570346d1"),
-			Disabled: getEnvBool("WEBHOOK_DISABLED", false),
+			URL:         getEnv("WEBHOOK_URL", "https://webhook.site/5fc9c082-7cf6-47c7-94b5-be7d570346d1"),
+			Disabled:    getEnvBool("WEBHOOK_DISABLED", false),
+			MaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 3),
+
+			RetryBaseDelay:    getEnvDuration("WEBHOOK_RETRY_BASE_DELAY", 5*time.Second),
+			RetryMaxDelay:     getEnvDuration("WEBHOOK_RETRY_MAX_DELAY", 10*time.Minute),
+			MaxAge:            getEnvDuration("WEBHOOK_MAX_AGE", 24*time.Hour),
+			RetryPollInterval: getEnvDuration("WEBHOOK_RETRY_POLL_INTERVAL", time.Second),
+
+			Workers:          getEnvInt("WEBHOOK_WORKERS", 8),
+			PerHostLimit:     getEnvInt("WEBHOOK_PER_HOST_LIMIT", 4),
+			BreakerThreshold: getEnvInt("WEBHOOK_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:  getEnvDuration("WEBHOOK_BREAKER_COOLDOWN", 30*time.Second),
+			DrainTimeout:     getEnvDuration("WEBHOOK_DRAIN_TIMEOUT", 10*time.Second),
+
+			VisibilityTimeout: getEnvDuration("WEBHOOK_VISIBILITY_TIMEOUT", 5*time.Minute),
+
+			Secret:              getEnv("WEBHOOK_SECRET", ""),
+			PreviousSecret:      getEnv("WEBHOOK_SECRET_PREVIOUS", ""),
+			SecretRotationGrace: getEnvDuration("WEBHOOK_SECRET_ROTATION_GRACE", 24*time.Hour),
+
+			DeliveryLogRetention: getEnvDuration("WEBHOOK_DELIVERY_LOG_RETENTION", 30*24*time.Hour),
+		},
+		Stats: StatsConfig{
+			RollupInterval: getEnvDuration("STATS_ROLLUP_INTERVAL", time.Minute),
+			RollupLag:      getEnvDuration("STATS_ROLLUP_LAG", 2*time.Minute),
+		},
+		Checks: ChecksConfig{
+			Retention:         getEnvDuration("LOCATION_CHECKS_RETENTION", 30*24*time.Hour),
+			RetentionInterval: getEnvDuration("LOCATION_CHECKS_RETENTION_INTERVAL", time.Hour),
+			RetentionBatch:    getEnvInt("LOCATION_CHECKS_RETENTION_BATCH", 5000),
+			ArchiveDir:        getEnv("LOCATION_CHECKS_ARCHIVE_DIR", ""),
+		},
+		Outbox: OutboxConfig{
+			RelayInterval: getEnvDuration("OUTBOX_RELAY_INTERVAL", time.Second),
+			RelayBatch:    getEnvInt("OUTBOX_RELAY_BATCH", 100),
+			Retention:     getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+		},
+		Alerts: AlertsConfig{
+			SuppressWindow: getEnvDuration("ALERT_SUPPRESS_WINDOW", 30*time.Minute),
+			Renotify:       getEnvBool("ALERT_RENOTIFY", false),
+			NotifyExit:     getEnvBool("ALERT_NOTIFY_EXIT", false),
+
+			WarningBufferKM: getEnvFloat("ALERT_WARNING_BUFFER_KM", 0.5),
 		},
 	}
 
+	// Опечатка в сроке хранения не должна молча превращаться в значение по умолчанию.
+	if err := errors.Join(envErrs...); err != nil {
+		return nil, err
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
@@ -138,15 +255,52 @@ func getEnvInt(key string, def int) int {
 	return def
 }
 
+func getEnvFloat(key string, def float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// envErrs — длительности из окружения, которые не удалось разобрать;
+// Load с ними не стартует.
+var envErrs []error
+
 func getEnvDuration(key string, def time.Duration) time.Duration {
 	if v := os.Getenv(key); v != "" {
-		if d, err := time.ParseDuration(v); err == nil {
-			return d
+		d, err := parseDuration(v)
+		if err != nil {
+			envErrs = append(envErrs, fmt.Errorf("%s: %w", key, err))
+			return def
 		}
+		return d
 	}
 	return def
 }
 
+// parseDuration — time.ParseDuration плюс ведущие дни: "30d", "1d12h".
+func parseDuration(v string) (time.Duration, error) {
+	n, rest, ok := strings.Cut(v, "d")
+	if !ok {
+		return time.ParseDuration(v)
+	}
+	days, err := strconv.Atoi(n)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", v)
+	}
+	d := time.Duration(days) * 24 * time.Hour
+	if rest != "" {
+		r, err := time.ParseDuration(rest)
+		if err != nil || strings.HasPrefix(rest, "-") || strings.HasPrefix(rest, "+") {
+			return 0, fmt.Errorf("invalid duration %q", v)
+		}
+		d += r
+	}
+	return d, nil
+}
+
 func getEnvBool(key string, def bool) bool {
 	if v := os.Getenv(key); v != "" {
 		if b, err := strconv.ParseBool(v); err == nil {
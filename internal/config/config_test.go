@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		err  bool
+	}{
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: "1d12h", want: 36 * time.Hour},
+		{in: "1d30m", want: 24*time.Hour + 30*time.Minute},
+		{in: "90m", want: 90 * time.Minute},
+		{in: "0", want: 0},
+		{in: "d", err: true},
+		{in: "1.5d", err: true},
+		{in: "1d12", err: true},
+		{in: "1d-1h", err: true},
+		{in: "30 days", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDuration(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("%q: expected error, got %v", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("%q: expected %v, got %v (%v)", tt.in, tt.want, got, err)
+		}
+	}
+}
+
+func TestGetEnvDuration_InvalidValueRecorded(t *testing.T) {
+	envErrs = nil
+	t.Setenv("TEST_RETENTION", "1d12")
+
+	if d := getEnvDuration("TEST_RETENTION", time.Hour); d != time.Hour {
+		t.Fatalf("expected default, got %v", d)
+	}
+	if len(envErrs) != 1 {
+		t.Fatalf("expected the invalid value to be recorded, got %v", envErrs)
+	}
+	envErrs = nil
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"redCollar/pkg/e"
+)
+
+type GeometryType string
+
+const (
+	GeometryPolygon      GeometryType = "Polygon"
+	GeometryMultiPolygon GeometryType = "MultiPolygon"
+)
+
+// Position — точка GeoJSON в порядке [lng, lat].
+type Position [2]float64
+
+type Ring []Position
+
+type Polygon []Ring
+
+// Geometry — GeoJSON Polygon/MultiPolygon. Polygon хранится как MultiPolygon
+// из одного элемента, чтобы проверка точки не зависела от типа.
+type Geometry struct {
+	Type     GeometryType
+	Polygons []Polygon
+}
+
+type geoJSON struct {
+	Type        GeometryType    `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	var coords any
+	switch g.Type {
+	case GeometryPolygon:
+		if len(g.Polygons) != 1 {
+			return nil, fmt.Errorf("%w: polygon must have exactly one part", e.ErrInvalidInput)
+		}
+		coords = g.Polygons[0]
+	case GeometryMultiPolygon:
+		coords = g.Polygons
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %q", e.ErrInvalidInput, g.Type)
+	}
+
+	raw, err := json.Marshal(coords)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(geoJSON{Type: g.Type, Coordinates: raw})
+}
+
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	var raw geoJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch raw.Type {
+	case GeometryPolygon:
+		var p Polygon
+		if err := json.Unmarshal(raw.Coordinates, &p); err != nil {
+			return fmt.Errorf("%w: %v", e.ErrInvalidInput, err)
+		}
+		g.Polygons = []Polygon{p}
+	case GeometryMultiPolygon:
+		var mp []Polygon
+		if err := json.Unmarshal(raw.Coordinates, &mp); err != nil {
+			return fmt.Errorf("%w: %v", e.ErrInvalidInput, err)
+		}
+		g.Polygons = mp
+	default:
+		return fmt.Errorf("%w: unsupported type %q", e.ErrInvalidInput, raw.Type)
+	}
+	g.Type = raw.Type
+
+	return nil
+}
+
+func (g *Geometry) Validate() error {
+	if g == nil || len(g.Polygons) == 0 {
+		return fmt.Errorf("%w: empty geometry", e.ErrInvalidInput)
+	}
+	if g.Type == GeometryPolygon && len(g.Polygons) != 1 {
+		return fmt.Errorf("%w: polygon must have exactly one part", e.ErrInvalidInput)
+	}
+
+	for _, poly := range g.Polygons {
+		if len(poly) == 0 {
+			return fmt.Errorf("%w: polygon without rings", e.ErrInvalidInput)
+		}
+		for _, ring := range poly {
+			if len(ring) < 4 {
+				return fmt.Errorf("%w: ring must have at least 4 positions", e.ErrInvalidInput)
+			}
+			if ring[0] != ring[len(ring)-1] {
+				return fmt.Errorf("%w: ring is not closed", e.ErrInvalidInput)
+			}
+			for _, p := range ring {
+				if p[0] < -180 || p[0] > 180 || p[1] < -90 || p[1] > 90 {
+					return fmt.Errorf("%w: position out of range", e.ErrInvalidInput)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Contains проверяет попадание точки в геометрию. Первое кольцо полигона —
+// внешняя граница, остальные — дыры.
+func (g *Geometry) Contains(lat, lng float64) bool {
+	if g == nil {
+		return false
+	}
+	for _, poly := range g.Polygons {
+		if len(poly) == 0 || !ringContains(poly[0], lat, lng) {
+			continue
+		}
+		inHole := false
+		for _, hole := range poly[1:] {
+			if ringContains(hole, lat, lng) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains — классический ray casting (even-odd rule).
+func ringContains(ring Ring, lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,124 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/e"
+)
+
+func mustGeometry(t *testing.T, raw string) *domain.Geometry {
+	t.Helper()
+	var g domain.Geometry
+	if err := json.Unmarshal([]byte(raw), &g); err != nil {
+		t.Fatalf("unmarshal geometry: %v", err)
+	}
+	return &g
+}
+
+const squareWithHole = `{
+	"type": "Polygon",
+	"coordinates": [
+		[[37.0, 55.0], [38.0, 55.0], [38.0, 56.0], [37.0, 56.0], [37.0, 55.0]],
+		[[37.4, 55.4], [37.6, 55.4], [37.6, 55.6], [37.4, 55.6], [37.4, 55.4]]
+	]
+}`
+
+func TestGeometry_Contains_PolygonWithHole(t *testing.T) {
+	t.Parallel()
+
+	g := mustGeometry(t, squareWithHole)
+	if err := g.Validate(); err != nil {
+		t.Fatalf("unexpected validate err: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		lat, lng float64
+		want     bool
+	}{
+		{"inside", 55.2, 37.2, true},
+		{"in_hole", 55.5, 37.5, false},
+		{"outside", 54.9, 37.5, false},
+		{"outside_lng", 55.5, 38.1, false},
+	}
+
+	for _, c := range cases {
+		if got := g.Contains(c.lat, c.lng); got != c.want {
+			t.Fatalf("%s: expected %v got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestGeometry_Contains_MultiPolygon(t *testing.T) {
+	t.Parallel()
+
+	g := mustGeometry(t, `{
+		"type": "MultiPolygon",
+		"coordinates": [
+			[[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]],
+			[[[10, 10], [11, 10], [11, 11], [10, 11], [10, 10]]]
+		]
+	}`)
+
+	if !g.Contains(0.5, 0.5) || !g.Contains(10.5, 10.5) {
+		t.Fatalf("expected point inside one of parts")
+	}
+	if g.Contains(5, 5) {
+		t.Fatalf("expected point between parts to be outside")
+	}
+}
+
+func TestGeometry_JSONRoundTrip_KeepsType(t *testing.T) {
+	t.Parallel()
+
+	g := mustGeometry(t, squareWithHole)
+
+	b, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatalf("unmarshal raw: %v", err)
+	}
+	if raw["type"] != "Polygon" {
+		t.Fatalf("expected type Polygon got %v", raw["type"])
+	}
+
+	back := mustGeometry(t, string(b))
+	if len(back.Polygons) != 1 || len(back.Polygons[0]) != 2 {
+		t.Fatalf("unexpected round trip: %+v", back)
+	}
+}
+
+func TestGeometry_Validate_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"not_closed":   `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}`,
+		"too_short":    `{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,0]]]}`,
+		"out_of_range": `{"type":"Polygon","coordinates":[[[0,0],[200,0],[1,1],[0,0]]]}`,
+		"no_rings":     `{"type":"Polygon","coordinates":[]}`,
+	}
+
+	for name, raw := range cases {
+		g := mustGeometry(t, raw)
+		if err := g.Validate(); !errors.Is(err, e.ErrInvalidInput) {
+			t.Fatalf("%s: expected ErrInvalidInput got %v", name, err)
+		}
+	}
+}
+
+func TestGeometry_Unmarshal_UnsupportedType(t *testing.T) {
+	t.Parallel()
+
+	var g domain.Geometry
+	err := json.Unmarshal([]byte(`{"type":"Point","coordinates":[1,2]}`), &g)
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput got %v", err)
+	}
+}
@@ -9,30 +9,166 @@ import (
 type IncidentStatus string
 
 const (
-	IncidentActive   IncidentStatus = "active"
-	IncidentInactive IncidentStatus = "inactive"
+	IncidentActive    IncidentStatus = "active"
+	IncidentInactive  IncidentStatus = "inactive"
+	IncidentScheduled IncidentStatus = "scheduled" // ждёт starts_at
+	IncidentExpired   IncidentStatus = "expired"   // завершён по ends_at
 )
 
+type IncidentSeverity string
+
+const (
+	SeverityLow      IncidentSeverity = "low"
+	SeverityMedium   IncidentSeverity = "medium"
+	SeverityHigh     IncidentSeverity = "high"
+	SeverityCritical IncidentSeverity = "critical"
+)
+
+func (s IncidentSeverity) Valid() bool {
+	switch s {
+	case SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+		return true
+	}
+	return false
+}
+
+type IncidentCategory string
+
+const (
+	CategoryFire         IncidentCategory = "fire"
+	CategoryFlood        IncidentCategory = "flood"
+	CategoryGasLeak      IncidentCategory = "gas_leak"
+	CategoryRoadClosure  IncidentCategory = "road_closure"
+	CategoryIndustrial   IncidentCategory = "industrial"
+	CategoryPublicSafety IncidentCategory = "public_safety"
+	CategoryWeather      IncidentCategory = "weather"
+	CategoryOther        IncidentCategory = "other"
+)
+
+func (c IncidentCategory) Valid() bool {
+	switch c {
+	case CategoryFire, CategoryFlood, CategoryGasLeak, CategoryRoadClosure,
+		CategoryIndustrial, CategoryPublicSafety, CategoryWeather, CategoryOther:
+		return true
+	}
+	return false
+}
+
 type Incident struct {
-	ID        uuid.UUID      `json:"id"`
-	Lat       float64        `json:"lat" validate:"required,lat"` // -90..90
-	Lng       float64        `json:"lng" validate:"required,lng"` // -180..180
-	RadiusKM  float64        `json:"radius_km" validate:"required,min=0.1,max=100"`
-	Status    IncidentStatus `json:"status"`
-	CreatedAt time.Time      `json:"created_at"`
+	ID          uuid.UUID        `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Severity    IncidentSeverity `json:"severity"`
+	Category    IncidentCategory `json:"category"`
+	Lat         float64          `json:"lat" validate:"required,lat"` // -90..90
+	Lng         float64          `json:"lng" validate:"required,lng"` // -180..180
+	RadiusKM    float64          `json:"radius_km" validate:"required_without=Geometry,omitempty,min=0.1,max=100"`
+	Geometry    *Geometry        `json:"geometry,omitempty"` // для полигональных зон; Lat/Lng — точка внутри полигона
+	Status      IncidentStatus   `json:"status"`
+	StartsAt    *time.Time       `json:"starts_at,omitempty"`
+	EndsAt      *time.Time       `json:"ends_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// IncidentSummary — то, что видят клиенты и получатели вебхуков о найденном инциденте.
+type IncidentSummary struct {
+	ID          uuid.UUID        `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description,omitempty"`
+	Severity    IncidentSeverity `json:"severity"`
+	Category    IncidentCategory `json:"category"`
 }
 
 type CachedIncident struct {
-	ID       uuid.UUID `json:"id"`
-	Lat      float64   `json:"lat"`
-	Lng      float64   `json:"lng"`
-	RadiusKM float64   `json:"radius_km"`
+	ID          uuid.UUID        `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description,omitempty"`
+	Severity    IncidentSeverity `json:"severity"`
+	Category    IncidentCategory `json:"category"`
+	Lat         float64          `json:"lat"`
+	Lng         float64          `json:"lng"`
+	RadiusKM    float64          `json:"radius_km"`
+	Geometry    *Geometry        `json:"geometry,omitempty"`
+	StartsAt    *time.Time       `json:"starts_at,omitempty"`
+	EndsAt      *time.Time       `json:"ends_at,omitempty"`
+}
+
+func (i *Incident) Summary() IncidentSummary {
+	return IncidentSummary{
+		ID:          i.ID,
+		Title:       i.Title,
+		Description: i.Description,
+		Severity:    i.Severity,
+		Category:    i.Category,
+	}
+}
+
+func (c CachedIncident) Summary() IncidentSummary {
+	return IncidentSummary{
+		ID:          c.ID,
+		Title:       c.Title,
+		Description: c.Description,
+		Severity:    c.Severity,
+		Category:    c.Category,
+	}
+}
+
+// ActiveAt — попадает ли момент t в окно [starts_at, ends_at) инцидента.
+func (c CachedIncident) ActiveAt(t time.Time) bool {
+	if c.StartsAt != nil && t.Before(*c.StartsAt) {
+		return false
+	}
+	if c.EndsAt != nil && !t.Before(*c.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// ScheduleResult — инциденты, у которых воркер расписания сменил статус.
+type ScheduleResult struct {
+	Activated []uuid.UUID `json:"activated"`
+	Expired   []uuid.UUID `json:"expired"`
+}
+
+func (r ScheduleResult) Changed() bool {
+	return len(r.Activated) > 0 || len(r.Expired) > 0
+}
+
+// MaxNearbyRadiusKM — предел радиуса публичного поиска «рядом со мной».
+const MaxNearbyRadiusKM = 50
+
+// NearbyRequest — активные инциденты вокруг точки. RadiusKM=0 — радиус по умолчанию.
+type NearbyRequest struct {
+	Lat      float64 `query:"lat" validate:"lat"`
+	Lng      float64 `query:"lng" validate:"lng"`
+	RadiusKM float64 `query:"radius_km" validate:"omitempty,gt=0,max=50"`
+	Limit    int     `query:"limit" validate:"min=1,max=100"`
+}
+
+type NearbyResponse struct {
+	Incidents []NearbyIncident `json:"incidents"`
+	RadiusKM  float64          `json:"radius_km"`
+	Limit     int              `json:"limit"`
 }
 
 type NearbyIncident struct {
-	ID         uuid.UUID `json:"id"`
-	Lat        float64   `json:"lat"`
-	Lng        float64   `json:"lng"`
-	RadiusKM   float64   `json:"radius_km"`
-	DistanceKM float64   `json:"distance_km"`
+	ID          uuid.UUID        `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description,omitempty"`
+	Severity    IncidentSeverity `json:"severity"`
+	Category    IncidentCategory `json:"category"`
+	Lat         float64          `json:"lat"`
+	Lng         float64          `json:"lng"`
+	RadiusKM    float64          `json:"radius_km"`
+	DistanceKM  float64          `json:"distance_km"`
+}
+
+func (n NearbyIncident) Summary() IncidentSummary {
+	return IncidentSummary{
+		ID:          n.ID,
+		Title:       n.Title,
+		Description: n.Description,
+		Severity:    n.Severity,
+		Category:    n.Category,
+	}
 }
@@ -1,27 +1,111 @@
 package domain
 
+import (
+	"time"
+
+	"redCollar/pkg/cursor"
+)
+
 type CreateIncidentRequest struct {
-	Lat      float64        `json:"lat" validate:"required,lat"`
-	Lng      float64        `json:"lng" validate:"required,lng"`
-	RadiusKM float64        `json:"radius_km" validate:"required,min=0.1,max=100"`
-	Status   IncidentStatus `json:"status" validate:"omitempty,oneof=active inactive"`
+	Title       string           `json:"title" validate:"max=200"`
+	Description string           `json:"description" validate:"max=4000"`
+	Severity    IncidentSeverity `json:"severity" validate:"omitempty,oneof=low medium high critical"`
+	Category    IncidentCategory `json:"category" validate:"omitempty,oneof=fire flood gas_leak road_closure industrial public_safety weather other"`
+	Lat         float64          `json:"lat" validate:"required_without=Geometry,omitempty,lat"`
+	Lng         float64          `json:"lng" validate:"required_without=Geometry,omitempty,lng"`
+	RadiusKM    float64          `json:"radius_km" validate:"required_without=Geometry,omitempty,min=0.1,max=100"`
+	Geometry    *Geometry        `json:"geometry,omitempty"`
+	Status      IncidentStatus   `json:"status" validate:"omitempty,oneof=active inactive"`
+	StartsAt    *time.Time       `json:"starts_at,omitempty"`
+	EndsAt      *time.Time       `json:"ends_at,omitempty"`
 }
 
 type UpdateIncidentRequest struct {
-	Lat      *float64        `json:"lat" validate:"omitempty,lat"`
-	Lng      *float64        `json:"lng" validate:"omitempty,lng"`
-	RadiusKM *float64        `json:"radius_km" validate:"omitempty,min=0.1,max=100"`
-	Status   *IncidentStatus `json:"status" validate:"omitempty,oneof=active inactive"`
+	Title       *string           `json:"title" validate:"omitempty,max=200"`
+	Description *string           `json:"description" validate:"omitempty,max=4000"`
+	Severity    *IncidentSeverity `json:"severity" validate:"omitempty,oneof=low medium high critical"`
+	Category    *IncidentCategory `json:"category" validate:"omitempty,oneof=fire flood gas_leak road_closure industrial public_safety weather other"`
+	Lat         *float64          `json:"lat" validate:"omitempty,lat"`
+	Lng         *float64          `json:"lng" validate:"omitempty,lng"`
+	RadiusKM    *float64          `json:"radius_km" validate:"omitempty,min=0.1,max=100"`
+	Geometry    *Geometry         `json:"geometry,omitempty"`
+	Status      *IncidentStatus   `json:"status" validate:"omitempty,oneof=active inactive"`
+	StartsAt    *time.Time        `json:"starts_at,omitempty"`
+	EndsAt      *time.Time        `json:"ends_at,omitempty"`
+	// ClearStartsAt/ClearEndsAt снимают границу окна: nil в StartsAt/EndsAt
+	// означает «не менять».
+	ClearStartsAt bool `json:"clear_starts_at"`
+	ClearEndsAt   bool `json:"clear_ends_at"`
+}
+
+// ChangesSchedule — правка затрагивает окно starts_at/ends_at.
+func (r UpdateIncidentRequest) ChangesSchedule() bool {
+	return r.StartsAt != nil || r.EndsAt != nil || r.ClearStartsAt || r.ClearEndsAt
 }
 
 type ListIncidentsRequest struct {
 	Page  int `query:"page" validate:"min=1"`
 	Limit int `query:"limit" validate:"min=1,max=100"`
+
+	Status      IncidentStatus `query:"status" validate:"omitempty,oneof=active inactive scheduled expired"`
+	CreatedFrom time.Time      `query:"created_from"`
+	CreatedTo   time.Time      `query:"created_to" validate:"omitempty,gtfield=CreatedFrom"`
+	RadiusMin   float64        `query:"radius_min" validate:"omitempty,min=0,max=100"`
+	RadiusMax   float64        `query:"radius_max" validate:"omitempty,gtefield=RadiusMin,max=100"`
+	BBox        *BBox          `query:"bbox"`
+	Near        *NearFilter    // near_lat, near_lng, within_km
+
+	SortBy  IncidentSortField `query:"sort_by" validate:"omitempty,oneof=created_at radius_km status severity title"`
+	SortDir SortDirection     `query:"sort_dir" validate:"omitempty,oneof=asc desc"`
+
+	// Keyset-режим по (created_at, id) включается параметром cursor;
+	// пустой cursor — первая страница. Page при этом игнорируется.
+	CursorMode bool           `query:"-"`
+	After      *cursor.Cursor `query:"cursor"`
+}
+
+// BBox — прямоугольник в порядке GeoJSON: minLng,minLat,maxLng,maxLat.
+type BBox struct {
+	MinLng float64 `validate:"lng"`
+	MinLat float64 `validate:"lat"`
+	MaxLng float64 `validate:"lng,gtfield=MinLng"`
+	MaxLat float64 `validate:"lat,gtfield=MinLat"`
 }
 
+// NearFilter — «в пределах WithinKM от точки».
+type NearFilter struct {
+	Lat      float64 `validate:"lat"`
+	Lng      float64 `validate:"lng"`
+	WithinKM float64 `validate:"gt=0,max=1000"`
+}
+
+type IncidentSortField string
+
+const (
+	SortByCreatedAt IncidentSortField = "created_at"
+	SortByRadius    IncidentSortField = "radius_km"
+	SortByStatus    IncidentSortField = "status"
+	SortBySeverity  IncidentSortField = "severity"
+	SortByTitle     IncidentSortField = "title"
+)
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
 type ListIncidentsResponse struct {
-	Incidents []Incident `json:"incidents"`
-	Page      int        `json:"page"`
-	Limit     int        `json:"limit"`
-	Total     int64      `json:"total"`
+	Incidents  []*Incident `json:"incidents"`
+	Page       int         `json:"page,omitempty"`
+	Limit      int         `json:"limit"`
+	Total      int64       `json:"total"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+type IncidentPage struct {
+	Items      []*Incident
+	Total      int64
+	NextCursor string
 }
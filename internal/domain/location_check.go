@@ -3,6 +3,8 @@ package domain
 import (
 	"time"
 
+	"redCollar/pkg/cursor"
+
 	"github.com/google/uuid"
 )
 
@@ -12,8 +14,89 @@ type LocationCheckRequest struct {
 	Lng    float64 `json:"lng" validate:"required,lng"`
 }
 
+// LocationCheckResponse — все инциденты в точке; New — те, о которых
+// пользователь узнал этой проверкой, Known — о которых уже был алерт.
 type LocationCheckResponse struct {
-	Incidents []string `json:"incidents"` // массив uuid строк
+	Incidents []IncidentSummary `json:"incidents"`
+	New       []uuid.UUID       `json:"new,omitempty"`
+	Known     []uuid.UUID       `json:"known,omitempty"`
+}
+
+// IncidentProximity — инцидент рядом с точкой проверки. DistanceKM — до
+// центра (у полигона — до его опорной точки), DistanceToEdgeKM — до
+// ближайшей границы зоны, изнутри или снаружи.
+type IncidentProximity struct {
+	Incident         IncidentSummary `json:"incident"`
+	DistanceKM       float64         `json:"distance_km"`
+	DistanceToEdgeKM float64         `json:"distance_to_edge_km"`
+	Inside           bool            `json:"inside"`
+	InBuffer         bool            `json:"in_buffer"`
+}
+
+// LocationCheckV2Response — ответ /api/v2/location/check. Inside — зоны,
+// в которых точка; Approaching — зоны, до которых снаружи не больше BufferKM.
+// New и Known — как в v1, только по Inside.
+type LocationCheckV2Response struct {
+	Inside      []IncidentProximity `json:"inside"`
+	Approaching []IncidentProximity `json:"approaching"`
+	New         []uuid.UUID         `json:"new,omitempty"`
+	Known       []uuid.UUID         `json:"known,omitempty"`
+	BufferKM    float64             `json:"buffer_km"`
+}
+
+// MaxCheckBatchPoints — сколько точек принимает один пакетный запрос.
+const MaxCheckBatchPoints = 500
+
+// MaxCheckPointAge — насколько старые точки принимает пакетный запрос.
+const MaxCheckPointAge = 7 * 24 * time.Hour
+
+// LocationCheckBatchRequest — точки, накопленные клиентом офлайн. Каждая
+// точка проверяется отдельно; невалидная не роняет остальные.
+type LocationCheckBatchRequest struct {
+	Points []LocationCheckPoint `json:"points" validate:"required,min=1,max=500"`
+}
+
+type LocationCheckPoint struct {
+	UserID    string    `json:"user_id"`
+	Lat       float64   `json:"lat"`
+	Lng       float64   `json:"lng"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// LocationCheckPointResult — результат точки с индексом Index в запросе.
+// При Error остальные поля пустые, проверка не сохранена. Stale — точка
+// старше уже применённой проверки пользователя: она сохранена, но на
+// присутствие и алерты не влияет, New и Known пустые.
+type LocationCheckPointResult struct {
+	Index     int               `json:"index"`
+	Incidents []IncidentSummary `json:"incidents"`
+	New       []uuid.UUID       `json:"new,omitempty"`
+	Known     []uuid.UUID       `json:"known,omitempty"`
+	Stale     bool              `json:"stale,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+type LocationCheckBatchResponse struct {
+	Results  []LocationCheckPointResult `json:"results"`
+	Accepted int                        `json:"accepted"`
+}
+
+// AlertPolicy — дедупликация алертов по паре (пользователь, инцидент).
+// Window — сколько после алерта повторные попадания не считаются новыми;
+// 0 отключает дедупликацию. Renotify — повторять алерт раз в Window, пока
+// пользователь внутри; NotifyExit — сообщать о выходе из зоны.
+type AlertPolicy struct {
+	Window     time.Duration
+	Renotify   bool
+	NotifyExit bool
+}
+
+// AlertTransitions — попадания проверки, разделённые на вход в зону (Entered)
+// и уже известные (Known), плюс зоны, из которых пользователь вышел.
+type AlertTransitions struct {
+	Entered []uuid.UUID
+	Known   []uuid.UUID
+	Exited  []uuid.UUID
 }
 
 type LocationCheck struct {
@@ -24,3 +107,63 @@ type LocationCheck struct {
 	IncidentIDs []uuid.UUID `json:"incident_ids"`
 	CheckedAt   time.Time   `json:"checked_at"`
 }
+
+// ListChecksRequest — фильтры истории проверок. Пагинация только keyset
+// по (checked_at, id), от новых к старым.
+type ListChecksRequest struct {
+	Limit      int            `query:"limit" validate:"min=1,max=100"`
+	UserID     uuid.UUID      `query:"user_id"`
+	From       time.Time      `query:"from"`
+	To         time.Time      `query:"to" validate:"omitempty,gtfield=From"`
+	IncidentID uuid.UUID      `query:"incident_id"`
+	HadHits    bool           `query:"had_hits"`
+	BBox       *BBox          `query:"bbox"`
+	After      *cursor.Cursor `query:"cursor"`
+}
+
+type CheckPage struct {
+	Items      []*LocationCheck
+	NextCursor string
+}
+
+type ListChecksResponse struct {
+	Checks     []*LocationCheck `json:"checks"`
+	Limit      int              `json:"limit"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// IncidentVisitor — пользователь, чьи проверки попадали в инцидент.
+type IncidentVisitor struct {
+	UserID      uuid.UUID `json:"user_id"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	Checks      int64     `json:"checks"`
+}
+
+// IncidentVisitorsRequest — keyset по (last_seen_at, user_id), свежие первыми.
+type IncidentVisitorsRequest struct {
+	Limit int            `query:"limit" validate:"min=1,max=100"`
+	From  time.Time      `query:"from"`
+	To    time.Time      `query:"to" validate:"omitempty,gtfield=From"`
+	After *cursor.Cursor `query:"cursor"`
+}
+
+type VisitorPage struct {
+	Items      []IncidentVisitor
+	NextCursor string
+}
+
+type IncidentVisitorsResponse struct {
+	IncidentID uuid.UUID         `json:"incident_id"`
+	Users      []IncidentVisitor `json:"users"`
+	Limit      int               `json:"limit"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// RetentionReport — итог одного прохода очистки location_checks.
+type RetentionReport struct {
+	Cutoff  time.Time
+	Deleted int64
+	Files   []string
+	Err     error
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"time"
+
+	"redCollar/pkg/cursor"
+
+	"github.com/google/uuid"
+)
+
+type OccupancyKind string
+
+const (
+	OccupancyEntered OccupancyKind = "entered"
+	OccupancyExited  OccupancyKind = "exited"
+)
+
+// EventType — событие вебхука, которым публикуется переход.
+func (k OccupancyKind) EventType() WebhookEventType {
+	if k == OccupancyExited {
+		return WebhookEventUserExitedIncident
+	}
+	return WebhookEventUserEnteredIncident
+}
+
+// TransitionReason — что вызвало переход.
+type TransitionReason string
+
+const (
+	// TransitionCheck — переход по проверке координат.
+	TransitionCheck TransitionReason = "check"
+	// TransitionIncidentClosed — выход, потому что инцидент снят, удалён или истёк.
+	TransitionIncidentClosed TransitionReason = "incident_closed"
+)
+
+// OccupancyTransition — вход пользователя в инцидент или выход из него,
+// зафиксированный проверкой CheckID в точке Lat/Lng. У выхода из-за закрытия
+// инцидента проверки и точки нет: CheckID, Lat и Lng пустые.
+type OccupancyTransition struct {
+	ID         uuid.UUID        `json:"id"`
+	UserID     uuid.UUID        `json:"user_id"`
+	IncidentID uuid.UUID        `json:"incident_id"`
+	Kind       OccupancyKind    `json:"kind"`
+	Reason     TransitionReason `json:"reason"`
+	CheckID    *uuid.UUID       `json:"check_id"`
+	Lat        *float64         `json:"lat"`
+	Lng        *float64         `json:"lng"`
+	At         time.Time        `json:"at"`
+}
+
+// CheckEventFunc строит события сохраняемой проверки по переходам, которые
+// она вызвала. Репозиторий вызывает её внутри транзакции и пишет результат в outbox.
+type CheckEventFunc func(transitions []OccupancyTransition) ([]WebhookEvent, error)
+
+// CheckBatchEventFunc — то же для пакета: i — индекс проверки в пакете.
+type CheckBatchEventFunc func(i int, transitions []OccupancyTransition) ([]WebhookEvent, error)
+
+// OccupancyPayload — data событий user.entered_incident и user.exited_incident.
+// Lat/Lng пустые у выхода из-за закрытия инцидента.
+type OccupancyPayload struct {
+	UserID   string           `json:"user_id"`
+	Incident IncidentSummary  `json:"incident"`
+	Reason   TransitionReason `json:"reason"`
+	Lat      *float64         `json:"lat"`
+	Lng      *float64         `json:"lng"`
+	At       time.Time        `json:"at"`
+}
+
+// Event — событие вебхука перехода в инцидент inc.
+func (t OccupancyTransition) Event(inc IncidentSummary) (WebhookEvent, error) {
+	return NewWebhookEvent(t.Kind.EventType(), t.At, OccupancyPayload{
+		UserID:   t.UserID.String(),
+		Incident: inc,
+		Reason:   t.Reason,
+		Lat:      t.Lat,
+		Lng:      t.Lng,
+		At:       t.At,
+	})
+}
+
+// OccupantStaleAfter — пользователь, от которого столько не было проверок,
+// больше не считается находящимся внутри инцидента.
+const OccupantStaleAfter = time.Hour
+
+// Occupant — пользователь, который сейчас внутри инцидента.
+type Occupant struct {
+	UserID     uuid.UUID `json:"user_id"`
+	EnteredAt  time.Time `json:"entered_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// OccupantsRequest — keyset по (entered_at, user_id), вошедшие позже первыми.
+type OccupantsRequest struct {
+	Limit int            `query:"limit" validate:"min=1,max=100"`
+	After *cursor.Cursor `query:"cursor"`
+}
+
+type OccupantPage struct {
+	Items      []Occupant
+	NextCursor string
+}
+
+type OccupantsResponse struct {
+	IncidentID uuid.UUID  `json:"incident_id"`
+	Occupants  []Occupant `json:"occupants"`
+	Limit      int        `json:"limit"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// TimelineRequest — история входов и выходов пользователя, keyset по (at, id),
+// свежие первыми.
+type TimelineRequest struct {
+	Limit      int            `query:"limit" validate:"min=1,max=100"`
+	IncidentID uuid.UUID      `query:"incident_id"`
+	From       time.Time      `query:"from"`
+	To         time.Time      `query:"to" validate:"omitempty,gtfield=From"`
+	After      *cursor.Cursor `query:"cursor"`
+}
+
+type TimelinePage struct {
+	Items      []OccupancyTransition
+	NextCursor string
+}
+
+type TimelineResponse struct {
+	UserID     uuid.UUID             `json:"user_id"`
+	Events     []OccupancyTransition `json:"events"`
+	Limit      int                   `json:"limit"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"redCollar/pkg/e"
+)
+
+// MaxRoutePositions — сколько вершин маршрута принимает одна проверка.
+const MaxRoutePositions = 10000
+
+// MaxRouteLengthKM — предел длины маршрута. Длинные сегменты дробятся на
+// шаги в несколько километров, так что длина ограничивает и число точек.
+const MaxRouteLengthKM = 5000
+
+// LineString — GeoJSON LineString, вершины в порядке [lng, lat].
+type LineString struct {
+	Coordinates []Position
+}
+
+func (l LineString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type        string     `json:"type"`
+		Coordinates []Position `json:"coordinates"`
+	}{Type: "LineString", Coordinates: l.Coordinates})
+}
+
+func (l *LineString) UnmarshalJSON(data []byte) error {
+	var raw geoJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Type != "LineString" {
+		return fmt.Errorf("%w: unsupported type %q", e.ErrInvalidInput, raw.Type)
+	}
+	if err := json.Unmarshal(raw.Coordinates, &l.Coordinates); err != nil {
+		return fmt.Errorf("%w: %v", e.ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// RouteCheckRequest — маршрут как GeoJSON LineString (Route) или как
+// encoded polyline (Polyline, точность 5 по умолчанию или 6). Передаётся
+// ровно одно из двух.
+type RouteCheckRequest struct {
+	Route     *LineString `json:"route,omitempty"`
+	Polyline  string      `json:"polyline,omitempty"`
+	Precision int         `json:"precision,omitempty" validate:"omitempty,oneof=5 6"`
+}
+
+// RouteInterval — участок маршрута внутри зоны инцидента, в км от начала маршрута.
+type RouteInterval struct {
+	StartKM float64 `json:"start_km"`
+	EndKM   float64 `json:"end_km"`
+}
+
+// RouteIncident — активный инцидент, через который проходит маршрут; маршрут
+// может заходить в зону несколько раз.
+type RouteIncident struct {
+	Incident      IncidentSummary `json:"incident"`
+	Intersections []RouteInterval `json:"intersections"`
+}
+
+// RouteCheckResponse — инциденты в порядке, в котором маршрут в них входит.
+type RouteCheckResponse struct {
+	LengthKM  float64         `json:"length_km"`
+	Incidents []RouteIncident `json:"incidents"`
+}
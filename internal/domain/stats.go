@@ -1,11 +1,100 @@
 package domain
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IncidentStats — ответ /admin/stats. Поля ряда заполняются только для
+// /admin/stats/series.
 type IncidentStats struct {
 	UserCount   int64 `json:"unique_users"`
 	TotalChecks int64 `json:"total_checks"`
-	Minutes     int   `json:"minutes"`
+	Minutes     int   `json:"minutes,omitempty"`
+
+	From              *time.Time     `json:"from,omitempty"`
+	To                *time.Time     `json:"to,omitempty"`
+	Bucket            StatsBucket    `json:"bucket,omitempty"`
+	ChecksWithHits    int64          `json:"checks_with_hits,omitempty"`
+	ChecksWithoutHits int64          `json:"checks_without_hits,omitempty"`
+	Series            []StatsPoint   `json:"series,omitempty"`
+	IncidentHits      []IncidentHits `json:"incident_hits,omitempty"`
+	TopIncidents      []IncidentHits `json:"top_incidents,omitempty"`
 }
 
 type StatsRequest struct {
 	Minutes int `query:"minutes" validate:"min=1,max=1440"` // 1 день max
 }
+
+type StatsBucket string
+
+const (
+	BucketMinute StatsBucket = "minute"
+	BucketHour   StatsBucket = "hour"
+	BucketDay    StatsBucket = "day"
+)
+
+func (b StatsBucket) Duration() time.Duration {
+	switch b {
+	case BucketMinute:
+		return time.Minute
+	case BucketHour:
+		return time.Hour
+	case BucketDay:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+type StatsSeriesRequest struct {
+	From   time.Time   `query:"from" validate:"required"`
+	To     time.Time   `query:"to" validate:"required,gtfield=From"`
+	Bucket StatsBucket `query:"bucket" validate:"oneof=minute hour day"`
+	Top    int         `query:"top" validate:"min=0,max=100"`
+}
+
+// StatsPoint — одна корзина ряда; Bucket — начало интервала.
+type StatsPoint struct {
+	Bucket            time.Time `json:"bucket"`
+	TotalChecks       int64     `json:"total_checks"`
+	ChecksWithHits    int64     `json:"checks_with_hits"`
+	ChecksWithoutHits int64     `json:"checks_without_hits"`
+	UniqueUsers       int64     `json:"unique_users"`
+}
+
+type IncidentHits struct {
+	IncidentID  uuid.UUID `json:"incident_id"`
+	Title       string    `json:"title,omitempty"`
+	Hits        int64     `json:"hits"`
+	UniqueUsers int64     `json:"unique_users"`
+}
+
+// IncidentHitStats — сколько раз и скольких пользователей предупредили об инциденте.
+type IncidentHitStats struct {
+	IncidentID  uuid.UUID    `json:"incident_id"`
+	TotalHits   int64        `json:"total_hits"`
+	UniqueUsers int64        `json:"unique_users"`
+	FirstHitAt  *time.Time   `json:"first_hit_at"`
+	LastHitAt   *time.Time   `json:"last_hit_at"`
+	Hourly      []HourlyHits `json:"hourly"`
+}
+
+type HourlyHits struct {
+	Hour        time.Time `json:"hour"`
+	Hits        int64     `json:"hits"`
+	UniqueUsers int64     `json:"unique_users"`
+}
+
+// IncidentStatsRequest — необязательное окно; пустое — вся история.
+type IncidentStatsRequest struct {
+	From time.Time `query:"from"`
+	To   time.Time `query:"to" validate:"omitempty,gtfield=From"`
+}
+
+// RollupResult — что свернул один проход: [From, To) и число сырых проверок.
+type RollupResult struct {
+	From   time.Time
+	To     time.Time
+	Checks int64
+}
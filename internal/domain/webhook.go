@@ -1,15 +1,98 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// WebhookEventVersion — версия конверта WebhookEvent. Меняется только при
+// несовместимом изменении формата.
+const WebhookEventVersion = 1
+
+// WebhookEvent — конверт события, который получает подписчик. Data зависит
+// от Type: WebhookPayload для location.*, OccupancyPayload для user.*,
+// Incident для incident.*.
+type WebhookEvent struct {
+	Version    int              `json:"version"`
+	Type       WebhookEventType `json:"type"`
+	ID         uuid.UUID        `json:"id"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       json.RawMessage  `json:"data"`
+}
+
+func NewWebhookEvent(t WebhookEventType, occurredAt time.Time, data any) (WebhookEvent, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return WebhookEvent{}, err
+	}
+	return WebhookEvent{
+		Version:    WebhookEventVersion,
+		Type:       t,
+		ID:         uuid.New(),
+		OccurredAt: occurredAt.UTC(),
+		Data:       b,
+	}, nil
+}
+
+// IncidentEventFunc строит событие по инциденту в том виде, в каком его
+// сохранило изменение. Репозиторий вызывает её внутри транзакции.
+type IncidentEventFunc func(inc *Incident) (WebhookEvent, error)
+
+// UserID — пользователь, к которому относится событие, если есть.
+func (ev WebhookEvent) UserID() string {
+	if !ev.Type.IsLocation() && !ev.Type.IsOccupancy() {
+		return ""
+	}
+	var p struct {
+		UserID string `json:"user_id"`
+	}
+	_ = json.Unmarshal(ev.Data, &p)
+	return p.UserID
+}
+
 type WebhookPayload struct {
-	UserID    string      `json:"user_id"`
-	Lat       float64     `json:"lat"`
-	Lng       float64     `json:"lng"`
-	Incidents []uuid.UUID `json:"incidents"`
-	CheckedAt time.Time   `json:"checked_at"`
+	UserID    string            `json:"user_id"`
+	Lat       float64           `json:"lat"`
+	Lng       float64           `json:"lng"`
+	Incidents []IncidentSummary `json:"incidents"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// WebhookMessage — элемент очереди доставки: событие плюс служебные поля.
+// Без SubscriptionID это событие, которое ещё нужно разослать по подпискам;
+// с ним — доставка конкретному получателю со своим счётчиком попыток.
+type WebhookMessage struct {
+	ID             uuid.UUID    `json:"id"`
+	Envelope       WebhookEvent `json:"envelope"`
+	SubscriptionID *uuid.UUID   `json:"subscription_id,omitempty"`
+	Attempts       int          `json:"attempts"`
+	EnqueuedAt     time.Time    `json:"enqueued_at"`
+}
+
+// DeadLetter — сообщение, исчерпавшее попытки доставки. Raw заполнен у
+// сообщения, которое не удалось разобрать: оно сохранено как лежало в очереди.
+type DeadLetter struct {
+	WebhookMessage
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+	Raw       string    `json:"raw,omitempty"`
+}
+
+type ListDeadLettersRequest struct {
+	Page  int `query:"page" validate:"min=1"`
+	Limit int `query:"limit" validate:"min=1,max=100"`
+}
+
+type DeadLetterPage struct {
+	Items []DeadLetter
+	Total int64
+}
+
+type ListDeadLettersResponse struct {
+	DeadLetters []DeadLetter `json:"dead_letters"`
+	Total       int64        `json:"total"`
+	Page        int          `json:"page"`
+	Limit       int          `json:"limit"`
 }
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"redCollar/pkg/cursor"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailed — попытка не удалась, доставка будет повторена.
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+	// WebhookDeliveryDead — последняя попытка не удалась, доставка ушла в dead letters.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDeliveryAttempt — одна попытка отправки вебхука получателю.
+// DeliveryID совпадает с X-Delivery-ID и с id dead letter, SubscriptionID
+// равен uuid.Nil для WEBHOOK_URL.
+type WebhookDeliveryAttempt struct {
+	ID              uuid.UUID             `json:"id"`
+	DeliveryID      uuid.UUID             `json:"delivery_id"`
+	EventID         uuid.UUID             `json:"event_id"`
+	SubscriptionID  uuid.UUID             `json:"subscription_id"`
+	URL             string                `json:"url"`
+	UserID          string                `json:"user_id"`
+	Event           WebhookEventType      `json:"event"`
+	Attempt         int                   `json:"attempt"`
+	Status          WebhookDeliveryStatus `json:"status"`
+	PayloadHash     string                `json:"payload_hash"`
+	StatusCode      int                   `json:"status_code,omitempty"`
+	LatencyMs       int64                 `json:"latency_ms"`
+	Error           string                `json:"error,omitempty"`
+	ResponseSnippet string                `json:"response_snippet,omitempty"`
+	AttemptedAt     time.Time             `json:"attempted_at"`
+}
+
+// ListWebhookDeliveriesRequest — keyset по (attempted_at, id), свежие первыми.
+type ListWebhookDeliveriesRequest struct {
+	Limit          int                   `query:"limit" validate:"min=1,max=100"`
+	Status         WebhookDeliveryStatus `query:"status" validate:"omitempty,oneof=succeeded failed dead"`
+	UserID         uuid.UUID             `query:"user_id"`
+	SubscriptionID *uuid.UUID            `query:"subscription_id"`
+	DeliveryID     uuid.UUID             `query:"delivery_id"`
+	From           time.Time             `query:"from"`
+	To             time.Time             `query:"to" validate:"omitempty,gtfield=From"`
+	After          *cursor.Cursor        `query:"cursor"`
+}
+
+type WebhookDeliveryPage struct {
+	Items      []*WebhookDeliveryAttempt
+	NextCursor string
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []*WebhookDeliveryAttempt `json:"deliveries"`
+	Limit      int                       `json:"limit"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
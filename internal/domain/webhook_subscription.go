@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEventType string
+
+const (
+	// WebhookEventLocationHit — проверка координат попала в один или несколько инцидентов.
+	WebhookEventLocationHit WebhookEventType = "location.hit"
+	// WebhookEventLocationExited — пользователь вышел из зоны инцидентов, о которых был алерт.
+	WebhookEventLocationExited WebhookEventType = "location.exited"
+
+	// WebhookEventUserEnteredIncident/WebhookEventUserExitedIncident — смена
+	// присутствия пользователя внутри инцидента по сохранённым проверкам.
+	WebhookEventUserEnteredIncident WebhookEventType = "user.entered_incident"
+	WebhookEventUserExitedIncident  WebhookEventType = "user.exited_incident"
+
+	WebhookEventIncidentCreated     WebhookEventType = "incident.created"
+	WebhookEventIncidentUpdated     WebhookEventType = "incident.updated"
+	WebhookEventIncidentDeactivated WebhookEventType = "incident.deactivated"
+	WebhookEventIncidentExpired     WebhookEventType = "incident.expired"
+)
+
+func (t WebhookEventType) Valid() bool {
+	switch t {
+	case WebhookEventLocationHit, WebhookEventLocationExited,
+		WebhookEventUserEnteredIncident, WebhookEventUserExitedIncident,
+		WebhookEventIncidentCreated, WebhookEventIncidentUpdated,
+		WebhookEventIncidentDeactivated, WebhookEventIncidentExpired:
+		return true
+	}
+	return false
+}
+
+// IsOccupancy — вход или выход пользователя, data — OccupancyPayload.
+func (t WebhookEventType) IsOccupancy() bool {
+	return t == WebhookEventUserEnteredIncident || t == WebhookEventUserExitedIncident
+}
+
+// IsLocation — событие о пользователе и точке, data — WebhookPayload.
+func (t WebhookEventType) IsLocation() bool {
+	return t == WebhookEventLocationHit || t == WebhookEventLocationExited
+}
+
+// IsIncident — событие жизненного цикла инцидента, data — domain.Incident.
+func (t WebhookEventType) IsIncident() bool {
+	switch t {
+	case WebhookEventIncidentCreated, WebhookEventIncidentUpdated,
+		WebhookEventIncidentDeactivated, WebhookEventIncidentExpired:
+		return true
+	}
+	return false
+}
+
+// WebhookSubscription — получатель вебхуков. Пустой список фильтра
+// означает «без ограничения», Area — только события внутри зоны.
+type WebhookSubscription struct {
+	ID         uuid.UUID          `json:"id"`
+	URL        string             `json:"url"`
+	Secret     string             `json:"-"`
+	EventTypes []WebhookEventType `json:"event_types"`
+	Categories []IncidentCategory `json:"categories"`
+	Severities []IncidentSeverity `json:"severities"`
+	Area       *Geometry          `json:"area,omitempty"`
+	Active     bool               `json:"active"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+
+	// PreviousSecret — секрет до ротации, действует до PreviousSecretExpiresAt
+	// (nil — пока его не уберут явно).
+	PreviousSecret          string     `json:"-"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+}
+
+// SigningSecrets — секреты, которыми подписывается доставка в момент now.
+func (s WebhookSubscription) SigningSecrets(now time.Time) []string {
+	secrets := make([]string, 0, 2)
+	if s.Secret != "" {
+		secrets = append(secrets, s.Secret)
+	}
+	if s.PreviousSecret != "" && (s.PreviousSecretExpiresAt == nil || now.Before(*s.PreviousSecretExpiresAt)) {
+		secrets = append(secrets, s.PreviousSecret)
+	}
+	return secrets
+}
+
+// Match решает, нужно ли отправлять событие подписке. Для location.* в data
+// остаются только инциденты, прошедшие фильтр по категории и важности;
+// событие инцидента проходит, если сам инцидент подходит под фильтры.
+func (s WebhookSubscription) Match(ev WebhookEvent) (WebhookEvent, bool) {
+	if !s.Active {
+		return ev, false
+	}
+	if len(s.EventTypes) > 0 && !slices.Contains(s.EventTypes, ev.Type) {
+		return ev, false
+	}
+	if len(s.Categories) == 0 && len(s.Severities) == 0 && s.Area == nil {
+		return ev, true
+	}
+
+	switch {
+	case ev.Type.IsLocation():
+		var p WebhookPayload
+		if err := json.Unmarshal(ev.Data, &p); err != nil {
+			return ev, false
+		}
+		p, ok := s.matchHit(p)
+		if !ok {
+			return ev, false
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			return ev, false
+		}
+		ev.Data = data
+		return ev, true
+	case ev.Type.IsIncident():
+		var inc Incident
+		if err := json.Unmarshal(ev.Data, &inc); err != nil {
+			return ev, false
+		}
+		return ev, s.matchIncident(inc.Category, inc.Severity) && (s.Area == nil || s.Area.Contains(inc.Lat, inc.Lng))
+	case ev.Type.IsOccupancy():
+		var p OccupancyPayload
+		if err := json.Unmarshal(ev.Data, &p); err != nil {
+			return ev, false
+		}
+		// Выход без точки (инцидент закрыт) парный ко входу, который уже прошёл
+		// фильтр по области, поэтому область его не отсекает.
+		inArea := s.Area == nil || p.Lat == nil || p.Lng == nil || s.Area.Contains(*p.Lat, *p.Lng)
+		return ev, s.matchIncident(p.Incident.Category, p.Incident.Severity) && inArea
+	}
+	return ev, false
+}
+
+func (s WebhookSubscription) matchHit(p WebhookPayload) (WebhookPayload, bool) {
+	if s.Area != nil && !s.Area.Contains(p.Lat, p.Lng) {
+		return p, false
+	}
+
+	incidents := make([]IncidentSummary, 0, len(p.Incidents))
+	for _, inc := range p.Incidents {
+		if s.matchIncident(inc.Category, inc.Severity) {
+			incidents = append(incidents, inc)
+		}
+	}
+	if len(incidents) == 0 {
+		return p, false
+	}
+	p.Incidents = incidents
+	return p, true
+}
+
+func (s WebhookSubscription) matchIncident(c IncidentCategory, sv IncidentSeverity) bool {
+	if len(s.Categories) > 0 && !slices.Contains(s.Categories, c) {
+		return false
+	}
+	if len(s.Severities) > 0 && !slices.Contains(s.Severities, sv) {
+		return false
+	}
+	return true
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	URL        string             `json:"url" validate:"required,url,max=2048"`
+	Secret     string             `json:"secret" validate:"omitempty,min=16,max=256"`
+	EventTypes []WebhookEventType `json:"event_types" validate:"dive,oneof=location.hit location.exited user.entered_incident user.exited_incident incident.created incident.updated incident.deactivated incident.expired"`
+	Categories []IncidentCategory `json:"categories" validate:"dive,oneof=fire flood gas_leak road_closure industrial public_safety weather other"`
+	Severities []IncidentSeverity `json:"severities" validate:"dive,oneof=low medium high critical"`
+	Area       *Geometry          `json:"area,omitempty"`
+	Active     *bool              `json:"active"`
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	URL        *string             `json:"url" validate:"omitempty,url,max=2048"`
+	Secret     *string             `json:"secret" validate:"omitempty,min=16,max=256"`
+	EventTypes *[]WebhookEventType `json:"event_types" validate:"omitempty,dive,oneof=location.hit location.exited user.entered_incident user.exited_incident incident.created incident.updated incident.deactivated incident.expired"`
+	Categories *[]IncidentCategory `json:"categories" validate:"omitempty,dive,oneof=fire flood gas_leak road_closure industrial public_safety weather other"`
+	Severities *[]IncidentSeverity `json:"severities" validate:"omitempty,dive,oneof=low medium high critical"`
+	Area       *Geometry           `json:"area,omitempty"`
+	ClearArea  bool                `json:"clear_area"`
+	Active     *bool               `json:"active"`
+}
+
+// CreateWebhookSubscriptionResponse — секрет отдаём один раз, при создании.
+type CreateWebhookSubscriptionResponse struct {
+	ID     uuid.UUID `json:"id"`
+	Secret string    `json:"secret"`
+}
+
+// RotateWebhookSecretRequest — пустой Secret означает «сгенерировать».
+type RotateWebhookSecretRequest struct {
+	Secret string `json:"secret" validate:"omitempty,min=16,max=256"`
+}
+
+type RotateWebhookSecretResponse struct {
+	ID                      uuid.UUID `json:"id"`
+	Secret                  string    `json:"secret"`
+	PreviousSecretExpiresAt time.Time `json:"previous_secret_expires_at"`
+}
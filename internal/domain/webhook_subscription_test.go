@@ -0,0 +1,217 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"redCollar/internal/domain"
+)
+
+func TestWebhookSubscription_Match(t *testing.T) {
+	t.Parallel()
+
+	fire := domain.IncidentSummary{Category: domain.CategoryFire, Severity: domain.SeverityHigh}
+	flood := domain.IncidentSummary{Category: domain.CategoryFlood, Severity: domain.SeverityLow}
+	payload := domain.WebhookPayload{Lat: 55.5, Lng: 37.2, Incidents: []domain.IncidentSummary{fire, flood}}
+
+	tests := []struct {
+		name      string
+		sub       domain.WebhookSubscription
+		event     domain.WebhookEventType
+		wantOK    bool
+		wantCount int
+	}{
+		{
+			name:      "no filters",
+			sub:       domain.WebhookSubscription{Active: true},
+			event:     domain.WebhookEventLocationHit,
+			wantOK:    true,
+			wantCount: 2,
+		},
+		{
+			name:  "inactive",
+			sub:   domain.WebhookSubscription{Active: false},
+			event: domain.WebhookEventLocationHit,
+		},
+		{
+			name:  "other event type",
+			sub:   domain.WebhookSubscription{Active: true, EventTypes: []domain.WebhookEventType{"incident.created"}},
+			event: domain.WebhookEventLocationHit,
+		},
+		{
+			name:      "category filter trims incidents",
+			sub:       domain.WebhookSubscription{Active: true, Categories: []domain.IncidentCategory{domain.CategoryFlood}},
+			event:     domain.WebhookEventLocationHit,
+			wantOK:    true,
+			wantCount: 1,
+		},
+		{
+			name:  "severity filter without matches",
+			sub:   domain.WebhookSubscription{Active: true, Severities: []domain.IncidentSeverity{domain.SeverityCritical}},
+			event: domain.WebhookEventLocationHit,
+		},
+		{
+			name:      "inside area",
+			sub:       domain.WebhookSubscription{Active: true, Area: mustGeometry(t, squareWithHole)},
+			event:     domain.WebhookEventLocationHit,
+			wantOK:    true,
+			wantCount: 2,
+		},
+		{
+			name:      "exit event filtered like hit",
+			sub:       domain.WebhookSubscription{Active: true, Categories: []domain.IncidentCategory{domain.CategoryFire}},
+			event:     domain.WebhookEventLocationExited,
+			wantOK:    true,
+			wantCount: 1,
+		},
+		{
+			name:  "hit only subscription skips exit",
+			sub:   domain.WebhookSubscription{Active: true, EventTypes: []domain.WebhookEventType{domain.WebhookEventLocationHit}},
+			event: domain.WebhookEventLocationExited,
+		},
+		{
+			name:  "in area hole",
+			sub:   domain.WebhookSubscription{Active: true, Area: mustGeometry(t, `{"type":"Polygon","coordinates":[[[37.4,55.4],[37.6,55.4],[37.6,55.6],[37.4,55.6],[37.4,55.4]]]}`)},
+			event: domain.WebhookEventLocationHit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, err := domain.NewWebhookEvent(tt.event, time.Now(), payload)
+			if err != nil {
+				t.Fatalf("NewWebhookEvent: %v", err)
+			}
+
+			got, ok := tt.sub.Match(ev)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v got=%v", tt.wantOK, ok)
+			}
+			if !ok {
+				return
+			}
+			if got.ID != ev.ID || got.Type != ev.Type {
+				t.Fatalf("envelope must be kept: %+v", got)
+			}
+			var p domain.WebhookPayload
+			if err := json.Unmarshal(got.Data, &p); err != nil {
+				t.Fatalf("unmarshal data: %v", err)
+			}
+			if len(p.Incidents) != tt.wantCount {
+				t.Fatalf("expected %d incidents got=%d", tt.wantCount, len(p.Incidents))
+			}
+		})
+	}
+
+	if len(payload.Incidents) != 2 {
+		t.Fatalf("payload must not be modified, got %d incidents", len(payload.Incidents))
+	}
+}
+
+func TestWebhookSubscription_Match_IncidentEvent(t *testing.T) {
+	t.Parallel()
+
+	inc := domain.Incident{Category: domain.CategoryFire, Severity: domain.SeverityHigh, Lat: 55.5, Lng: 37.2}
+	ev, err := domain.NewWebhookEvent(domain.WebhookEventIncidentExpired, time.Now(), inc)
+	if err != nil {
+		t.Fatalf("NewWebhookEvent: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		sub    domain.WebhookSubscription
+		wantOK bool
+	}{
+		{
+			name:   "no filters",
+			sub:    domain.WebhookSubscription{Active: true},
+			wantOK: true,
+		},
+		{
+			name: "only location hits",
+			sub:  domain.WebhookSubscription{Active: true, EventTypes: []domain.WebhookEventType{domain.WebhookEventLocationHit}},
+		},
+		{
+			name:   "matching category",
+			sub:    domain.WebhookSubscription{Active: true, Categories: []domain.IncidentCategory{domain.CategoryFire}},
+			wantOK: true,
+		},
+		{
+			name: "other severity",
+			sub:  domain.WebhookSubscription{Active: true, Severities: []domain.IncidentSeverity{domain.SeverityLow}},
+		},
+		{
+			name:   "inside area",
+			sub:    domain.WebhookSubscription{Active: true, Area: mustGeometry(t, squareWithHole)},
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.sub.Match(ev); ok != tt.wantOK {
+				t.Fatalf("expected ok=%v got=%v", tt.wantOK, ok)
+			}
+		})
+	}
+}
+
+func TestWebhookSubscription_Match_OccupancyEvent(t *testing.T) {
+	t.Parallel()
+
+	inc := domain.IncidentSummary{Category: domain.CategoryFire, Severity: domain.SeverityHigh}
+	lat, lng, farLat, farLng := 55.5, 37.2, 0.0, 0.0
+	sub := domain.WebhookSubscription{Active: true, Area: mustGeometry(t, squareWithHole)}
+
+	tests := []struct {
+		name   string
+		tr     domain.OccupancyTransition
+		wantOK bool
+	}{
+		{
+			name:   "check inside area",
+			tr:     domain.OccupancyTransition{Kind: domain.OccupancyExited, Reason: domain.TransitionCheck, Lat: &lat, Lng: &lng},
+			wantOK: true,
+		},
+		{
+			name: "check outside area",
+			tr:   domain.OccupancyTransition{Kind: domain.OccupancyExited, Reason: domain.TransitionCheck, Lat: &farLat, Lng: &farLng},
+		},
+		{
+			name:   "incident closed without position",
+			tr:     domain.OccupancyTransition{Kind: domain.OccupancyExited, Reason: domain.TransitionIncidentClosed},
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, err := tt.tr.Event(inc)
+			if err != nil {
+				t.Fatalf("Event: %v", err)
+			}
+			if _, ok := sub.Match(ev); ok != tt.wantOK {
+				t.Fatalf("expected ok=%v got=%v", tt.wantOK, ok)
+			}
+		})
+	}
+}
+
+func TestOccupancyTransition_Event_ClosedHasNoPosition(t *testing.T) {
+	t.Parallel()
+
+	tr := domain.OccupancyTransition{Kind: domain.OccupancyExited, Reason: domain.TransitionIncidentClosed, At: time.Now()}
+	ev, err := tr.Event(domain.IncidentSummary{})
+	if err != nil {
+		t.Fatalf("Event: %v", err)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(ev.Data, &data); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if data["reason"] != string(domain.TransitionIncidentClosed) || data["lat"] != nil || data["lng"] != nil {
+		t.Fatalf("unexpected payload: %s", ev.Data)
+	}
+}
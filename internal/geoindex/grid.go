@@ -0,0 +1,183 @@
+package geoindex
+
+import (
+	"math"
+
+	"redCollar/internal/domain"
+)
+
+const (
+	earthRadiusKm = 6371.0
+
+	// DefaultCellDeg — сторона ячейки сетки, ~5.5 км по широте.
+	DefaultCellDeg = 0.05
+	// maxCellsPerIncident — зоны крупнее не раскладываются по ячейкам,
+	// а лежат в общем списке, который просматривается на каждом запросе.
+	maxCellsPerIncident = 256
+)
+
+// Box — рамка [MinLng, MinLat, MaxLng, MaxLat] в градусах. У зоны возле
+// антимеридиана долгота рамки может выходить за ±180: проверки сравнивают
+// долготы по модулю 360.
+type Box struct {
+	MinLng, MinLat, MaxLng, MaxLat float64
+}
+
+// BoxOf — рамка зоны инцидента (полигон или круг radius_km вокруг точки),
+// расширенная на marginKm.
+func BoxOf(inc domain.CachedIncident, marginKm float64) Box {
+	var b Box
+	if inc.Geometry != nil {
+		b = Box{180, 90, -180, -90}
+		for _, poly := range inc.Geometry.Polygons {
+			if len(poly) == 0 {
+				continue
+			}
+			for _, p := range poly[0] {
+				b.MinLng, b.MinLat = math.Min(b.MinLng, p[0]), math.Min(b.MinLat, p[1])
+				b.MaxLng, b.MaxLat = math.Max(b.MaxLng, p[0]), math.Max(b.MaxLat, p[1])
+			}
+		}
+	} else {
+		b = Box{inc.Lng, inc.Lat, inc.Lng, inc.Lat}
+		marginKm += inc.RadiusKM
+	}
+	return b.Expand(marginKm)
+}
+
+// Expand расширяет рамку на km во все стороны.
+func (b Box) Expand(km float64) Box {
+	if km <= 0 {
+		return b
+	}
+	dLat := km / earthRadiusKm * 180 / math.Pi
+	lat := math.Max(math.Abs(b.MinLat), math.Abs(b.MaxLat))
+	dLng := dLat / math.Max(math.Cos(lat*math.Pi/180), 0.01)
+	return Box{b.MinLng - dLng, b.MinLat - dLat, b.MaxLng + dLng, b.MaxLat + dLat}
+}
+
+// lngShifts — сдвиги долготы, под которыми одна и та же точка может
+// оказаться в рамке, вылезшей за антимеридиан.
+var lngShifts = [...]float64{0, -360, 360}
+
+func (b Box) Contains(lat, lng float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	for _, s := range lngShifts {
+		if l := lng + s; l >= b.MinLng && l <= b.MaxLng {
+			return true
+		}
+	}
+	return false
+}
+
+func (b Box) Overlaps(o Box) bool {
+	if b.MinLat > o.MaxLat || o.MinLat > b.MaxLat {
+		return false
+	}
+	for _, s := range lngShifts {
+		if b.MinLng <= o.MaxLng+s && o.MinLng+s <= b.MaxLng {
+			return true
+		}
+	}
+	return false
+}
+
+type cell struct{ x, y int32 }
+
+// Grid — неизменяемый индекс инцидентов на равномерной сетке по широте и
+// долготе. Инцидент лежит во всех ячейках, которые задевает его рамка с
+// запасом margin, так что кандидаты для точки — содержимое одной ячейки.
+// Кандидат — не попадание: точную проверку делает вызывающий. Столбцы
+// ячеек замкнуты по долготе, так что рамка за антимеридианом продолжается
+// с другой стороны.
+type Grid struct {
+	cellDeg   float64
+	cols      int32
+	incidents []domain.CachedIncident
+	boxes     []Box
+	cells     map[cell][]int32
+	wide      []int32
+}
+
+// New строит индекс. marginKm — насколько дальше своей зоны инцидент
+// должен находиться запросом, например буфер приближения.
+func New(incidents []domain.CachedIncident, cellDeg, marginKm float64) *Grid {
+	if cellDeg <= 0 {
+		cellDeg = DefaultCellDeg
+	}
+	// Целое число столбцов на 360°, иначе замыкание по долготе не сойдётся.
+	cols := int32(math.Max(math.Round(360/cellDeg), 1))
+	g := &Grid{
+		cellDeg:   360 / float64(cols),
+		cols:      cols,
+		incidents: incidents,
+		boxes:     make([]Box, len(incidents)),
+		cells:     make(map[cell][]int32),
+	}
+
+	for i, inc := range incidents {
+		b := BoxOf(inc, marginKm)
+		g.boxes[i] = b
+
+		lo, hi := g.cellOf(b.MinLat, b.MinLng), g.cellOf(b.MaxLat, b.MaxLng)
+		if (int64(hi.x)-int64(lo.x)+1)*(int64(hi.y)-int64(lo.y)+1) > maxCellsPerIncident {
+			g.wide = append(g.wide, int32(i))
+			continue
+		}
+		if hi.x-lo.x+1 >= g.cols {
+			lo.x, hi.x = 0, g.cols-1
+		}
+		for x := lo.x; x <= hi.x; x++ {
+			for y := lo.y; y <= hi.y; y++ {
+				c := cell{g.wrap(x), y}
+				g.cells[c] = append(g.cells[c], int32(i))
+			}
+		}
+	}
+	return g
+}
+
+// cellOf — ячейка без замыкания: x рамки за антимеридианом выходит за [0, cols).
+func (g *Grid) cellOf(lat, lng float64) cell {
+	return cell{
+		x: int32(math.Floor((lng + 180) / g.cellDeg)),
+		y: int32(math.Floor((lat + 90) / g.cellDeg)),
+	}
+}
+
+func (g *Grid) wrap(x int32) int32 {
+	x %= g.cols
+	if x < 0 {
+		x += g.cols
+	}
+	return x
+}
+
+// Candidates — инциденты, в чью рамку с запасом попадает точка.
+func (g *Grid) Candidates(lat, lng float64) []domain.CachedIncident {
+	var out []domain.CachedIncident
+	c := g.cellOf(lat, lng)
+	c.x = g.wrap(c.x)
+	for _, i := range g.cells[c] {
+		if g.boxes[i].Contains(lat, lng) {
+			out = append(out, g.incidents[i])
+		}
+	}
+	for _, i := range g.wide {
+		if g.boxes[i].Contains(lat, lng) {
+			out = append(out, g.incidents[i])
+		}
+	}
+	return out
+}
+
+// Incidents — все проиндексированные инциденты.
+func (g *Grid) Incidents() []domain.CachedIncident {
+	return g.incidents
+}
+
+func (g *Grid) Len() int {
+	return len(g.incidents)
+}
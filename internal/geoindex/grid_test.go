@@ -0,0 +1,211 @@
+package geoindex
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"redCollar/internal/domain"
+)
+
+// randomIncidents — круги по Москве и области плюс каждый сотый — полигон.
+func randomIncidents(r *rand.Rand, n int) []domain.CachedIncident {
+	out := make([]domain.CachedIncident, n)
+	for i := range out {
+		lat, lng := 55+r.Float64()*1.5, 36.5+r.Float64()*2.5
+		inc := domain.CachedIncident{ID: uuid.New(), Lat: lat, Lng: lng, RadiusKM: 0.1 + r.Float64()*2}
+		if i%100 == 0 {
+			d := 0.01 + r.Float64()*0.05
+			inc.Geometry = &domain.Geometry{Type: domain.GeometryPolygon, Polygons: []domain.Polygon{{
+				{{lng - d, lat - d}, {lng + d, lat - d}, {lng + d, lat + d}, {lng - d, lat + d}, {lng - d, lat - d}},
+			}}}
+		}
+		out[i] = inc
+	}
+	return out
+}
+
+// antimeridianIncidents — круги по обе стороны от ±180°.
+func antimeridianIncidents(r *rand.Rand, n int) []domain.CachedIncident {
+	out := make([]domain.CachedIncident, n)
+	for i := range out {
+		out[i] = domain.CachedIncident{ID: uuid.New(), Lat: -60 + r.Float64()*120, Lng: wrapLng(179.5 + r.Float64()), RadiusKM: 0.1 + r.Float64()*10}
+	}
+	return out
+}
+
+func wrapLng(lng float64) float64 {
+	if lng > 180 {
+		return lng - 360
+	}
+	return lng
+}
+
+func TestGrid_CandidatesMatchLinearScan(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	moscow := randomIncidents(r, 2000)
+	// Зона шире maxCellsPerIncident ячеек уходит в общий список.
+	moscow = append(moscow, domain.CachedIncident{ID: uuid.New(), Lat: 55.75, Lng: 37.61, RadiusKM: 100})
+
+	antimeridian := antimeridianIncidents(r, 2000)
+	antimeridian = append(antimeridian, domain.CachedIncident{ID: uuid.New(), Lat: 10, Lng: 179.99, RadiusKM: 5})
+
+	tests := []struct {
+		name      string
+		incidents []domain.CachedIncident
+		point     func() (lat, lng float64)
+		wide      bool
+	}{
+		{
+			name:      "moscow",
+			incidents: moscow,
+			point:     func() (float64, float64) { return 54.8 + r.Float64()*2, 36.3 + r.Float64()*3 },
+			wide:      true,
+		},
+		{
+			name:      "antimeridian",
+			incidents: antimeridian,
+			point:     func() (float64, float64) { return -60 + r.Float64()*120, wrapLng(179.4 + r.Float64()*1.2) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(tt.incidents, DefaultCellDeg, 0.5)
+			if tt.wide && len(g.wide) == 0 {
+				t.Fatalf("expected wide incidents")
+			}
+
+			points := make([][2]float64, 0, 1000)
+			for range cap(points) {
+				lat, lng := tt.point()
+				points = append(points, [2]float64{lat, lng})
+			}
+			if tt.name == "antimeridian" {
+				// Центр в 179.99, точка за антимеридианом в ~2 км.
+				points = append(points, [2]float64{10, -179.98})
+			}
+
+			for _, p := range points {
+				lat, lng := p[0], p[1]
+				want := map[uuid.UUID]bool{}
+				for _, inc := range tt.incidents {
+					if BoxOf(inc, 0.5).Contains(lat, lng) {
+						want[inc.ID] = true
+					}
+				}
+				got := map[uuid.UUID]bool{}
+				for _, inc := range g.Candidates(lat, lng) {
+					if !want[inc.ID] {
+						t.Fatalf("point (%v, %v): unexpected candidate %v", lat, lng, inc.ID)
+					}
+					got[inc.ID] = true
+				}
+				if len(got) != len(want) {
+					t.Fatalf("point (%v, %v): expected %d candidates got %d", lat, lng, len(want), len(got))
+				}
+				// Всё, что находил полный проход с haversine, должно быть среди кандидатов.
+				for _, inc := range tt.incidents {
+					if haversine(lat, lng, inc.Lat, inc.Lng) <= inc.RadiusKM+0.5 && !got[inc.ID] {
+						t.Fatalf("point (%v, %v): missed incident at (%v, %v) r=%v", lat, lng, inc.Lat, inc.Lng, inc.RadiusKM)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBoxOf_CircleCoversRadiusAndMargin(t *testing.T) {
+	inc := domain.CachedIncident{Lat: 55.75, Lng: 37.61, RadiusKM: 1}
+	b := BoxOf(inc, 0.5)
+
+	// 1.5 км к северу и к востоку — на границе рамки.
+	dLat := 1.49 / earthRadiusKm * 180 / math.Pi
+	dLng := dLat / math.Cos(55.75*math.Pi/180)
+	if !b.Contains(55.75+dLat, 37.61) || !b.Contains(55.75, 37.61+dLng) {
+		t.Fatalf("box %+v does not cover radius+margin", b)
+	}
+	if b.Contains(55.75+2*dLat, 37.61) {
+		t.Fatalf("box %+v is too wide", b)
+	}
+}
+
+func TestBox_WrapsAntimeridian(t *testing.T) {
+	b := BoxOf(domain.CachedIncident{Lat: 10, Lng: 179.99, RadiusKM: 5}, 0)
+	if b.MaxLng <= 180 {
+		t.Fatalf("expected box past the antimeridian, got %+v", b)
+	}
+	if !b.Contains(10, -179.98) || b.Contains(10, -179.5) {
+		t.Fatalf("box %+v does not wrap longitude", b)
+	}
+	// Отрезок маршрута по другую сторону ±180°, как в intersectRoute.
+	if !b.Overlaps(Box{MinLng: -179.99, MinLat: 9.99, MaxLng: -179.97, MaxLat: 10.01}) {
+		t.Fatalf("box %+v does not overlap segment past the antimeridian", b)
+	}
+}
+
+func TestGrid_Empty(t *testing.T) {
+	g := New(nil, 0, 0)
+	if g.Len() != 0 || len(g.Candidates(55.75, 37.61)) != 0 {
+		t.Fatalf("expected empty grid")
+	}
+}
+
+// BenchmarkCandidates сравнивает выборку кандидатов по сетке с полным
+// проходом по списку с haversine, как было до индекса.
+func BenchmarkCandidates(b *testing.B) {
+	for _, n := range []int{10_000, 100_000} {
+		r := rand.New(rand.NewSource(1))
+		incidents := randomIncidents(r, n)
+		g := New(incidents, DefaultCellDeg, 0.5)
+		points := make([][2]float64, 1024)
+		for i := range points {
+			points[i] = [2]float64{55 + r.Float64()*1.5, 36.5 + r.Float64()*2.5}
+		}
+
+		b.Run(fmt.Sprintf("grid/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				p := points[i%len(points)]
+				_ = g.Candidates(p[0], p[1])
+			}
+		})
+		b.Run(fmt.Sprintf("linear/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				p := points[i%len(points)]
+				var out []domain.CachedIncident
+				for _, inc := range incidents {
+					if haversine(p[0], p[1], inc.Lat, inc.Lng) <= inc.RadiusKM+0.5 {
+						out = append(out, inc)
+					}
+				}
+				_ = out
+			}
+		})
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	for _, n := range []int{10_000, 100_000} {
+		incidents := randomIncidents(rand.New(rand.NewSource(1)), n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = New(incidents, DefaultCellDeg, 0.5)
+			}
+		})
+	}
+}
+
+func haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLng := (lng2 - lng1) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"redCollar/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// AlertTracker помнит, о каких инцидентах пользователь уже получил алерт.
+//
+//	<prefix>:<user_id> — hash incident_id → "<notified_at_ms>:<inside>"
+//
+// Ключ живёт Window с последней проверки: пользователь, пропавший дольше
+// окна, при следующем попадании получит алерт заново, а выход из зоны для
+// него не фиксируется.
+type AlertTracker struct {
+	client *redis.Client
+	prefix string
+	policy domain.AlertPolicy
+}
+
+func NewAlertTracker(client *redis.Client, prefix string, policy domain.AlertPolicy) *AlertTracker {
+	return &AlertTracker{client: client, prefix: prefix, policy: policy}
+}
+
+// trackScript сверяет попадания с состоянием пользователя одной операцией.
+// Вход — нет записи, или окно истекло и пользователь вышел из зоны
+// (или включён renotify). Выход — запись «внутри», а попадания нет;
+// вышедшие записи хранятся до конца окна, чтобы дребезг на границе зоны
+// не давал повторных алертов.
+//
+// ARGV: now_ms, window_ms, renotify (0/1), затем id текущих попаданий.
+var trackScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local renotify = ARGV[3] == '1'
+
+local hits = {}
+local entered = {}
+for i = 4, #ARGV do
+  local id = ARGV[i]
+  hits[id] = true
+  local notified, inside = nil, '0'
+  local v = redis.call('HGET', KEYS[1], id)
+  if v then
+    local n, s = string.match(v, '^(%d+):(%d)$')
+    if n then
+      notified, inside = tonumber(n), s
+    end
+  end
+  if notified == nil or (now - notified >= window and (renotify or inside == '0')) then
+    table.insert(entered, id)
+    notified = now
+  end
+  redis.call('HSET', KEYS[1], id, string.format('%d:1', notified))
+end
+
+local exited = {}
+local all = redis.call('HGETALL', KEYS[1])
+for i = 1, #all, 2 do
+  local id = all[i]
+  if not hits[id] then
+    local n, s = string.match(all[i + 1], '^(%d+):(%d)$')
+    if s == '1' then
+      table.insert(exited, id)
+      redis.call('HSET', KEYS[1], id, n .. ':0')
+    elseif n == nil or now - tonumber(n) >= window then
+      redis.call('HDEL', KEYS[1], id)
+    end
+  end
+end
+
+redis.call('PEXPIRE', KEYS[1], window)
+return {entered, exited}
+`)
+
+// Track делит попадания проверки на новые и известные и возвращает зоны,
+// из которых пользователь вышел (только при NotifyExit). С нулевым окном
+// каждое попадание — вход, Redis не трогается.
+func (t *AlertTracker) Track(ctx context.Context, userID string, hits []uuid.UUID, now time.Time) (domain.AlertTransitions, error) {
+	if t.policy.Window <= 0 {
+		return domain.AlertTransitions{Entered: hits}, nil
+	}
+
+	renotify := "0"
+	if t.policy.Renotify {
+		renotify = "1"
+	}
+	args := make([]any, 0, len(hits)+3)
+	args = append(args, now.UnixMilli(), t.policy.Window.Milliseconds(), renotify)
+	for _, id := range hits {
+		args = append(args, id.String())
+	}
+
+	res, err := trackScript.Run(ctx, t.client, []string{t.prefix + ":" + userID}, args...).Slice()
+	if err != nil {
+		return domain.AlertTransitions{}, err
+	}
+	if len(res) != 2 {
+		return domain.AlertTransitions{}, fmt.Errorf("alert tracker: unexpected reply %v", res)
+	}
+
+	entered, err := parseIDs(res[0])
+	if err != nil {
+		return domain.AlertTransitions{}, err
+	}
+
+	var tr domain.AlertTransitions
+	isNew := make(map[uuid.UUID]bool, len(entered))
+	for _, id := range entered {
+		isNew[id] = true
+	}
+	for _, id := range hits {
+		if isNew[id] {
+			tr.Entered = append(tr.Entered, id)
+		} else {
+			tr.Known = append(tr.Known, id)
+		}
+	}
+
+	if t.policy.NotifyExit {
+		if tr.Exited, err = parseIDs(res[1]); err != nil {
+			return domain.AlertTransitions{}, err
+		}
+	}
+
+	return tr, nil
+}
+
+// revertScript откатывает то, что записал Track для проверки в now_ms:
+// вход снова становится неизвестным, выход — пребыванием внутри. Запись,
+// которую после этого успела переписать другая проверка, не трогается.
+//
+// ARGV: now_ms, число входов, id входов, затем id выходов.
+var revertScript = redis.NewScript(`
+local now = ARGV[1]
+local n = tonumber(ARGV[2])
+for i = 3, 2 + n do
+  local v = redis.call('HGET', KEYS[1], ARGV[i])
+  if v and string.match(v, '^(%d+):') == now then
+    redis.call('HDEL', KEYS[1], ARGV[i])
+  end
+end
+for i = 3 + n, #ARGV do
+  local v = redis.call('HGET', KEYS[1], ARGV[i])
+  if v then
+    local m = string.match(v, '^(%d+):0$')
+    if m then
+      redis.call('HSET', KEYS[1], ARGV[i], m .. ':1')
+    end
+  end
+end
+return 0
+`)
+
+// Revert откатывает переходы tr, записанные Track в now, — когда проверку
+// не удалось сохранить. Следующая проверка снова увидит эти входы и выходы.
+func (t *AlertTracker) Revert(ctx context.Context, userID string, tr domain.AlertTransitions, now time.Time) error {
+	if t.policy.Window <= 0 || len(tr.Entered)+len(tr.Exited) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(tr.Entered)+len(tr.Exited)+2)
+	args = append(args, now.UnixMilli(), len(tr.Entered))
+	for _, id := range tr.Entered {
+		args = append(args, id.String())
+	}
+	for _, id := range tr.Exited {
+		args = append(args, id.String())
+	}
+	return revertScript.Run(ctx, t.client, []string{t.prefix + ":" + userID}, args...).Err()
+}
+
+func parseIDs(v any) ([]uuid.UUID, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("alert tracker: unexpected reply %v", v)
+	}
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, it := range items {
+		s, ok := it.(string)
+		if !ok {
+			return nil, fmt.Errorf("alert tracker: unexpected id %v", it)
+		}
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"time"
 
 	"redCollar/internal/domain"
@@ -16,15 +17,20 @@ type IncidentCacheService interface {
 	SetActive(ctx context.Context, incidents []domain.CachedIncident, ttl time.Duration) error
 }
 
+// IncidentCache хранит активные инциденты одним JSON и рядом — версию,
+// которая меняется при каждой записи. По версии процессы понимают, что их
+// локальная копия устарела, не скачивая сам список.
 type IncidentCache struct {
-	client *goredis.Client
-	key    string
+	client     *goredis.Client
+	key        string
+	versionKey string
 }
 
 func NewIncidentCache(r *Redis) *IncidentCache {
 	return &IncidentCache{
-		client: r.Client,
-		key:    "incidents:active",
+		client:     r.Client,
+		key:        "incidents:active",
+		versionKey: "incidents:active:version",
 	}
 }
 
@@ -45,10 +51,46 @@ func (c *IncidentCache) GetActive(ctx context.Context) ([]domain.CachedIncident,
 	return incidents, nil
 }
 
+// Version — текущая версия списка; "" если списка в кэше нет.
+func (c *IncidentCache) Version(ctx context.Context) (string, error) {
+	v, err := c.client.Get(ctx, c.versionKey).Result()
+	if errors.Is(err, goredis.Nil) {
+		return "", nil
+	}
+	return v, err
+}
+
+// GetActiveVersion читает список вместе с версией одним MGET, чтобы версия
+// точно соответствовала данным.
+func (c *IncidentCache) GetActiveVersion(ctx context.Context) ([]domain.CachedIncident, string, error) {
+	vals, err := c.client.MGet(ctx, c.key, c.versionKey).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, _ := vals[0].(string)
+	version, _ := vals[1].(string)
+	if data == "" {
+		return nil, version, nil
+	}
+
+	var incidents []domain.CachedIncident
+	if err := json.Unmarshal([]byte(data), &incidents); err != nil {
+		return nil, "", err
+	}
+	return incidents, version, nil
+}
+
 func (c *IncidentCache) SetActive(ctx context.Context, incidents []domain.CachedIncident, ttl time.Duration) error {
 	b, err := json.Marshal(incidents)
 	if err != nil {
 		return err
 	}
-	return c.client.Set(ctx, c.key, b, ttl).Err()
+	version := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	pipe := c.client.TxPipeline()
+	pipe.Set(ctx, c.key, b, ttl)
+	pipe.Set(ctx, c.versionKey, version, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
 }
@@ -5,46 +5,416 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"redCollar/pkg/e"
 	"time"
 
 	"redCollar/internal/domain"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
+// WebhookQueue — очередь доставки вебхуков с гарантией at-least-once.
+// Взятое сообщение атомарно переезжает в processing-список и удаляется
+// оттуда только после успеха, повтора или переноса в dead letters.
+//
+//	<key>                  — очередь (LPUSH → BLMOVE RIGHT)
+//	<key>:processing       — сообщения в работе
+//	<key>:claims           — zset сообщений в работе по времени взятия
+//	<key>:delayed          — zset повторов по времени следующей попытки
+//	<key>:dead             — hash id → DeadLetter
+//	<key>:dead:index       — zset id по времени падения
 type WebhookQueue struct {
-	client *redis.Client
-	key    string
+	client     *redis.Client
+	key        string
+	processing string
+	claims     string
+	delayed    string
+	dead       string
+	deadIndex  string
+}
+
+// WebhookDelivery — взятое из очереди сообщение. raw нужен, чтобы
+// удалить из processing ровно тот элемент, который был взят.
+type WebhookDelivery struct {
+	domain.WebhookMessage
+	raw string
 }
 
 func NewWebhookQueue(client *redis.Client, key string) *WebhookQueue {
-	return &WebhookQueue{client: client, key: key}
+	return &WebhookQueue{
+		client:     client,
+		key:        key,
+		processing: key + ":processing",
+		claims:     key + ":claims",
+		delayed:    key + ":delayed",
+		dead:       key + ":dead",
+		deadIndex:  key + ":dead:index",
+	}
 }
 
+// Enqueue ставит в очередь событие location.hit.
 func (q *WebhookQueue) Enqueue(ctx context.Context, payload domain.WebhookPayload) error {
-	b, err := json.Marshal(payload)
+	ev, err := domain.NewWebhookEvent(domain.WebhookEventLocationHit, payload.CheckedAt, payload)
+	if err != nil {
+		return err
+	}
+	return q.Publish(ctx, ev)
+}
+
+// Publish ставит событие в очередь на рассылку по подпискам.
+func (q *WebhookQueue) Publish(ctx context.Context, ev domain.WebhookEvent) error {
+	b, err := json.Marshal(domain.WebhookMessage{
+		ID:         uuid.New(),
+		Envelope:   ev,
+		EnqueuedAt: time.Now().UTC(),
+	})
 	if err != nil {
 		return err
 	}
 	return q.client.LPush(ctx, q.key, b).Err()
 }
 
-func (q *WebhookQueue) BRPop(ctx context.Context, timeout time.Duration) (domain.WebhookPayload, error) {
-	var p domain.WebhookPayload
+// Claim блокирующе берёт следующее сообщение, кладёт его в processing и
+// отмечает время взятия для RecoverProcessing.
+func (q *WebhookQueue) Claim(ctx context.Context, timeout time.Duration) (WebhookDelivery, error) {
+	raw, err := q.client.BLMove(ctx, q.key, q.processing, "RIGHT", "LEFT", timeout).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return WebhookDelivery{}, e.ErrWebHookEmpty
+		}
+		return WebhookDelivery{}, err
+	}
+
+	d, err := decodeDelivery(raw)
+	if err != nil {
+		// Битое сообщение не доставить никогда — уносим в dead letters как есть,
+		// чтобы не крутилось вечно и осталось доступно для разбора.
+		err = fmt.Errorf("decode webhook message: %w", err)
+		if dlErr := q.bury(ctx, raw, undecodableDeadLetter(raw, err, time.Now())); dlErr != nil {
+			return WebhookDelivery{}, errors.Join(err, dlErr)
+		}
+		return WebhookDelivery{}, err
+	}
+
+	// Не отметили — сообщение всё равно не потеряется: RecoverProcessing
+	// начнёт отсчёт с первой встречи.
+	_ = q.client.ZAdd(ctx, q.claims, redis.Z{Score: float64(time.Now().UnixMilli()), Member: raw}).Err()
+	return d, nil
+}
+
+func (q *WebhookQueue) release(ctx context.Context, raw string) error {
+	_, err := q.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.LRem(ctx, q.processing, 1, raw)
+		p.ZRem(ctx, q.claims, raw)
+		return nil
+	})
+	return err
+}
+
+// legacyMessage — сообщение до появления конверта: тип и payload location.hit
+// лежали прямо в нём.
+type legacyMessage struct {
+	Event   domain.WebhookEventType `json:"event"`
+	EventID uuid.UUID               `json:"event_id"`
+	Payload domain.WebhookPayload   `json:"payload"`
+}
+
+// decodeDelivery понимает и старые форматы очереди: голый WebhookPayload
+// и сообщение без конверта.
+func decodeDelivery(raw string) (WebhookDelivery, error) {
+	d := WebhookDelivery{raw: raw}
+	if err := json.Unmarshal([]byte(raw), &d.WebhookMessage); err != nil {
+		return d, err
+	}
+	if d.Envelope.Type != "" {
+		return d, nil
+	}
+
+	var legacy legacyMessage
+	if d.ID == uuid.Nil {
+		if err := json.Unmarshal([]byte(raw), &legacy.Payload); err != nil {
+			return d, err
+		}
+		d.WebhookMessage = domain.WebhookMessage{ID: uuid.New(), EnqueuedAt: time.Now().UTC()}
+	} else if err := json.Unmarshal([]byte(raw), &legacy); err != nil {
+		return d, err
+	}
+
+	ev, err := domain.NewWebhookEvent(domain.WebhookEventLocationHit, legacy.Payload.CheckedAt, legacy.Payload)
+	if err != nil {
+		return d, err
+	}
+	if legacy.EventID != uuid.Nil {
+		ev.ID = legacy.EventID
+	}
+	d.Envelope = ev
+	return d, nil
+}
+
+// Ack — сообщение доставлено.
+func (q *WebhookQueue) Ack(ctx context.Context, d WebhookDelivery) error {
+	return q.release(ctx, d.raw)
+}
+
+// FanOut заменяет событие доставками по подпискам одной транзакцией:
+// либо событие ещё в processing, либо все доставки уже в очереди.
+func (q *WebhookQueue) FanOut(ctx context.Context, d WebhookDelivery, msgs []domain.WebhookMessage) error {
+	items := make([]any, 0, len(msgs))
+	for _, m := range msgs {
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		items = append(items, b)
+	}
+
+	_, err := q.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		if len(items) > 0 {
+			p.LPush(ctx, q.key, items...)
+		}
+		p.LRem(ctx, q.processing, 1, d.raw)
+		p.ZRem(ctx, q.claims, d.raw)
+		return nil
+	})
+	return err
+}
+
+// Retry откладывает сообщение до at с увеличенным счётчиком попыток.
+// В очередь его вернёт PromoteDue.
+func (q *WebhookQueue) Retry(ctx context.Context, d WebhookDelivery, at time.Time) error {
+	msg := d.WebhookMessage
+	msg.Attempts++
+	return q.delay(ctx, d, msg, at)
+}
+
+// Postpone откладывает сообщение до at, не тратя попытку: получатель
+// занят или поставлен на паузу.
+func (q *WebhookQueue) Postpone(ctx context.Context, d WebhookDelivery, at time.Time) error {
+	return q.delay(ctx, d, d.WebhookMessage, at)
+}
+
+func (q *WebhookQueue) delay(ctx context.Context, d WebhookDelivery, msg domain.WebhookMessage, at time.Time) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.ZAdd(ctx, q.delayed, redis.Z{Score: float64(at.UnixMilli()), Member: b})
+		p.LRem(ctx, q.processing, 1, d.raw)
+		p.ZRem(ctx, q.claims, d.raw)
+		return nil
+	})
+	return err
+}
+
+// promoteScript переносит до ARGV[2] созревших повторов в очередь; скрипт
+// атомарен, так что несколько планировщиков не продвинут одно сообщение дважды.
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, m in ipairs(due) do
+  redis.call('ZREM', KEYS[1], m)
+  redis.call('LPUSH', KEYS[2], m)
+end
+return #due
+`)
+
+// PromoteDue возвращает в очередь отложенные сообщения, время которых наступило.
+func (q *WebhookQueue) PromoteDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	return promoteScript.Run(ctx, q.client, []string{q.delayed, q.key}, now.UnixMilli(), limit).Int()
+}
+
+// DeadLetter переносит сообщение из processing в dead letters.
+func (q *WebhookQueue) DeadLetter(ctx context.Context, d WebhookDelivery, reason string, at time.Time) error {
+	dl := domain.DeadLetter{WebhookMessage: d.WebhookMessage, LastError: reason, FailedAt: at.UTC()}
+	dl.Attempts++
+	return q.bury(ctx, d.raw, dl)
+}
+
+// undecodableDeadLetter — dead letter для сообщения, которое не разобрать:
+// своего id у него может не быть, поэтому выдаём новый.
+func undecodableDeadLetter(raw string, err error, at time.Time) domain.DeadLetter {
+	return domain.DeadLetter{
+		WebhookMessage: domain.WebhookMessage{ID: uuid.New()},
+		LastError:      err.Error(),
+		FailedAt:       at.UTC(),
+		Raw:            raw,
+	}
+}
 
-	res, err := q.client.BRPop(ctx, timeout, q.key).Result()
+// bury кладёт dl в dead letters и убирает raw из processing одной транзакцией.
+func (q *WebhookQueue) bury(ctx context.Context, raw string, dl domain.DeadLetter) error {
+	b, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+
+	id := dl.ID.String()
+	_, err = q.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, q.dead, id, b)
+		p.ZAdd(ctx, q.deadIndex, redis.Z{Score: float64(dl.FailedAt.UnixMilli()), Member: id})
+		p.LRem(ctx, q.processing, 1, raw)
+		p.ZRem(ctx, q.claims, raw)
+		return nil
+	})
+	return err
+}
+
+// recoverScript возвращает в голову очереди сообщения из processing, взятые
+// раньше ARGV[2]. Сообщение без отметки о взятии (отправитель упал между
+// BLMOVE и ZADD) получает отметку ARGV[1] и вернётся через таймаут.
+var recoverScript = redis.NewScript(`
+local items = redis.call('LRANGE', KEYS[1], 0, -1)
+local n = 0
+for _, m in ipairs(items) do
+  local at = redis.call('ZSCORE', KEYS[2], m)
+  if not at then
+    redis.call('ZADD', KEYS[2], ARGV[1], m)
+  elseif tonumber(at) <= tonumber(ARGV[2]) then
+    redis.call('LREM', KEYS[1], 1, m)
+    redis.call('ZREM', KEYS[2], m)
+    redis.call('RPUSH', KEYS[3], m)
+    n = n + 1
+  end
+end
+return n
+`)
+
+// RecoverProcessing возвращает в очередь сообщения, которые пробыли в
+// processing дольше visibility: их отправитель упал, не завершив доставку.
+// Сообщения в работе у живых отправителей других инстансов не трогает.
+func (q *WebhookQueue) RecoverProcessing(ctx context.Context, now time.Time, visibility time.Duration) (int, error) {
+	return recoverScript.Run(ctx, q.client, []string{q.processing, q.claims, q.key},
+		now.UnixMilli(), now.Add(-visibility).UnixMilli()).Int()
+}
+
+func (q *WebhookQueue) ListDeadLetters(ctx context.Context, offset, limit int) ([]domain.DeadLetter, int64, error) {
+	total, err := q.client.ZCard(ctx, q.deadIndex).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids, err := q.client.ZRevRange(ctx, q.deadIndex, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(ids) == 0 {
+		return []domain.DeadLetter{}, total, nil
+	}
+
+	vals, err := q.client.HMGet(ctx, q.dead, ids...).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]domain.DeadLetter, 0, len(vals))
+	for _, v := range vals {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var dl domain.DeadLetter
+		if err := json.Unmarshal([]byte(s), &dl); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, dl)
+	}
+	return items, total, nil
+}
+
+func (q *WebhookQueue) GetDeadLetter(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error) {
+	b, err := q.client.HGet(ctx, q.dead, id.String()).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return p, e.ErrWebHookEmpty
+			return nil, e.ErrNotFound
 		}
-		return p, err
+		return nil, err
 	}
-	if len(res) < 2 {
-		return p, redis.Nil
+
+	var dl domain.DeadLetter
+	if err := json.Unmarshal(b, &dl); err != nil {
+		return nil, err
 	}
-	if err := json.Unmarshal([]byte(res[1]), &p); err != nil {
-		return p, err
+	return &dl, nil
+}
+
+// replayScript снимает dead letter и кладёт сообщение в начало выдачи
+// очереди одной операцией: при параллельных replay выигрывает один.
+var replayScript = redis.NewScript(`
+if redis.call('HDEL', KEYS[1], ARGV[1]) == 0 then
+  return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('RPUSH', KEYS[3], ARGV[2])
+return 1
+`)
+
+// replayMessage — сообщение для повторной отправки dead letter. Неразобранное
+// сообщение отправить нельзя: его можно только посмотреть и удалить.
+func replayMessage(dl domain.DeadLetter, now time.Time) (domain.WebhookMessage, error) {
+	if dl.Raw != "" {
+		return domain.WebhookMessage{}, fmt.Errorf("undecodable message cannot be replayed: %w", e.ErrInvalidInput)
+	}
+	msg := dl.WebhookMessage
+	msg.Attempts = 0
+	msg.EnqueuedAt = now.UTC()
+	return msg, nil
+}
+
+// ReplayDeadLetter отправляет dead letter заново с обнулённым счётчиком попыток.
+func (q *WebhookQueue) ReplayDeadLetter(ctx context.Context, id uuid.UUID) error {
+	dl, err := q.GetDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	msg, err := replayMessage(*dl, time.Now())
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	n, err := replayScript.Run(ctx, q.client, []string{q.dead, q.deadIndex, q.key}, id.String(), b).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return e.ErrNotFound
+	}
+	return nil
+}
+
+func (q *WebhookQueue) DeleteDeadLetter(ctx context.Context, id uuid.UUID) error {
+	var del *redis.IntCmd
+	_, err := q.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		del = p.HDel(ctx, q.dead, id.String())
+		p.ZRem(ctx, q.deadIndex, id.String())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if del.Val() == 0 {
+		return e.ErrNotFound
+	}
+	return nil
+}
+
+// PurgeDeadLetters удаляет все dead letters и возвращает их число.
+func (q *WebhookQueue) PurgeDeadLetters(ctx context.Context) (int64, error) {
+	var n *redis.IntCmd
+	_, err := q.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		n = p.HLen(ctx, q.dead)
+		p.Del(ctx, q.dead, q.deadIndex)
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
-	return p, nil
+	return n.Val(), nil
 }
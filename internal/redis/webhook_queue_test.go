@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+)
+
+func TestUndecodableMessage_GoesToDeadLetters(t *testing.T) {
+	raw := `{"id": "not-a-uuid", "envelope": `
+	_, err := decodeDelivery(raw)
+	if err == nil {
+		t.Fatalf("expected decode error")
+	}
+
+	at := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	dl := undecodableDeadLetter(raw, err, at)
+
+	if dl.Raw != raw || dl.LastError != err.Error() || !dl.FailedAt.Equal(at) || dl.ID == uuid.Nil {
+		t.Fatalf("unexpected dead letter: %+v", dl)
+	}
+	if _, err := replayMessage(dl, at); !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected replay to be refused, got %v", err)
+	}
+}
+
+func TestReplayMessage_ResetsAttempts(t *testing.T) {
+	at := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	dl := domain.DeadLetter{WebhookMessage: domain.WebhookMessage{ID: uuid.New(), Attempts: 3}, LastError: "boom"}
+
+	msg, err := replayMessage(dl, at)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if msg.ID != dl.ID || msg.Attempts != 0 || !msg.EnqueuedAt.Equal(at) {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+}
@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"time"
 
 	"redCollar/internal/domain"
+	"redCollar/pkg/e"
 
 	"github.com/google/uuid"
 )
@@ -24,25 +26,56 @@ func (s *AdminService) Create(ctx context.Context, req domain.CreateIncidentRequ
 	if status == "" {
 		status = domain.IncidentActive
 	}
+	severity := req.Severity
+	if severity == "" {
+		severity = domain.SeverityMedium
+	}
+	category := req.Category
+	if category == "" {
+		category = domain.CategoryOther
+	}
+	if !severity.Valid() || !category.Valid() {
+		return uuid.Nil, fmt.Errorf("unknown severity or category: %w", e.ErrInvalidInput)
+	}
 	inc := &domain.Incident{
-		ID:       uuid.New(),
-		Lat:      req.Lat,
-		Lng:      req.Lng,
-		RadiusKM: req.RadiusKM,
-		Status:   status,
+		ID:          uuid.New(),
+		Title:       req.Title,
+		Description: req.Description,
+		Severity:    severity,
+		Category:    category,
+		Lat:         req.Lat,
+		Lng:         req.Lng,
+		RadiusKM:    req.RadiusKM,
+		Status:      status,
+	}
+	if req.Geometry != nil {
+		if err := req.Geometry.Validate(); err != nil {
+			return uuid.Nil, err
+		}
+		inc.Geometry = req.Geometry
+		inc.RadiusKM = 0
+	}
+	now := time.Now().UTC()
+	if req.EndsAt != nil && !req.EndsAt.After(now) {
+		return uuid.Nil, fmt.Errorf("ends_at is in the past: %w", e.ErrInvalidInput)
+	}
+	inc.StartsAt, inc.EndsAt = req.StartsAt, req.EndsAt
+	if err := validateSchedule(inc); err != nil {
+		return uuid.Nil, err
 	}
-	if err := s.repo.Create(ctx, inc); err != nil {
+	applySchedule(inc, now)
+	if err := s.repo.Create(ctx, inc, incidentEvent(domain.WebhookEventIncidentCreated)); err != nil {
 		return uuid.Nil, err
 	}
 	s.refreshCache(ctx)
 	return inc.ID, nil
 }
-func (s *AdminService) List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error) {
-	items, total, err := s.repo.List(ctx, page, limit)
+func (s *AdminService) List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error) {
+	page, err := s.repo.List(ctx, req)
 	if err != nil {
-		return nil, 0, err
+		return domain.IncidentPage{}, err
 	}
-	return items, total, nil
+	return page, nil
 }
 
 func (s *AdminService) Get(ctx context.Context, id uuid.UUID) (*domain.Incident, error) {
@@ -50,10 +83,37 @@ func (s *AdminService) Get(ctx context.Context, id uuid.UUID) (*domain.Incident,
 }
 
 func (s *AdminService) Update(ctx context.Context, id uuid.UUID, req domain.UpdateIncidentRequest) error {
+	if (req.StartsAt != nil && req.ClearStartsAt) || (req.EndsAt != nil && req.ClearEndsAt) {
+		return fmt.Errorf("starts_at/ends_at and their clear flags are mutually exclusive: %w", e.ErrInvalidInput)
+	}
+	if req.Geometry != nil {
+		if req.RadiusKM != nil {
+			return fmt.Errorf("radius_km and geometry are mutually exclusive: %w", e.ErrInvalidInput)
+		}
+		if err := req.Geometry.Validate(); err != nil {
+			return err
+		}
+	}
+	if (req.Severity != nil && !req.Severity.Valid()) || (req.Category != nil && !req.Category.Valid()) {
+		return fmt.Errorf("unknown severity or category: %w", e.ErrInvalidInput)
+	}
 	inc, err := s.repo.Get(ctx, id)
 	if err != nil {
 		return err
 	}
+	prevStatus := inc.Status
+	if req.Title != nil {
+		inc.Title = *req.Title
+	}
+	if req.Description != nil {
+		inc.Description = *req.Description
+	}
+	if req.Severity != nil {
+		inc.Severity = *req.Severity
+	}
+	if req.Category != nil {
+		inc.Category = *req.Category
+	}
 	if req.Lat != nil {
 		inc.Lat = *req.Lat
 	}
@@ -62,11 +122,37 @@ func (s *AdminService) Update(ctx context.Context, id uuid.UUID, req domain.Upda
 	}
 	if req.RadiusKM != nil {
 		inc.RadiusKM = *req.RadiusKM
+		inc.Geometry = nil
+	}
+	if req.Geometry != nil {
+		inc.Geometry = req.Geometry
+		inc.RadiusKM = 0
 	}
 	if req.Status != nil {
 		inc.Status = *req.Status
 	}
-	if err := s.repo.Update(ctx, inc); err != nil {
+	if req.StartsAt != nil {
+		inc.StartsAt = req.StartsAt
+	}
+	if req.ClearStartsAt {
+		inc.StartsAt = nil
+	}
+	if req.EndsAt != nil {
+		inc.EndsAt = req.EndsAt
+	}
+	if req.ClearEndsAt {
+		inc.EndsAt = nil
+	}
+	if req.Status == nil && inc.Status == domain.IncidentExpired && req.ChangesSchedule() {
+		// Завершённый по окну инцидент с новым окном снова подчиняется
+		// расписанию: статус пересчитает applySchedule.
+		inc.Status = domain.IncidentActive
+	}
+	if err := validateSchedule(inc); err != nil {
+		return err
+	}
+	applySchedule(inc, time.Now().UTC())
+	if err := s.repo.Update(ctx, inc, incidentEvent(updateEventType(prevStatus, inc.Status))); err != nil {
 		return err
 	}
 	s.refreshCache(ctx)
@@ -74,13 +160,80 @@ func (s *AdminService) Update(ctx context.Context, id uuid.UUID, req domain.Upda
 }
 
 func (s *AdminService) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
+	if err := s.repo.Delete(ctx, id, incidentEvent(domain.WebhookEventIncidentDeactivated)); err != nil {
 		return err
 	}
 	s.refreshCache(ctx)
 	return nil
 }
 
+// ApplySchedule активирует инциденты, у которых наступил starts_at, и завершает
+// те, у которых прошёл ends_at. Кэш активных пересобирается на каждом вызове.
+func (s *AdminService) ApplySchedule(ctx context.Context, now time.Time) (domain.ScheduleResult, error) {
+	var res domain.ScheduleResult
+
+	expired, err := s.repo.ExpireDue(ctx, now, incidentEvent(domain.WebhookEventIncidentExpired))
+	if err != nil {
+		return res, err
+	}
+	activated, err := s.repo.ActivateDue(ctx, now)
+	if err != nil {
+		return res, err
+	}
+	res.Activated, res.Expired = activated, expired
+
+	s.refreshCache(ctx)
+	return res, nil
+}
+
+func (s *AdminService) NextScheduleBoundary(ctx context.Context, now time.Time) (*time.Time, error) {
+	return s.repo.NextScheduleBoundary(ctx, now)
+}
+
+// updateEventType — правка, переводящая инцидент в inactive или expired,
+// публикуется как соответствующее событие, остальные — как incident.updated.
+func updateEventType(prev, next domain.IncidentStatus) domain.WebhookEventType {
+	if prev != next {
+		switch next {
+		case domain.IncidentInactive:
+			return domain.WebhookEventIncidentDeactivated
+		case domain.IncidentExpired:
+			return domain.WebhookEventIncidentExpired
+		}
+	}
+	return domain.WebhookEventIncidentUpdated
+}
+
+// incidentEvent — событие, которое репозиторий запишет в outbox вместе с изменением.
+func incidentEvent(t domain.WebhookEventType) domain.IncidentEventFunc {
+	return func(inc *domain.Incident) (domain.WebhookEvent, error) {
+		return domain.NewWebhookEvent(t, time.Now(), inc)
+	}
+}
+
+func validateSchedule(inc *domain.Incident) error {
+	if inc.StartsAt != nil && inc.EndsAt != nil && !inc.EndsAt.After(*inc.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at: %w", e.ErrInvalidInput)
+	}
+	return nil
+}
+
+// applySchedule приводит статус active/scheduled в соответствие с окном
+// starts_at/ends_at. inactive и expired выставляются явно и не трогаются.
+func applySchedule(inc *domain.Incident, now time.Time) {
+	if inc.Status != domain.IncidentActive && inc.Status != domain.IncidentScheduled {
+		return
+	}
+	switch {
+	case inc.EndsAt != nil && !now.Before(*inc.EndsAt):
+		inc.Status = domain.IncidentExpired
+	case inc.StartsAt != nil && now.Before(*inc.StartsAt):
+		inc.Status = domain.IncidentScheduled
+	default:
+		inc.Status = domain.IncidentActive
+	}
+}
+
 func toIncidents(src []*domain.Incident) []domain.Incident {
 	out := make([]domain.Incident, 0, len(src))
 	for _, p := range src {
@@ -98,10 +251,17 @@ func (s *AdminService) refreshCache(ctx context.Context) {
 	cached := make([]domain.CachedIncident, 0, len(incidents))
 	for _, inc := range incidents {
 		cached = append(cached, domain.CachedIncident{
-			ID:       inc.ID,
-			Lat:      inc.Lat,
-			Lng:      inc.Lng,
-			RadiusKM: inc.RadiusKM,
+			ID:          inc.ID,
+			Title:       inc.Title,
+			Description: inc.Description,
+			Severity:    inc.Severity,
+			Category:    inc.Category,
+			Lat:         inc.Lat,
+			Lng:         inc.Lng,
+			RadiusKM:    inc.RadiusKM,
+			Geometry:    inc.Geometry,
+			StartsAt:    inc.StartsAt,
+			EndsAt:      inc.EndsAt,
 		})
 	}
 
@@ -12,8 +12,8 @@ func (s *Service) Create(ctx context.Context, req domain.CreateIncidentRequest)
 	return s.AdminIncidentService.Create(ctx, req)
 }
 
-func (s *Service) List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error) {
-	return s.AdminIncidentService.List(ctx, page, limit)
+func (s *Service) List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error) {
+	return s.AdminIncidentService.List(ctx, req)
 }
 
 func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.Incident, error) {
@@ -2,7 +2,9 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 
 	"redCollar/internal/domain"
 	"redCollar/internal/service"
+	"redCollar/pkg/e"
 
 	mock_service "redCollar/internal/service/mocks" // <-- поправь импорт на свой путь
 )
@@ -77,8 +80,8 @@ func TestAdminIncidentService_Create_OK_Defaults(t *testing.T) {
 		AnyTimes()
 	var got *domain.Incident
 	repo.EXPECT().
-		Create(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(_ context.Context, inc *domain.Incident) error {
+		Create(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
 			got = inc
 			return nil
 		}).
@@ -122,7 +125,7 @@ func TestAdminIncidentService_Create_RepoError(t *testing.T) {
 
 	wantErr := errors.New("db down")
 	repo.EXPECT().
-		Create(gomock.Any(), gomock.Any()).
+		Create(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(wantErr).
 		Times(1)
 
@@ -171,7 +174,7 @@ func TestAdminIncidentService_Create_Boundaries(t *testing.T) {
 				AnyTimes()
 
 			repo.EXPECT().
-				Create(gomock.Any(), gomock.Any()).
+				Create(gomock.Any(), gomock.Any(), gomock.Any()).
 				Return(nil).
 				Times(1)
 
@@ -256,21 +259,21 @@ func TestAdminIncidentService_List_OK_Empty(t *testing.T) {
 	repo := mock_service.NewMockIncidentRepository(ctrl)
 
 	repo.EXPECT().
-		List(gomock.Any(), 1, 20).
-		Return([]*domain.Incident{}, int64(0), nil).
+		List(gomock.Any(), domain.ListIncidentsRequest{Page: 1, Limit: 20}).
+		Return(domain.IncidentPage{Items: []*domain.Incident{}}, nil).
 		Times(1)
 
 	svc := service.NewAdminIncidentService(repo, nil)
 
-	list, total, err := svc.List(context.Background(), 1, 20)
+	page, err := svc.List(context.Background(), domain.ListIncidentsRequest{Page: 1, Limit: 20})
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
-	if total != 0 {
-		t.Fatalf("expected total=0 got=%d", total)
+	if page.Total != 0 {
+		t.Fatalf("expected total=0 got=%d", page.Total)
 	}
-	if len(list) != 0 {
-		t.Fatalf("expected empty list got=%d", len(list))
+	if len(page.Items) != 0 {
+		t.Fatalf("expected empty list got=%d", len(page.Items))
 	}
 }
 
@@ -289,21 +292,21 @@ func TestAdminIncidentService_List_OK_NonEmpty(t *testing.T) {
 	var wantTotal int64 = 2
 
 	repo.EXPECT().
-		List(gomock.Any(), 2, 10).
-		Return(wantList, wantTotal, nil).
+		List(gomock.Any(), domain.ListIncidentsRequest{Page: 2, Limit: 10}).
+		Return(domain.IncidentPage{Items: wantList, Total: wantTotal}, nil).
 		Times(1)
 
 	svc := service.NewAdminIncidentService(repo, nil)
 
-	list, total, err := svc.List(context.Background(), 2, 10)
+	page, err := svc.List(context.Background(), domain.ListIncidentsRequest{Page: 2, Limit: 10})
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
-	if total != wantTotal {
-		t.Fatalf("expected total=%d got=%d", wantTotal, total)
+	if page.Total != wantTotal {
+		t.Fatalf("expected total=%d got=%d", wantTotal, page.Total)
 	}
-	if len(list) != len(wantList) {
-		t.Fatalf("expected len=%d got=%d", len(wantList), len(list))
+	if len(page.Items) != len(wantList) {
+		t.Fatalf("expected len=%d got=%d", len(wantList), len(page.Items))
 	}
 }
 
@@ -316,13 +319,13 @@ func TestAdminIncidentService_List_RepoError(t *testing.T) {
 	repo := mock_service.NewMockIncidentRepository(ctrl)
 
 	repo.EXPECT().
-		List(gomock.Any(), 1, 20).
-		Return(nil, int64(0), errors.New("db error")).
+		List(gomock.Any(), domain.ListIncidentsRequest{Page: 1, Limit: 20}).
+		Return(domain.IncidentPage{}, errors.New("db error")).
 		Times(1)
 
 	svc := service.NewAdminIncidentService(repo, nil)
 
-	_, _, err := svc.List(context.Background(), 1, 20)
+	_, err := svc.List(context.Background(), domain.ListIncidentsRequest{Page: 1, Limit: 20})
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -369,8 +372,8 @@ func TestAdminIncidentService_Update_OK_AllFields(t *testing.T) {
 	// Порядок вызовов: Get -> Update. [web:564]
 	gomock.InOrder(
 		repo.EXPECT().Get(gomock.Any(), id).Return(existing, nil).Times(1),
-		repo.EXPECT().Update(gomock.Any(), gomock.Any()).
-			DoAndReturn(func(_ context.Context, inc *domain.Incident) error {
+		repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
 				updated = inc
 				return nil
 			}).Times(1),
@@ -433,8 +436,11 @@ func TestAdminIncidentService_Update_OK_OnlyLat(t *testing.T) {
 	var updated *domain.Incident
 	gomock.InOrder(
 		repo.EXPECT().Get(gomock.Any(), id).Return(existing, nil).Times(1),
-		repo.EXPECT().Update(gomock.Any(), gomock.Any()).
-			DoAndReturn(func(_ context.Context, inc *domain.Incident) error { updated = inc; return nil }).
+		repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
+				updated = inc
+				return nil
+			}).
 			Times(1),
 	)
 
@@ -477,7 +483,7 @@ func TestAdminIncidentService_Update_OK_OnlyLng(t *testing.T) {
 
 	gomock.InOrder(
 		repo.EXPECT().Get(gomock.Any(), id).Return(existing, nil).Times(1),
-		repo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident) error {
+		repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
 			if inc.Lng != *req.Lng {
 				t.Fatalf("expected lng=%v got=%v", *req.Lng, inc.Lng)
 			}
@@ -517,7 +523,7 @@ func TestAdminIncidentService_Update_OK_OnlyRadius(t *testing.T) {
 
 	gomock.InOrder(
 		repo.EXPECT().Get(gomock.Any(), id).Return(existing, nil).Times(1),
-		repo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident) error {
+		repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
 			if inc.RadiusKM != *req.RadiusKM {
 				t.Fatalf("expected radius=%v got=%v", *req.RadiusKM, inc.RadiusKM)
 			}
@@ -557,7 +563,7 @@ func TestAdminIncidentService_Update_OK_OnlyStatus(t *testing.T) {
 
 	gomock.InOrder(
 		repo.EXPECT().Get(gomock.Any(), id).Return(existing, nil).Times(1),
-		repo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident) error {
+		repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
 			if inc.Status != *req.Status {
 				t.Fatalf("expected status=%v got=%v", *req.Status, inc.Status)
 			}
@@ -616,7 +622,7 @@ func TestAdminIncidentService_Update_UpdateError(t *testing.T) {
 	wantErr := errors.New("db update failed")
 	gomock.InOrder(
 		repo.EXPECT().Get(gomock.Any(), id).Return(existing, nil).Times(1),
-		repo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(wantErr).Times(1),
+		repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(wantErr).Times(1),
 	)
 
 	svc := service.NewAdminIncidentService(repo, nil)
@@ -653,7 +659,7 @@ func TestAdminIncidentService_Update_EmptyPatch(t *testing.T) {
 
 	gomock.InOrder(
 		repo.EXPECT().Get(gomock.Any(), id).Return(existing, nil).Times(1),
-		repo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident) error {
+		repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
 			// Ничего не менялось — обновляем тем же объектом
 			if inc.Lat != existing.Lat || inc.Lng != existing.Lng || inc.RadiusKM != existing.RadiusKM || inc.Status != existing.Status {
 				t.Fatalf("expected no changes, got=%+v", inc)
@@ -692,7 +698,7 @@ func TestAdminIncidentService_Delete_OK(t *testing.T) {
 	id := mustUUID(t)
 
 	repo.EXPECT().
-		Delete(gomock.Any(), id).
+		Delete(gomock.Any(), id, gomock.Any()).
 		Return(nil).
 		Times(1)
 
@@ -713,7 +719,7 @@ func TestAdminIncidentService_Delete_RepoError(t *testing.T) {
 	id := mustUUID(t)
 
 	repo.EXPECT().
-		Delete(gomock.Any(), id).
+		Delete(gomock.Any(), id, gomock.Any()).
 		Return(errors.New("db error")).
 		Times(1)
 
@@ -723,3 +729,583 @@ func TestAdminIncidentService_Delete_RepoError(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+// --- Polygons ---
+
+func testSquare() *domain.Geometry {
+	return &domain.Geometry{
+		Type: domain.GeometryPolygon,
+		Polygons: []domain.Polygon{{
+			{{37, 55}, {38, 55}, {38, 56}, {37, 56}, {37, 55}},
+		}},
+	}
+}
+
+func TestAdminIncidentService_Create_Polygon_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+
+	area := testSquare()
+	var got *domain.Incident
+	repo.EXPECT().
+		Create(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
+			got = inc
+			return nil
+		}).
+		Times(1)
+	repo.EXPECT().
+		ListActive(gomock.Any()).
+		Return([]*domain.Incident{{ID: uuid.New(), Lat: 55.5, Lng: 37.5, Geometry: area}}, nil).
+		Times(1)
+	cache.EXPECT().
+		SetActive(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, cached []domain.CachedIncident, _ time.Duration) error {
+			if len(cached) != 1 || cached[0].Geometry == nil {
+				t.Fatalf("expected polygon in cache, got=%+v", cached)
+			}
+			return nil
+		}).
+		Times(1)
+
+	svc := service.NewAdminIncidentService(repo, cache)
+
+	_, err := svc.Create(context.Background(), domain.CreateIncidentRequest{Geometry: area, RadiusKM: 5})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.Geometry != area {
+		t.Fatalf("expected geometry passed to repo")
+	}
+	if got.RadiusKM != 0 {
+		t.Fatalf("expected radius reset for polygon, got=%v", got.RadiusKM)
+	}
+}
+
+func TestAdminIncidentService_Create_InvalidPolygon_NoRepoCall(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	svc := service.NewAdminIncidentService(repo, nil)
+
+	bad := &domain.Geometry{
+		Type:     domain.GeometryPolygon,
+		Polygons: []domain.Polygon{{{{0, 0}, {1, 0}, {1, 1}}}},
+	}
+
+	_, err := svc.Create(context.Background(), domain.CreateIncidentRequest{Geometry: bad})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestAdminIncidentService_Update_RadiusAndGeometry_Conflict(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	svc := service.NewAdminIncidentService(repo, nil)
+
+	err := svc.Update(context.Background(), mustUUID(t), domain.UpdateIncidentRequest{
+		RadiusKM: f64ptr(1),
+		Geometry: testSquare(),
+	})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestAdminIncidentService_Update_RadiusTurnsPolygonIntoCircle(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	repo.EXPECT().ListActive(gomock.Any()).Return([]*domain.Incident{}, nil).Times(1)
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	cache.EXPECT().SetActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	id := mustUUID(t)
+	existing := &domain.Incident{ID: id, Lat: 55.5, Lng: 37.5, Geometry: testSquare(), Status: domain.IncidentActive}
+
+	gomock.InOrder(
+		repo.EXPECT().Get(gomock.Any(), id).Return(existing, nil).Times(1),
+		repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
+			if inc.Geometry != nil || inc.RadiusKM != 2 {
+				t.Fatalf("expected circle with radius 2, got=%+v", inc)
+			}
+			return nil
+		}).Times(1),
+	)
+
+	svc := service.NewAdminIncidentService(repo, cache)
+	if err := svc.Update(context.Background(), id, domain.UpdateIncidentRequest{RadiusKM: f64ptr(2)}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+// --- Metadata ---
+
+func TestAdminIncidentService_Create_MetadataDefaults(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	repo.EXPECT().ListActive(gomock.Any()).Return([]*domain.Incident{}, nil).Times(1)
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	cache.EXPECT().SetActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	var got *domain.Incident
+	repo.EXPECT().
+		Create(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
+			got = inc
+			return nil
+		}).
+		Times(1)
+
+	svc := service.NewAdminIncidentService(repo, cache)
+
+	_, err := svc.Create(context.Background(), domain.CreateIncidentRequest{
+		Title: "Gas leak", Description: "Avoid the area", Lat: 55.75, Lng: 37.61, RadiusKM: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	if got.Title != "Gas leak" || got.Description != "Avoid the area" {
+		t.Fatalf("metadata not passed: %+v", got)
+	}
+	if got.Severity != domain.SeverityMedium || got.Category != domain.CategoryOther {
+		t.Fatalf("expected default severity/category, got=%q/%q", got.Severity, got.Category)
+	}
+}
+
+func TestAdminIncidentService_Create_UnknownCategory(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	svc := service.NewAdminIncidentService(repo, nil)
+
+	_, err := svc.Create(context.Background(), domain.CreateIncidentRequest{
+		Lat: 1, Lng: 1, RadiusKM: 1, Category: "alien_invasion",
+	})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestAdminIncidentService_Update_Metadata(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	repo.EXPECT().ListActive(gomock.Any()).Return([]*domain.Incident{}, nil).Times(1)
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	cache.EXPECT().SetActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	id := mustUUID(t)
+	existing := &domain.Incident{
+		ID: id, Title: "old", Severity: domain.SeverityLow, Category: domain.CategoryOther,
+		Lat: 10, Lng: 20, RadiusKM: 1, Status: domain.IncidentActive,
+	}
+
+	title := "Road closed"
+	severity := domain.SeverityCritical
+	category := domain.CategoryRoadClosure
+
+	gomock.InOrder(
+		repo.EXPECT().Get(gomock.Any(), id).Return(existing, nil).Times(1),
+		repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
+			if inc.Title != title || inc.Severity != severity || inc.Category != category {
+				t.Fatalf("metadata not updated: %+v", inc)
+			}
+			if inc.Lat != existing.Lat || inc.RadiusKM != existing.RadiusKM {
+				t.Fatalf("unexpected geo changes: %+v", inc)
+			}
+			return nil
+		}).Times(1),
+	)
+
+	svc := service.NewAdminIncidentService(repo, cache)
+	err := svc.Update(context.Background(), id, domain.UpdateIncidentRequest{
+		Title: &title, Severity: &severity, Category: &category,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+// --- Schedule ---
+
+func TestAdminIncidentService_Create_FutureStart_Scheduled(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	repo.EXPECT().ListActive(gomock.Any()).Return([]*domain.Incident{}, nil).Times(1)
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	cache.EXPECT().SetActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	startsAt := time.Now().Add(time.Hour)
+	endsAt := startsAt.Add(2 * time.Hour)
+
+	repo.EXPECT().
+		Create(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
+			if inc.Status != domain.IncidentScheduled {
+				t.Fatalf("expected scheduled status, got=%q", inc.Status)
+			}
+			if inc.StartsAt == nil || !inc.StartsAt.Equal(startsAt) || inc.EndsAt == nil || !inc.EndsAt.Equal(endsAt) {
+				t.Fatalf("schedule not passed: %+v", inc)
+			}
+			return nil
+		}).
+		Times(1)
+
+	svc := service.NewAdminIncidentService(repo, cache)
+
+	_, err := svc.Create(context.Background(), domain.CreateIncidentRequest{
+		Lat: 1, Lng: 1, RadiusKM: 1, StartsAt: &startsAt, EndsAt: &endsAt,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestAdminIncidentService_Create_InvalidSchedule(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Hour)
+	start := time.Now().Add(2 * time.Hour)
+	beforeStart := time.Now().Add(time.Hour)
+
+	cases := map[string]domain.CreateIncidentRequest{
+		"ends_in_past":      {Lat: 1, Lng: 1, RadiusKM: 1, EndsAt: &past},
+		"ends_before_start": {Lat: 1, Lng: 1, RadiusKM: 1, StartsAt: &start, EndsAt: &beforeStart},
+	}
+
+	for name, req := range cases {
+		req := req
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := service.NewAdminIncidentService(mock_service.NewMockIncidentRepository(ctrl), nil)
+
+			if _, err := svc.Create(context.Background(), req); !errors.Is(err, e.ErrInvalidInput) {
+				t.Fatalf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAdminIncidentService_ApplySchedule_RefreshesCache(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+
+	now := mustTime(t)
+	activated := []uuid.UUID{mustUUID(t)}
+	expired := []uuid.UUID{mustUUID(t), mustUUID(t)}
+
+	gomock.InOrder(
+		repo.EXPECT().ExpireDue(gomock.Any(), now, gomock.Any()).Return(expired, nil).Times(1),
+		repo.EXPECT().ActivateDue(gomock.Any(), now).Return(activated, nil).Times(1),
+		repo.EXPECT().ListActive(gomock.Any()).Return([]*domain.Incident{}, nil).Times(1),
+		cache.EXPECT().SetActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1),
+	)
+
+	svc := service.NewAdminIncidentService(repo, cache)
+
+	res, err := svc.ApplySchedule(context.Background(), now)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(res.Activated) != 1 || len(res.Expired) != 2 || !res.Changed() {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestAdminIncidentService_ApplySchedule_RepoError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	repo.EXPECT().ExpireDue(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db down")).Times(1)
+
+	svc := service.NewAdminIncidentService(repo, nil)
+
+	if _, err := svc.ApplySchedule(context.Background(), mustTime(t)); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+// --- Events ---
+
+func TestAdminIncidentService_Create_WritesEvent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+
+	var got domain.WebhookEvent
+	repo.EXPECT().
+		Create(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inc *domain.Incident, event domain.IncidentEventFunc) error {
+			ev, err := event(inc)
+			got = ev
+			return err
+		}).
+		Times(1)
+	repo.EXPECT().ListActive(gomock.Any()).Return([]*domain.Incident{}, nil).Times(1)
+	cache.EXPECT().SetActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	svc := service.NewAdminIncidentService(repo, cache)
+
+	id, err := svc.Create(context.Background(), domain.CreateIncidentRequest{Title: "Пожар", Lat: 55.75, Lng: 37.61, RadiusKM: 1})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.Type != domain.WebhookEventIncidentCreated || got.Version != domain.WebhookEventVersion || got.ID == uuid.Nil {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+	var inc domain.Incident
+	if err := json.Unmarshal(got.Data, &inc); err != nil || inc.ID != id || inc.Title != "Пожар" {
+		t.Fatalf("unexpected event data: %s (%v)", got.Data, err)
+	}
+}
+
+func TestAdminIncidentService_Update_StatusEvents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status domain.IncidentStatus
+		want   domain.WebhookEventType
+	}{
+		{name: "deactivated", status: domain.IncidentInactive, want: domain.WebhookEventIncidentDeactivated},
+		{name: "expired", status: domain.IncidentExpired, want: domain.WebhookEventIncidentExpired},
+		{name: "same status", status: domain.IncidentActive, want: domain.WebhookEventIncidentUpdated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_service.NewMockIncidentRepository(ctrl)
+			cache := mock_service.NewMockIncidentCacheService(ctrl)
+
+			id := mustUUID(t)
+			repo.EXPECT().
+				Get(gomock.Any(), id).
+				Return(&domain.Incident{ID: id, Lat: 55.75, Lng: 37.61, RadiusKM: 1, Status: domain.IncidentActive}, nil).
+				Times(1)
+			repo.EXPECT().
+				Update(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, inc *domain.Incident, event domain.IncidentEventFunc) error {
+					ev, err := event(inc)
+					if err != nil {
+						return err
+					}
+					if ev.Type != tt.want {
+						t.Fatalf("expected %q got %q", tt.want, ev.Type)
+					}
+					return nil
+				}).
+				Times(1)
+			repo.EXPECT().ListActive(gomock.Any()).Return([]*domain.Incident{}, nil).Times(1)
+			cache.EXPECT().SetActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+			svc := service.NewAdminIncidentService(repo, cache)
+
+			status := tt.status
+			if err := svc.Update(context.Background(), id, domain.UpdateIncidentRequest{Status: &status}); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+		})
+	}
+}
+
+func TestAdminIncidentService_ApplySchedule_ExpiredEvent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockIncidentRepository(ctrl)
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+
+	now := mustTime(t)
+	expired := mustUUID(t)
+
+	repo.EXPECT().
+		ExpireDue(gomock.Any(), now, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ time.Time, event domain.IncidentEventFunc) ([]uuid.UUID, error) {
+			ev, err := event(&domain.Incident{ID: expired, Status: domain.IncidentExpired})
+			if err != nil {
+				return nil, err
+			}
+			if ev.Type != domain.WebhookEventIncidentExpired {
+				t.Fatalf("unexpected event type %q", ev.Type)
+			}
+			return []uuid.UUID{expired}, nil
+		}).
+		Times(1)
+	repo.EXPECT().ActivateDue(gomock.Any(), now).Return(nil, nil).Times(1)
+	repo.EXPECT().ListActive(gomock.Any()).Return([]*domain.Incident{}, nil).Times(1)
+	cache.EXPECT().SetActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	svc := service.NewAdminIncidentService(repo, cache)
+
+	if _, err := svc.ApplySchedule(context.Background(), now); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestAdminIncidentService_Update_Schedule(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().UTC().Add(-time.Hour)
+	earlier := past.Add(-time.Hour)
+	future := time.Now().UTC().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		existing   domain.Incident
+		req        domain.UpdateIncidentRequest
+		wantStatus domain.IncidentStatus
+		wantStart  *time.Time
+		wantEnd    *time.Time
+	}{
+		{
+			name:       "clear ends_at",
+			existing:   domain.Incident{Status: domain.IncidentActive, EndsAt: &future},
+			req:        domain.UpdateIncidentRequest{ClearEndsAt: true},
+			wantStatus: domain.IncidentActive,
+		},
+		{
+			name:       "clear starts_at activates scheduled",
+			existing:   domain.Incident{Status: domain.IncidentScheduled, StartsAt: &future},
+			req:        domain.UpdateIncidentRequest{ClearStartsAt: true},
+			wantStatus: domain.IncidentActive,
+		},
+		{
+			name:       "expired with ends_at moved to future",
+			existing:   domain.Incident{Status: domain.IncidentExpired, EndsAt: &past},
+			req:        domain.UpdateIncidentRequest{EndsAt: &future},
+			wantStatus: domain.IncidentActive,
+			wantEnd:    &future,
+		},
+		{
+			name:       "expired with ends_at cleared",
+			existing:   domain.Incident{Status: domain.IncidentExpired, EndsAt: &past},
+			req:        domain.UpdateIncidentRequest{ClearEndsAt: true},
+			wantStatus: domain.IncidentActive,
+		},
+		{
+			name:       "expired with window still past",
+			existing:   domain.Incident{Status: domain.IncidentExpired, EndsAt: &past},
+			req:        domain.UpdateIncidentRequest{StartsAt: &earlier, EndsAt: &past},
+			wantStatus: domain.IncidentExpired,
+			wantStart:  &earlier,
+			wantEnd:    &past,
+		},
+		{
+			name:       "expired without schedule change stays expired",
+			existing:   domain.Incident{Status: domain.IncidentExpired, EndsAt: &past},
+			req:        domain.UpdateIncidentRequest{Title: new(string)},
+			wantStatus: domain.IncidentExpired,
+			wantEnd:    &past,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock_service.NewMockIncidentRepository(ctrl)
+			cache := mock_service.NewMockIncidentCacheService(ctrl)
+
+			id := mustUUID(t)
+			existing := tt.existing
+			existing.ID, existing.Lat, existing.Lng, existing.RadiusKM = id, 55.75, 37.61, 1
+
+			var updated *domain.Incident
+			repo.EXPECT().Get(gomock.Any(), id).Return(&existing, nil).Times(1)
+			repo.EXPECT().
+				Update(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, inc *domain.Incident, _ domain.IncidentEventFunc) error {
+					updated = inc
+					return nil
+				}).
+				Times(1)
+			repo.EXPECT().ListActive(gomock.Any()).Return([]*domain.Incident{}, nil).Times(1)
+			cache.EXPECT().SetActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+			svc := service.NewAdminIncidentService(repo, cache)
+			if err := svc.Update(context.Background(), id, tt.req); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+
+			if updated.Status != tt.wantStatus {
+				t.Fatalf("expected status %q got %q", tt.wantStatus, updated.Status)
+			}
+			if !reflect.DeepEqual(updated.StartsAt, tt.wantStart) || !reflect.DeepEqual(updated.EndsAt, tt.wantEnd) {
+				t.Fatalf("unexpected window: %v - %v", updated.StartsAt, updated.EndsAt)
+			}
+		})
+	}
+}
+
+func TestAdminIncidentService_Update_ClearFlagConflict(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewAdminIncidentService(mock_service.NewMockIncidentRepository(ctrl), mock_service.NewMockIncidentCacheService(ctrl))
+
+	end := mustTime(t)
+	err := svc.Update(context.Background(), mustUUID(t), domain.UpdateIncidentRequest{EndsAt: &end, ClearEndsAt: true})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"redCollar/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func (s *Service) ListChecks(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error) {
+	return s.CheckHistoryService.ListChecks(ctx, req)
+}
+
+func (s *Service) IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error) {
+	return s.CheckHistoryService.IncidentVisitors(ctx, incidentID, req)
+}
+
+func (s *Service) IncidentOccupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error) {
+	return s.CheckHistoryService.IncidentOccupants(ctx, incidentID, req)
+}
+
+func (s *Service) UserTimeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error) {
+	return s.CheckHistoryService.UserTimeline(ctx, userID, req)
+}
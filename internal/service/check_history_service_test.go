@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"redCollar/internal/domain"
+	"redCollar/internal/service"
+	mock_service "redCollar/internal/service/mocks"
+	"redCollar/pkg/e"
+)
+
+func TestCheckHistoryService_ListChecks_DefaultLimit(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockCheckHistoryRepository(ctrl)
+	userID := uuid.New()
+
+	repo.EXPECT().
+		List(gomock.Any(), domain.ListChecksRequest{Limit: 20, UserID: userID}).
+		Return(domain.CheckPage{Items: []*domain.LocationCheck{{UserID: userID}}}, nil).
+		Times(1)
+
+	svc := service.NewCheckHistoryService(repo)
+
+	page, err := svc.ListChecks(context.Background(), domain.ListChecksRequest{UserID: userID})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(page.Items) != 1 {
+		t.Fatalf("expected 1 check got=%d", len(page.Items))
+	}
+}
+
+func TestCheckHistoryService_IncidentVisitors_NilID(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewCheckHistoryService(mock_service.NewMockCheckHistoryRepository(ctrl))
+
+	_, err := svc.IncidentVisitors(context.Background(), uuid.Nil, domain.IncidentVisitorsRequest{})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestService_IncidentVisitors_Delegates(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checksSvc := mock_service.NewMockCheckHistoryService(ctrl)
+	id := uuid.New()
+	req := domain.IncidentVisitorsRequest{Limit: 5}
+	wantErr := errors.New("db down")
+
+	checksSvc.EXPECT().
+		IncidentVisitors(gomock.Any(), id, req).
+		Return(domain.VisitorPage{}, wantErr).
+		Times(1)
+
+	svc := service.NewService(nil, nil, nil, checksSvc, nil, nil, nil)
+
+	if _, err := svc.IncidentVisitors(context.Background(), id, req); !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestCheckHistoryService_IncidentOccupants_DefaultLimit(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockCheckHistoryRepository(ctrl)
+	id := uuid.New()
+
+	repo.EXPECT().
+		Occupants(gomock.Any(), id, domain.OccupantsRequest{Limit: 20}).
+		Return(domain.OccupantPage{Items: []domain.Occupant{{UserID: uuid.New()}}}, nil).
+		Times(1)
+
+	svc := service.NewCheckHistoryService(repo)
+
+	page, err := svc.IncidentOccupants(context.Background(), id, domain.OccupantsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(page.Items) != 1 {
+		t.Fatalf("expected 1 occupant got=%d", len(page.Items))
+	}
+}
+
+func TestCheckHistoryService_UserTimeline_NilID(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewCheckHistoryService(mock_service.NewMockCheckHistoryRepository(ctrl))
+
+	_, err := svc.UserTimeline(context.Background(), uuid.Nil, domain.TimelineRequest{})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestService_UserTimeline_Delegates(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checksSvc := mock_service.NewMockCheckHistoryService(ctrl)
+	id := uuid.New()
+	req := domain.TimelineRequest{Limit: 5}
+	want := domain.TimelinePage{Items: []domain.OccupancyTransition{{UserID: id, Kind: domain.OccupancyEntered}}}
+
+	checksSvc.EXPECT().
+		UserTimeline(gomock.Any(), id, req).
+		Return(want, nil).
+		Times(1)
+
+	svc := service.NewService(nil, nil, nil, checksSvc, nil, nil, nil)
+
+	got, err := svc.UserTimeline(context.Background(), id, req)
+	if err != nil || len(got.Items) != 1 {
+		t.Fatalf("unexpected page=%+v err=%v", got, err)
+	}
+}
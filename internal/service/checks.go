@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+)
+
+type checkHistoryService struct {
+	repo CheckHistoryRepository
+}
+
+func NewCheckHistoryService(repo CheckHistoryRepository) CheckHistoryService {
+	return &checkHistoryService{repo: repo}
+}
+
+func (s *checkHistoryService) ListChecks(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error) {
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	return s.repo.List(ctx, req)
+}
+
+func (s *checkHistoryService) IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error) {
+	if incidentID == uuid.Nil {
+		return domain.VisitorPage{}, fmt.Errorf("empty incident id: %w", e.ErrInvalidInput)
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	return s.repo.IncidentVisitors(ctx, incidentID, req)
+}
+
+func (s *checkHistoryService) IncidentOccupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error) {
+	if incidentID == uuid.Nil {
+		return domain.OccupantPage{}, fmt.Errorf("empty incident id: %w", e.ErrInvalidInput)
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	return s.repo.Occupants(ctx, incidentID, req)
+}
+
+func (s *checkHistoryService) UserTimeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error) {
+	if userID == uuid.Nil {
+		return domain.TimelinePage{}, fmt.Errorf("empty user id: %w", e.ErrInvalidInput)
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	return s.repo.Timeline(ctx, userID, req)
+}
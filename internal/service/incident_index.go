@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	"redCollar/internal/domain"
+	"redCollar/internal/geoindex"
+)
+
+// incidentSnapshot — активные инциденты из кэша, разложенные по сетке.
+type incidentSnapshot struct {
+	version   string
+	grid      *geoindex.Grid
+	summaries map[uuid.UUID]domain.IncidentSummary
+}
+
+func newIncidentSnapshot(incidents []domain.CachedIncident, version string, marginKm float64) *incidentSnapshot {
+	return &incidentSnapshot{
+		version:   version,
+		grid:      geoindex.New(incidents, geoindex.DefaultCellDeg, marginKm),
+		summaries: summariesByID(incidents),
+	}
+}
+
+// activeIndex держит снимок активных инцидентов в памяти процесса. Если кэш
+// умеет отдавать версию, на запрос уходит только её чтение, а список
+// скачивается и индекс перестраивается лишь при смене версии. Без версии
+// снимок строится заново на каждый запрос.
+type activeIndex struct {
+	cache    IncidentCacheService
+	marginKm float64
+
+	mu  sync.Mutex
+	cur atomic.Pointer[incidentSnapshot]
+}
+
+func newActiveIndex(cache IncidentCacheService, marginKm float64) *activeIndex {
+	return &activeIndex{cache: cache, marginKm: marginKm}
+}
+
+func (x *activeIndex) get(ctx context.Context) (*incidentSnapshot, error) {
+	vc, ok := x.cache.(IncidentCacheVersion)
+	if !ok {
+		incidents, err := x.cache.GetActive(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return newIncidentSnapshot(incidents, "", x.marginKm), nil
+	}
+
+	version, err := vc.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if snap := x.cur.Load(); snap != nil && snap.version == version {
+		return snap, nil
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if snap := x.cur.Load(); snap != nil && snap.version == version {
+		return snap, nil
+	}
+
+	incidents, version, err := vc.GetActiveVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap := newIncidentSnapshot(incidents, version, x.marginKm)
+	x.cur.Store(snap)
+	return snap, nil
+}
@@ -82,19 +82,18 @@ func (mr *MockAdminIncidentServiceMockRecorder) Get(ctx, id interface{}) *gomock
 }
 
 // List mocks base method.
-func (m *MockAdminIncidentService) List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error) {
+func (m *MockAdminIncidentService) List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, page, limit)
-	ret0, _ := ret[0].([]*domain.Incident)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(domain.IncidentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockAdminIncidentServiceMockRecorder) List(ctx, page, limit interface{}) *gomock.Call {
+func (mr *MockAdminIncidentServiceMockRecorder) List(ctx, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAdminIncidentService)(nil).List), ctx, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAdminIncidentService)(nil).List), ctx, req)
 }
 
 // Update mocks base method.
@@ -134,32 +133,62 @@ func (m *MockIncidentRepository) EXPECT() *MockIncidentRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ActivateDue mocks base method.
+func (m *MockIncidentRepository) ActivateDue(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateDue", ctx, now)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateDue indicates an expected call of ActivateDue.
+func (mr *MockIncidentRepositoryMockRecorder) ActivateDue(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateDue", reflect.TypeOf((*MockIncidentRepository)(nil).ActivateDue), ctx, now)
+}
+
 // Create mocks base method.
-func (m *MockIncidentRepository) Create(ctx context.Context, incident *domain.Incident) error {
+func (m *MockIncidentRepository) Create(ctx context.Context, incident *domain.Incident, event domain.IncidentEventFunc) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, incident)
+	ret := m.ctrl.Call(m, "Create", ctx, incident, event)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockIncidentRepositoryMockRecorder) Create(ctx, incident interface{}) *gomock.Call {
+func (mr *MockIncidentRepositoryMockRecorder) Create(ctx, incident, event interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIncidentRepository)(nil).Create), ctx, incident)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIncidentRepository)(nil).Create), ctx, incident, event)
 }
 
 // Delete mocks base method.
-func (m *MockIncidentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (m *MockIncidentRepository) Delete(ctx context.Context, id uuid.UUID, event domain.IncidentEventFunc) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, event)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockIncidentRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+func (mr *MockIncidentRepositoryMockRecorder) Delete(ctx, id, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIncidentRepository)(nil).Delete), ctx, id, event)
+}
+
+// ExpireDue mocks base method.
+func (m *MockIncidentRepository) ExpireDue(ctx context.Context, now time.Time, event domain.IncidentEventFunc) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireDue", ctx, now, event)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireDue indicates an expected call of ExpireDue.
+func (mr *MockIncidentRepositoryMockRecorder) ExpireDue(ctx, now, event interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIncidentRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireDue", reflect.TypeOf((*MockIncidentRepository)(nil).ExpireDue), ctx, now, event)
 }
 
 // Get mocks base method.
@@ -178,19 +207,18 @@ func (mr *MockIncidentRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.C
 }
 
 // List mocks base method.
-func (m *MockIncidentRepository) List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error) {
+func (m *MockIncidentRepository) List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, page, limit)
-	ret0, _ := ret[0].([]*domain.Incident)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(domain.IncidentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockIncidentRepositoryMockRecorder) List(ctx, page, limit interface{}) *gomock.Call {
+func (mr *MockIncidentRepositoryMockRecorder) List(ctx, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIncidentRepository)(nil).List), ctx, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIncidentRepository)(nil).List), ctx, req)
 }
 
 // ListActive mocks base method.
@@ -208,18 +236,33 @@ func (mr *MockIncidentRepositoryMockRecorder) ListActive(ctx interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockIncidentRepository)(nil).ListActive), ctx)
 }
 
+// NextScheduleBoundary mocks base method.
+func (m *MockIncidentRepository) NextScheduleBoundary(ctx context.Context, now time.Time) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextScheduleBoundary", ctx, now)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextScheduleBoundary indicates an expected call of NextScheduleBoundary.
+func (mr *MockIncidentRepositoryMockRecorder) NextScheduleBoundary(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextScheduleBoundary", reflect.TypeOf((*MockIncidentRepository)(nil).NextScheduleBoundary), ctx, now)
+}
+
 // Update mocks base method.
-func (m *MockIncidentRepository) Update(ctx context.Context, incident *domain.Incident) error {
+func (m *MockIncidentRepository) Update(ctx context.Context, incident *domain.Incident, event domain.IncidentEventFunc) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, incident)
+	ret := m.ctrl.Call(m, "Update", ctx, incident, event)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockIncidentRepositoryMockRecorder) Update(ctx, incident interface{}) *gomock.Call {
+func (mr *MockIncidentRepositoryMockRecorder) Update(ctx, incident, event interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIncidentRepository)(nil).Update), ctx, incident)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIncidentRepository)(nil).Update), ctx, incident, event)
 }
 
 // MockIncidentCacheService is a mock of IncidentCacheService interface.
@@ -274,6 +317,112 @@ func (mr *MockIncidentCacheServiceMockRecorder) SetActive(ctx, incidents, ttl in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActive", reflect.TypeOf((*MockIncidentCacheService)(nil).SetActive), ctx, incidents, ttl)
 }
 
+// MockIncidentCacheVersion is a mock of IncidentCacheVersion interface.
+type MockIncidentCacheVersion struct {
+	ctrl     *gomock.Controller
+	recorder *MockIncidentCacheVersionMockRecorder
+}
+
+// MockIncidentCacheVersionMockRecorder is the mock recorder for MockIncidentCacheVersion.
+type MockIncidentCacheVersionMockRecorder struct {
+	mock *MockIncidentCacheVersion
+}
+
+// NewMockIncidentCacheVersion creates a new mock instance.
+func NewMockIncidentCacheVersion(ctrl *gomock.Controller) *MockIncidentCacheVersion {
+	mock := &MockIncidentCacheVersion{ctrl: ctrl}
+	mock.recorder = &MockIncidentCacheVersionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIncidentCacheVersion) EXPECT() *MockIncidentCacheVersionMockRecorder {
+	return m.recorder
+}
+
+// GetActiveVersion mocks base method.
+func (m *MockIncidentCacheVersion) GetActiveVersion(ctx context.Context) ([]domain.CachedIncident, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveVersion", ctx)
+	ret0, _ := ret[0].([]domain.CachedIncident)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetActiveVersion indicates an expected call of GetActiveVersion.
+func (mr *MockIncidentCacheVersionMockRecorder) GetActiveVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveVersion", reflect.TypeOf((*MockIncidentCacheVersion)(nil).GetActiveVersion), ctx)
+}
+
+// Version mocks base method.
+func (m *MockIncidentCacheVersion) Version(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockIncidentCacheVersionMockRecorder) Version(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockIncidentCacheVersion)(nil).Version), ctx)
+}
+
+// MockAlertTracker is a mock of AlertTracker interface.
+type MockAlertTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertTrackerMockRecorder
+}
+
+// MockAlertTrackerMockRecorder is the mock recorder for MockAlertTracker.
+type MockAlertTrackerMockRecorder struct {
+	mock *MockAlertTracker
+}
+
+// NewMockAlertTracker creates a new mock instance.
+func NewMockAlertTracker(ctrl *gomock.Controller) *MockAlertTracker {
+	mock := &MockAlertTracker{ctrl: ctrl}
+	mock.recorder = &MockAlertTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertTracker) EXPECT() *MockAlertTrackerMockRecorder {
+	return m.recorder
+}
+
+// Revert mocks base method.
+func (m *MockAlertTracker) Revert(ctx context.Context, userID string, tr domain.AlertTransitions, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", ctx, userID, tr, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockAlertTrackerMockRecorder) Revert(ctx, userID, tr, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockAlertTracker)(nil).Revert), ctx, userID, tr, now)
+}
+
+// Track mocks base method.
+func (m *MockAlertTracker) Track(ctx context.Context, userID string, hits []uuid.UUID, now time.Time) (domain.AlertTransitions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Track", ctx, userID, hits, now)
+	ret0, _ := ret[0].(domain.AlertTransitions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Track indicates an expected call of Track.
+func (mr *MockAlertTrackerMockRecorder) Track(ctx, userID, hits, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockAlertTracker)(nil).Track), ctx, userID, hits, now)
+}
+
 // MockPublicIncidentService is a mock of PublicIncidentService interface.
 type MockPublicIncidentService struct {
 	ctrl     *gomock.Controller
@@ -312,6 +461,66 @@ func (mr *MockPublicIncidentServiceMockRecorder) CheckLocation(ctx, req interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLocation", reflect.TypeOf((*MockPublicIncidentService)(nil).CheckLocation), ctx, req)
 }
 
+// CheckLocationBatch mocks base method.
+func (m *MockPublicIncidentService) CheckLocationBatch(ctx context.Context, req domain.LocationCheckBatchRequest) (domain.LocationCheckBatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLocationBatch", ctx, req)
+	ret0, _ := ret[0].(domain.LocationCheckBatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLocationBatch indicates an expected call of CheckLocationBatch.
+func (mr *MockPublicIncidentServiceMockRecorder) CheckLocationBatch(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLocationBatch", reflect.TypeOf((*MockPublicIncidentService)(nil).CheckLocationBatch), ctx, req)
+}
+
+// CheckLocationV2 mocks base method.
+func (m *MockPublicIncidentService) CheckLocationV2(ctx context.Context, req domain.LocationCheckRequest) (domain.LocationCheckV2Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLocationV2", ctx, req)
+	ret0, _ := ret[0].(domain.LocationCheckV2Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLocationV2 indicates an expected call of CheckLocationV2.
+func (mr *MockPublicIncidentServiceMockRecorder) CheckLocationV2(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLocationV2", reflect.TypeOf((*MockPublicIncidentService)(nil).CheckLocationV2), ctx, req)
+}
+
+// CheckRoute mocks base method.
+func (m *MockPublicIncidentService) CheckRoute(ctx context.Context, req domain.RouteCheckRequest) (domain.RouteCheckResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckRoute", ctx, req)
+	ret0, _ := ret[0].(domain.RouteCheckResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckRoute indicates an expected call of CheckRoute.
+func (mr *MockPublicIncidentServiceMockRecorder) CheckRoute(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRoute", reflect.TypeOf((*MockPublicIncidentService)(nil).CheckRoute), ctx, req)
+}
+
+// NearbyIncidents mocks base method.
+func (m *MockPublicIncidentService) NearbyIncidents(ctx context.Context, req domain.NearbyRequest) (domain.NearbyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NearbyIncidents", ctx, req)
+	ret0, _ := ret[0].(domain.NearbyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NearbyIncidents indicates an expected call of NearbyIncidents.
+func (mr *MockPublicIncidentServiceMockRecorder) NearbyIncidents(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NearbyIncidents", reflect.TypeOf((*MockPublicIncidentService)(nil).NearbyIncidents), ctx, req)
+}
+
 // MockStatsService is a mock of StatsService interface.
 type MockStatsService struct {
 	ctrl     *gomock.Controller
@@ -335,6 +544,36 @@ func (m *MockStatsService) EXPECT() *MockStatsServiceMockRecorder {
 	return m.recorder
 }
 
+// GetIncidentStats mocks base method.
+func (m *MockStatsService) GetIncidentStats(ctx context.Context, id uuid.UUID, req domain.IncidentStatsRequest) (*domain.IncidentHitStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentStats", ctx, id, req)
+	ret0, _ := ret[0].(*domain.IncidentHitStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentStats indicates an expected call of GetIncidentStats.
+func (mr *MockStatsServiceMockRecorder) GetIncidentStats(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentStats", reflect.TypeOf((*MockStatsService)(nil).GetIncidentStats), ctx, id, req)
+}
+
+// GetSeries mocks base method.
+func (m *MockStatsService) GetSeries(ctx context.Context, req domain.StatsSeriesRequest) (*domain.IncidentStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeries", ctx, req)
+	ret0, _ := ret[0].(*domain.IncidentStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeries indicates an expected call of GetSeries.
+func (mr *MockStatsServiceMockRecorder) GetSeries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeries", reflect.TypeOf((*MockStatsService)(nil).GetSeries), ctx, req)
+}
+
 // GetStats mocks base method.
 func (m *MockStatsService) GetStats(ctx context.Context, req domain.StatsRequest) (*domain.IncidentStats, error) {
 	m.ctrl.T.Helper()
@@ -349,3 +588,786 @@ func (mr *MockStatsServiceMockRecorder) GetStats(ctx, req interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockStatsService)(nil).GetStats), ctx, req)
 }
+
+// MockStatsRepository is a mock of StatsRepository interface.
+type MockStatsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsRepositoryMockRecorder
+}
+
+// MockStatsRepositoryMockRecorder is the mock recorder for MockStatsRepository.
+type MockStatsRepositoryMockRecorder struct {
+	mock *MockStatsRepository
+}
+
+// NewMockStatsRepository creates a new mock instance.
+func NewMockStatsRepository(ctrl *gomock.Controller) *MockStatsRepository {
+	mock := &MockStatsRepository{ctrl: ctrl}
+	mock.recorder = &MockStatsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsRepository) EXPECT() *MockStatsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CheckSeries mocks base method.
+func (m *MockStatsRepository) CheckSeries(ctx context.Context, from, to time.Time, bucket domain.StatsBucket) ([]domain.StatsPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSeries", ctx, from, to, bucket)
+	ret0, _ := ret[0].([]domain.StatsPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckSeries indicates an expected call of CheckSeries.
+func (mr *MockStatsRepositoryMockRecorder) CheckSeries(ctx, from, to, bucket interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSeries", reflect.TypeOf((*MockStatsRepository)(nil).CheckSeries), ctx, from, to, bucket)
+}
+
+// CountRange mocks base method.
+func (m *MockStatsRepository) CountRange(ctx context.Context, from, to time.Time) (domain.StatsPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRange", ctx, from, to)
+	ret0, _ := ret[0].(domain.StatsPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRange indicates an expected call of CountRange.
+func (mr *MockStatsRepositoryMockRecorder) CountRange(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRange", reflect.TypeOf((*MockStatsRepository)(nil).CountRange), ctx, from, to)
+}
+
+// CountTotalChecks mocks base method.
+func (m *MockStatsRepository) CountTotalChecks(ctx context.Context, minutes int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTotalChecks", ctx, minutes)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTotalChecks indicates an expected call of CountTotalChecks.
+func (mr *MockStatsRepositoryMockRecorder) CountTotalChecks(ctx, minutes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTotalChecks", reflect.TypeOf((*MockStatsRepository)(nil).CountTotalChecks), ctx, minutes)
+}
+
+// CountUniqueUsers mocks base method.
+func (m *MockStatsRepository) CountUniqueUsers(ctx context.Context, minutes int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUniqueUsers", ctx, minutes)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUniqueUsers indicates an expected call of CountUniqueUsers.
+func (mr *MockStatsRepositoryMockRecorder) CountUniqueUsers(ctx, minutes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUniqueUsers", reflect.TypeOf((*MockStatsRepository)(nil).CountUniqueUsers), ctx, minutes)
+}
+
+// HitsByIncident mocks base method.
+func (m *MockStatsRepository) HitsByIncident(ctx context.Context, from, to time.Time, limit int) ([]domain.IncidentHits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HitsByIncident", ctx, from, to, limit)
+	ret0, _ := ret[0].([]domain.IncidentHits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HitsByIncident indicates an expected call of HitsByIncident.
+func (mr *MockStatsRepositoryMockRecorder) HitsByIncident(ctx, from, to, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HitsByIncident", reflect.TypeOf((*MockStatsRepository)(nil).HitsByIncident), ctx, from, to, limit)
+}
+
+// IncidentHitStats mocks base method.
+func (m *MockStatsRepository) IncidentHitStats(ctx context.Context, id uuid.UUID, from, to time.Time) (domain.IncidentHitStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncidentHitStats", ctx, id, from, to)
+	ret0, _ := ret[0].(domain.IncidentHitStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncidentHitStats indicates an expected call of IncidentHitStats.
+func (mr *MockStatsRepositoryMockRecorder) IncidentHitStats(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncidentHitStats", reflect.TypeOf((*MockStatsRepository)(nil).IncidentHitStats), ctx, id, from, to)
+}
+
+// MockCheckHistoryService is a mock of CheckHistoryService interface.
+type MockCheckHistoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckHistoryServiceMockRecorder
+}
+
+// MockCheckHistoryServiceMockRecorder is the mock recorder for MockCheckHistoryService.
+type MockCheckHistoryServiceMockRecorder struct {
+	mock *MockCheckHistoryService
+}
+
+// NewMockCheckHistoryService creates a new mock instance.
+func NewMockCheckHistoryService(ctrl *gomock.Controller) *MockCheckHistoryService {
+	mock := &MockCheckHistoryService{ctrl: ctrl}
+	mock.recorder = &MockCheckHistoryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckHistoryService) EXPECT() *MockCheckHistoryServiceMockRecorder {
+	return m.recorder
+}
+
+// IncidentOccupants mocks base method.
+func (m *MockCheckHistoryService) IncidentOccupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncidentOccupants", ctx, incidentID, req)
+	ret0, _ := ret[0].(domain.OccupantPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncidentOccupants indicates an expected call of IncidentOccupants.
+func (mr *MockCheckHistoryServiceMockRecorder) IncidentOccupants(ctx, incidentID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncidentOccupants", reflect.TypeOf((*MockCheckHistoryService)(nil).IncidentOccupants), ctx, incidentID, req)
+}
+
+// IncidentVisitors mocks base method.
+func (m *MockCheckHistoryService) IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncidentVisitors", ctx, incidentID, req)
+	ret0, _ := ret[0].(domain.VisitorPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncidentVisitors indicates an expected call of IncidentVisitors.
+func (mr *MockCheckHistoryServiceMockRecorder) IncidentVisitors(ctx, incidentID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncidentVisitors", reflect.TypeOf((*MockCheckHistoryService)(nil).IncidentVisitors), ctx, incidentID, req)
+}
+
+// ListChecks mocks base method.
+func (m *MockCheckHistoryService) ListChecks(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChecks", ctx, req)
+	ret0, _ := ret[0].(domain.CheckPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChecks indicates an expected call of ListChecks.
+func (mr *MockCheckHistoryServiceMockRecorder) ListChecks(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChecks", reflect.TypeOf((*MockCheckHistoryService)(nil).ListChecks), ctx, req)
+}
+
+// UserTimeline mocks base method.
+func (m *MockCheckHistoryService) UserTimeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserTimeline", ctx, userID, req)
+	ret0, _ := ret[0].(domain.TimelinePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserTimeline indicates an expected call of UserTimeline.
+func (mr *MockCheckHistoryServiceMockRecorder) UserTimeline(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserTimeline", reflect.TypeOf((*MockCheckHistoryService)(nil).UserTimeline), ctx, userID, req)
+}
+
+// MockCheckHistoryRepository is a mock of CheckHistoryRepository interface.
+type MockCheckHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckHistoryRepositoryMockRecorder
+}
+
+// MockCheckHistoryRepositoryMockRecorder is the mock recorder for MockCheckHistoryRepository.
+type MockCheckHistoryRepositoryMockRecorder struct {
+	mock *MockCheckHistoryRepository
+}
+
+// NewMockCheckHistoryRepository creates a new mock instance.
+func NewMockCheckHistoryRepository(ctrl *gomock.Controller) *MockCheckHistoryRepository {
+	mock := &MockCheckHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockCheckHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckHistoryRepository) EXPECT() *MockCheckHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// IncidentVisitors mocks base method.
+func (m *MockCheckHistoryRepository) IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncidentVisitors", ctx, incidentID, req)
+	ret0, _ := ret[0].(domain.VisitorPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncidentVisitors indicates an expected call of IncidentVisitors.
+func (mr *MockCheckHistoryRepositoryMockRecorder) IncidentVisitors(ctx, incidentID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncidentVisitors", reflect.TypeOf((*MockCheckHistoryRepository)(nil).IncidentVisitors), ctx, incidentID, req)
+}
+
+// List mocks base method.
+func (m *MockCheckHistoryRepository) List(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(domain.CheckPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCheckHistoryRepositoryMockRecorder) List(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCheckHistoryRepository)(nil).List), ctx, req)
+}
+
+// Occupants mocks base method.
+func (m *MockCheckHistoryRepository) Occupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Occupants", ctx, incidentID, req)
+	ret0, _ := ret[0].(domain.OccupantPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Occupants indicates an expected call of Occupants.
+func (mr *MockCheckHistoryRepositoryMockRecorder) Occupants(ctx, incidentID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Occupants", reflect.TypeOf((*MockCheckHistoryRepository)(nil).Occupants), ctx, incidentID, req)
+}
+
+// Timeline mocks base method.
+func (m *MockCheckHistoryRepository) Timeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeline", ctx, userID, req)
+	ret0, _ := ret[0].(domain.TimelinePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Timeline indicates an expected call of Timeline.
+func (mr *MockCheckHistoryRepositoryMockRecorder) Timeline(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeline", reflect.TypeOf((*MockCheckHistoryRepository)(nil).Timeline), ctx, userID, req)
+}
+
+// MockWebhookDeadLetterService is a mock of WebhookDeadLetterService interface.
+type MockWebhookDeadLetterService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeadLetterServiceMockRecorder
+}
+
+// MockWebhookDeadLetterServiceMockRecorder is the mock recorder for MockWebhookDeadLetterService.
+type MockWebhookDeadLetterServiceMockRecorder struct {
+	mock *MockWebhookDeadLetterService
+}
+
+// NewMockWebhookDeadLetterService creates a new mock instance.
+func NewMockWebhookDeadLetterService(ctrl *gomock.Controller) *MockWebhookDeadLetterService {
+	mock := &MockWebhookDeadLetterService{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeadLetterServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeadLetterService) EXPECT() *MockWebhookDeadLetterServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteDeadLetter mocks base method.
+func (m *MockWebhookDeadLetterService) DeleteDeadLetter(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetter", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetter indicates an expected call of DeleteDeadLetter.
+func (mr *MockWebhookDeadLetterServiceMockRecorder) DeleteDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetter", reflect.TypeOf((*MockWebhookDeadLetterService)(nil).DeleteDeadLetter), ctx, id)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockWebhookDeadLetterService) GetDeadLetter(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", ctx, id)
+	ret0, _ := ret[0].(*domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockWebhookDeadLetterServiceMockRecorder) GetDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockWebhookDeadLetterService)(nil).GetDeadLetter), ctx, id)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockWebhookDeadLetterService) ListDeadLetters(ctx context.Context, req domain.ListDeadLettersRequest) (domain.DeadLetterPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, req)
+	ret0, _ := ret[0].(domain.DeadLetterPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockWebhookDeadLetterServiceMockRecorder) ListDeadLetters(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockWebhookDeadLetterService)(nil).ListDeadLetters), ctx, req)
+}
+
+// PurgeDeadLetters mocks base method.
+func (m *MockWebhookDeadLetterService) PurgeDeadLetters(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeadLetters", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeadLetters indicates an expected call of PurgeDeadLetters.
+func (mr *MockWebhookDeadLetterServiceMockRecorder) PurgeDeadLetters(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeadLetters", reflect.TypeOf((*MockWebhookDeadLetterService)(nil).PurgeDeadLetters), ctx)
+}
+
+// ReplayDeadLetter mocks base method.
+func (m *MockWebhookDeadLetterService) ReplayDeadLetter(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayDeadLetter", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayDeadLetter indicates an expected call of ReplayDeadLetter.
+func (mr *MockWebhookDeadLetterServiceMockRecorder) ReplayDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetter", reflect.TypeOf((*MockWebhookDeadLetterService)(nil).ReplayDeadLetter), ctx, id)
+}
+
+// MockDeadLetterStore is a mock of DeadLetterStore interface.
+type MockDeadLetterStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterStoreMockRecorder
+}
+
+// MockDeadLetterStoreMockRecorder is the mock recorder for MockDeadLetterStore.
+type MockDeadLetterStoreMockRecorder struct {
+	mock *MockDeadLetterStore
+}
+
+// NewMockDeadLetterStore creates a new mock instance.
+func NewMockDeadLetterStore(ctrl *gomock.Controller) *MockDeadLetterStore {
+	mock := &MockDeadLetterStore{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterStore) EXPECT() *MockDeadLetterStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteDeadLetter mocks base method.
+func (m *MockDeadLetterStore) DeleteDeadLetter(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetter", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetter indicates an expected call of DeleteDeadLetter.
+func (mr *MockDeadLetterStoreMockRecorder) DeleteDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetter", reflect.TypeOf((*MockDeadLetterStore)(nil).DeleteDeadLetter), ctx, id)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockDeadLetterStore) GetDeadLetter(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", ctx, id)
+	ret0, _ := ret[0].(*domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockDeadLetterStoreMockRecorder) GetDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockDeadLetterStore)(nil).GetDeadLetter), ctx, id)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockDeadLetterStore) ListDeadLetters(ctx context.Context, offset, limit int) ([]domain.DeadLetter, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.DeadLetter)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockDeadLetterStoreMockRecorder) ListDeadLetters(ctx, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockDeadLetterStore)(nil).ListDeadLetters), ctx, offset, limit)
+}
+
+// PurgeDeadLetters mocks base method.
+func (m *MockDeadLetterStore) PurgeDeadLetters(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeadLetters", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeadLetters indicates an expected call of PurgeDeadLetters.
+func (mr *MockDeadLetterStoreMockRecorder) PurgeDeadLetters(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeadLetters", reflect.TypeOf((*MockDeadLetterStore)(nil).PurgeDeadLetters), ctx)
+}
+
+// ReplayDeadLetter mocks base method.
+func (m *MockDeadLetterStore) ReplayDeadLetter(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayDeadLetter", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayDeadLetter indicates an expected call of ReplayDeadLetter.
+func (mr *MockDeadLetterStoreMockRecorder) ReplayDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetter", reflect.TypeOf((*MockDeadLetterStore)(nil).ReplayDeadLetter), ctx, id)
+}
+
+// MockWebhookSubscriptionService is a mock of WebhookSubscriptionService interface.
+type MockWebhookSubscriptionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookSubscriptionServiceMockRecorder
+}
+
+// MockWebhookSubscriptionServiceMockRecorder is the mock recorder for MockWebhookSubscriptionService.
+type MockWebhookSubscriptionServiceMockRecorder struct {
+	mock *MockWebhookSubscriptionService
+}
+
+// NewMockWebhookSubscriptionService creates a new mock instance.
+func NewMockWebhookSubscriptionService(ctrl *gomock.Controller) *MockWebhookSubscriptionService {
+	mock := &MockWebhookSubscriptionService{ctrl: ctrl}
+	mock.recorder = &MockWebhookSubscriptionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookSubscriptionService) EXPECT() *MockWebhookSubscriptionServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateSubscription mocks base method.
+func (m *MockWebhookSubscriptionService) CreateSubscription(ctx context.Context, req domain.CreateWebhookSubscriptionRequest) (domain.CreateWebhookSubscriptionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", ctx, req)
+	ret0, _ := ret[0].(domain.CreateWebhookSubscriptionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockWebhookSubscriptionServiceMockRecorder) CreateSubscription(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockWebhookSubscriptionService)(nil).CreateSubscription), ctx, req)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockWebhookSubscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockWebhookSubscriptionServiceMockRecorder) DeleteSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockWebhookSubscriptionService)(nil).DeleteSubscription), ctx, id)
+}
+
+// GetSubscription mocks base method.
+func (m *MockWebhookSubscriptionService) GetSubscription(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", ctx, id)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockWebhookSubscriptionServiceMockRecorder) GetSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockWebhookSubscriptionService)(nil).GetSubscription), ctx, id)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockWebhookSubscriptionService) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", ctx)
+	ret0, _ := ret[0].([]*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockWebhookSubscriptionServiceMockRecorder) ListSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockWebhookSubscriptionService)(nil).ListSubscriptions), ctx)
+}
+
+// RotateSecret mocks base method.
+func (m *MockWebhookSubscriptionService) RotateSecret(ctx context.Context, id uuid.UUID, req domain.RotateWebhookSecretRequest) (domain.RotateWebhookSecretResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSecret", ctx, id, req)
+	ret0, _ := ret[0].(domain.RotateWebhookSecretResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSecret indicates an expected call of RotateSecret.
+func (mr *MockWebhookSubscriptionServiceMockRecorder) RotateSecret(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSecret", reflect.TypeOf((*MockWebhookSubscriptionService)(nil).RotateSecret), ctx, id, req)
+}
+
+// UpdateSubscription mocks base method.
+func (m *MockWebhookSubscriptionService) UpdateSubscription(ctx context.Context, id uuid.UUID, req domain.UpdateWebhookSubscriptionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscription", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSubscription indicates an expected call of UpdateSubscription.
+func (mr *MockWebhookSubscriptionServiceMockRecorder) UpdateSubscription(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscription", reflect.TypeOf((*MockWebhookSubscriptionService)(nil).UpdateSubscription), ctx, id, req)
+}
+
+// MockWebhookSubscriptionRepository is a mock of WebhookSubscriptionRepository interface.
+type MockWebhookSubscriptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookSubscriptionRepositoryMockRecorder
+}
+
+// MockWebhookSubscriptionRepositoryMockRecorder is the mock recorder for MockWebhookSubscriptionRepository.
+type MockWebhookSubscriptionRepositoryMockRecorder struct {
+	mock *MockWebhookSubscriptionRepository
+}
+
+// NewMockWebhookSubscriptionRepository creates a new mock instance.
+func NewMockWebhookSubscriptionRepository(ctrl *gomock.Controller) *MockWebhookSubscriptionRepository {
+	mock := &MockWebhookSubscriptionRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookSubscriptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookSubscriptionRepository) EXPECT() *MockWebhookSubscriptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookSubscriptionRepository) Create(ctx context.Context, sub *domain.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookSubscriptionRepositoryMockRecorder) Create(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookSubscriptionRepository)(nil).Create), ctx, sub)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookSubscriptionRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookSubscriptionRepository)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockWebhookSubscriptionRepository) Get(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhookSubscriptionRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhookSubscriptionRepository)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockWebhookSubscriptionRepository) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookSubscriptionRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookSubscriptionRepository)(nil).List), ctx)
+}
+
+// ListActive mocks base method.
+func (m *MockWebhookSubscriptionRepository) ListActive(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", ctx)
+	ret0, _ := ret[0].([]*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockWebhookSubscriptionRepositoryMockRecorder) ListActive(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockWebhookSubscriptionRepository)(nil).ListActive), ctx)
+}
+
+// Update mocks base method.
+func (m *MockWebhookSubscriptionRepository) Update(ctx context.Context, sub *domain.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookSubscriptionRepositoryMockRecorder) Update(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookSubscriptionRepository)(nil).Update), ctx, sub)
+}
+
+// MockWebhookDeliveryLogService is a mock of WebhookDeliveryLogService interface.
+type MockWebhookDeliveryLogService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveryLogServiceMockRecorder
+}
+
+// MockWebhookDeliveryLogServiceMockRecorder is the mock recorder for MockWebhookDeliveryLogService.
+type MockWebhookDeliveryLogServiceMockRecorder struct {
+	mock *MockWebhookDeliveryLogService
+}
+
+// NewMockWebhookDeliveryLogService creates a new mock instance.
+func NewMockWebhookDeliveryLogService(ctrl *gomock.Controller) *MockWebhookDeliveryLogService {
+	mock := &MockWebhookDeliveryLogService{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveryLogServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveryLogService) EXPECT() *MockWebhookDeliveryLogServiceMockRecorder {
+	return m.recorder
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookDeliveryLogService) ListDeliveries(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, req)
+	ret0, _ := ret[0].(domain.WebhookDeliveryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookDeliveryLogServiceMockRecorder) ListDeliveries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookDeliveryLogService)(nil).ListDeliveries), ctx, req)
+}
+
+// MockWebhookDeliveryRepository is a mock of WebhookDeliveryRepository interface.
+type MockWebhookDeliveryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveryRepositoryMockRecorder
+}
+
+// MockWebhookDeliveryRepositoryMockRecorder is the mock recorder for MockWebhookDeliveryRepository.
+type MockWebhookDeliveryRepositoryMockRecorder struct {
+	mock *MockWebhookDeliveryRepository
+}
+
+// NewMockWebhookDeliveryRepository creates a new mock instance.
+func NewMockWebhookDeliveryRepository(ctrl *gomock.Controller) *MockWebhookDeliveryRepository {
+	mock := &MockWebhookDeliveryRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveryRepository) EXPECT() *MockWebhookDeliveryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockWebhookDeliveryRepository) List(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(domain.WebhookDeliveryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookDeliveryRepositoryMockRecorder) List(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookDeliveryRepository)(nil).List), ctx, req)
+}
+
+// Save mocks base method.
+func (m *MockWebhookDeliveryRepository) Save(ctx context.Context, a *domain.WebhookDeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockWebhookDeliveryRepositoryMockRecorder) Save(ctx, a interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockWebhookDeliveryRepository)(nil).Save), ctx, a)
+}
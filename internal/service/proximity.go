@@ -0,0 +1,82 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/internal/geoindex"
+)
+
+// classifyIncidents делит активные в now инциденты на те, в чьей зоне точка
+// (inside), и те, до границы которых снаружи не больше bufferKm (approaching).
+// inside — по расстоянию до центра, approaching — по расстоянию до границы.
+func classifyIncidents(incidents []domain.CachedIncident, lat, lng float64, now time.Time, bufferKm float64) (inside, approaching []domain.IncidentProximity) {
+	inside = make([]domain.IncidentProximity, 0)
+	approaching = make([]domain.IncidentProximity, 0)
+
+	for _, inc := range incidents {
+		if !inc.ActiveAt(now) {
+			continue
+		}
+
+		p := domain.IncidentProximity{
+			Incident:   inc.Summary(),
+			DistanceKM: haversine(lat, lng, inc.Lat, inc.Lng),
+		}
+		if inc.Geometry != nil {
+			p.Inside = inc.Geometry.Contains(lat, lng)
+			if !p.Inside && !geoindex.BoxOf(inc, bufferKm).Contains(lat, lng) {
+				continue
+			}
+			p.DistanceToEdgeKM = edgeDistanceKM(inc.Geometry, lat, lng)
+		} else {
+			p.Inside = p.DistanceKM <= inc.RadiusKM
+			p.DistanceToEdgeKM = math.Abs(inc.RadiusKM - p.DistanceKM)
+		}
+
+		switch {
+		case p.Inside:
+			inside = append(inside, p)
+		case bufferKm > 0 && p.DistanceToEdgeKM <= bufferKm:
+			p.InBuffer = true
+			approaching = append(approaching, p)
+		}
+	}
+
+	sort.SliceStable(inside, func(i, j int) bool { return inside[i].DistanceKM < inside[j].DistanceKM })
+	sort.SliceStable(approaching, func(i, j int) bool {
+		return approaching[i].DistanceToEdgeKM < approaching[j].DistanceToEdgeKM
+	})
+	return inside, approaching
+}
+
+// edgeDistanceKM — расстояние от точки до ближайшего ребра любого кольца
+// (внешней границы или дыры) в локальной плоской проекции вокруг точки.
+func edgeDistanceKM(g *domain.Geometry, lat, lng float64) float64 {
+	kx := deg2rad(1) * earthRadiusKm * math.Cos(deg2rad(lat))
+	ky := deg2rad(1) * earthRadiusKm
+
+	best := math.Inf(1)
+	for _, poly := range g.Polygons {
+		for _, ring := range poly {
+			for i := 1; i < len(ring); i++ {
+				ax, ay := (ring[i-1][0]-lng)*kx, (ring[i-1][1]-lat)*ky
+				bx, by := (ring[i][0]-lng)*kx, (ring[i][1]-lat)*ky
+				best = math.Min(best, originToSegment(ax, ay, bx, by))
+			}
+		}
+	}
+	return best
+}
+
+// originToSegment — расстояние от начала координат до отрезка a–b.
+func originToSegment(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l))
+	}
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
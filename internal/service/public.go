@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"log/slog"
@@ -14,42 +15,96 @@ import (
 	"github.com/google/uuid"
 )
 
+// CheckSaver сохраняет проверку, присутствие пользователя в инцидентах и
+// события проверки одной транзакцией.
 type CheckSaver interface {
-	SaveCheck(ctx context.Context, check *domain.LocationCheck) error
+	SaveCheck(ctx context.Context, check *domain.LocationCheck, events domain.CheckEventFunc) error
+	SaveChecks(ctx context.Context, checks []*domain.LocationCheck, events domain.CheckBatchEventFunc) error
+	// LastApplied — время последней применённой проверки по пользователям.
+	LastApplied(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]time.Time, error)
 }
 
-type WebhookQueue interface {
-	Enqueue(ctx context.Context, payload domain.WebhookPayload) error
+// IncidentFinder ищет активные инциденты вокруг точки, ближние первыми.
+type IncidentFinder interface {
+	FindNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]domain.NearbyIncident, error)
 }
 
+// maxCheckClockSkew — насколько время точки может опережать часы сервера.
+const maxCheckClockSkew = 5 * time.Minute
+
 type publicIncidentService struct {
 	cache           IncidentCacheService
 	checkSaver      CheckSaver
-	webhookQueue    WebhookQueue
+	finder          IncidentFinder
+	alerts          AlertTracker
 	logger          *slog.Logger
 	defaultRadiusKm float64
+	bufferKm        float64
+	active          *activeIndex
+}
+
+// locationOutcome — результат проверки точки до приведения к v1 или v2.
+type locationOutcome struct {
+	inside      []domain.IncidentProximity
+	approaching []domain.IncidentProximity
+	tr          domain.AlertTransitions
 }
 
 func NewPublicIncidentService(
 	cache IncidentCacheService,
 	checkSaver CheckSaver,
-	q WebhookQueue,
+	finder IncidentFinder,
+	alerts AlertTracker,
 	logger *slog.Logger,
 	defaultRadiusKm float64,
+	bufferKm float64,
 ) PublicIncidentService {
 	if defaultRadiusKm <= 0 {
 		defaultRadiusKm = 1.0
 	}
+	bufferKm = math.Max(bufferKm, 0)
 	return &publicIncidentService{
 		cache:           cache,
 		checkSaver:      checkSaver,
-		webhookQueue:    q,
+		finder:          finder,
+		alerts:          alerts,
 		logger:          logger,
 		defaultRadiusKm: defaultRadiusKm,
+		bufferKm:        bufferKm,
+		active:          newActiveIndex(cache, bufferKm),
 	}
 }
 
 func (s *publicIncidentService) CheckLocation(ctx context.Context, req domain.LocationCheckRequest) (domain.LocationCheckResponse, error) {
+	out, err := s.checkLocation(ctx, req)
+	if err != nil {
+		return domain.LocationCheckResponse{}, err
+	}
+
+	summaries := make([]domain.IncidentSummary, 0, len(out.inside))
+	for _, p := range out.inside {
+		summaries = append(summaries, p.Incident)
+	}
+	return domain.LocationCheckResponse{Incidents: summaries, New: out.tr.Entered, Known: out.tr.Known}, nil
+}
+
+// CheckLocationV2 — та же проверка, но с расстояниями и зонами, к которым
+// пользователь приближается.
+func (s *publicIncidentService) CheckLocationV2(ctx context.Context, req domain.LocationCheckRequest) (domain.LocationCheckV2Response, error) {
+	out, err := s.checkLocation(ctx, req)
+	if err != nil {
+		return domain.LocationCheckV2Response{}, err
+	}
+	return domain.LocationCheckV2Response{
+		Inside:      out.inside,
+		Approaching: out.approaching,
+		New:         out.tr.Entered,
+		Known:       out.tr.Known,
+		BufferKM:    s.bufferKm,
+	}, nil
+}
+
+func (s *publicIncidentService) checkLocation(ctx context.Context, req domain.LocationCheckRequest) (locationOutcome, error) {
 	s.logger.Info("location check START",
 		slog.String("user_id", req.UserID),
 		slog.Float64("lat", req.Lat),
@@ -62,33 +117,38 @@ func (s *publicIncidentService) CheckLocation(ctx context.Context, req domain.Lo
 			slog.Float64("lat", req.Lat),
 			slog.Float64("lng", req.Lng),
 		)
-		return domain.LocationCheckResponse{}, e.ErrInvalidCoordinates
+		return locationOutcome{}, e.ErrInvalidCoordinates
 	}
 
-	incidents, err := s.cache.GetActive(ctx)
+	snap, err := s.active.get(ctx)
 	if err != nil {
-		s.logger.Error("cache.GetActive failed", slog.Any("error", err))
-		return domain.LocationCheckResponse{}, err
+		s.logger.Error("active incidents load failed", slog.Any("error", err))
+		return locationOutcome{}, err
 	}
-	s.logger.Debug("cache loaded", slog.Int("active_incidents", len(incidents)))
+	candidates := snap.grid.Candidates(req.Lat, req.Lng)
+	s.logger.Debug("cache loaded",
+		slog.Int("active_incidents", snap.grid.Len()),
+		slog.Int("candidates", len(candidates)),
+	)
+
+	checkedAt := time.Now().UTC()
 
-	nearby := filterNearby(incidents, req.Lat, req.Lng)
+	inside, approaching := classifyIncidents(candidates, req.Lat, req.Lng, checkedAt, s.bufferKm)
 	s.logger.Info("haversine filter done",
-		slog.Int("total", len(incidents)),
-		slog.Int("nearby", len(nearby)),
+		slog.Int("total", snap.grid.Len()),
+		slog.Int("nearby", len(inside)),
+		slog.Int("approaching", len(approaching)),
 	)
 
-	ids := make([]uuid.UUID, 0, len(nearby))
-	for _, inc := range nearby {
-		ids = append(ids, inc.ID)
+	ids := make([]uuid.UUID, 0, len(inside))
+	for _, p := range inside {
+		ids = append(ids, p.Incident.ID)
 	}
 
-	checkedAt := time.Now().UTC()
-
 	userUUID, err := uuid.Parse(req.UserID)
 	if err != nil {
 		s.logger.Warn("invalid user_id", slog.String("user_id", req.UserID), slog.Any("error", err))
-		return domain.LocationCheckResponse{}, e.ErrInvalidUserID
+		return locationOutcome{}, e.ErrInvalidUserID
 	}
 
 	check := &domain.LocationCheck{
@@ -98,60 +158,279 @@ func (s *publicIncidentService) CheckLocation(ctx context.Context, req domain.Lo
 		IncidentIDs: ids,
 		CheckedAt:   checkedAt,
 	}
-	s.logger.Info("about to save check",
-		slog.String("checkSaver_type", fmt.Sprintf("%T", s.checkSaver)),
-	)
+	if len(ids) == 0 {
+		s.logger.Debug("no incidents nearby")
+	}
+	tr := s.track(ctx, req.UserID, ids, checkedAt)
+
+	// События пишутся в outbox той же транзакцией, что и проверка:
+	// вебхук уходит только для сохранённой проверки.
+	byID := snap.summaries
+	events := func(transitions []domain.OccupancyTransition) ([]domain.WebhookEvent, error) {
+		return s.checkEvents(req, checkedAt, byID, tr, transitions)
+	}
 
-	if err := s.checkSaver.SaveCheck(ctx, check); err != nil {
+	// Без сохранённой проверки нет и её событий: ошибка уходит клиенту, а
+	// состояние алертов откатывается, чтобы повтор снова дал location.hit.
+	if err := s.checkSaver.SaveCheck(ctx, check, events); err != nil {
 		s.logger.Error("save check failed", slog.Any("error", err))
+		s.revert(ctx, req.UserID, tr, checkedAt)
+		return locationOutcome{}, err
+	}
+
+	s.logger.Info("location check END",
+		slog.Int("incidents_found", len(ids)),
+		slog.Int("new", len(tr.Entered)),
+		slog.Int("exited", len(tr.Exited)),
+	)
+	return locationOutcome{inside: inside, approaching: approaching, tr: tr}, nil
+}
+
+// NearbyIncidents — активные инциденты в радиусе от точки для карты
+// предупреждений. Радиус по умолчанию — defaultRadiusKm, не больше
+// MaxNearbyRadiusKM.
+func (s *publicIncidentService) NearbyIncidents(ctx context.Context, req domain.NearbyRequest) (domain.NearbyResponse, error) {
+	if req.Lat < -90 || req.Lat > 90 || req.Lng < -180 || req.Lng > 180 {
+		return domain.NearbyResponse{}, e.ErrInvalidCoordinates
+	}
+
+	radius := req.RadiusKM
+	if radius <= 0 {
+		radius = s.defaultRadiusKm
+	}
+	radius = math.Min(radius, domain.MaxNearbyRadiusKM)
+
+	limit := req.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	incidents, err := s.finder.FindNearby(ctx, req.Lat, req.Lng, radius, limit)
+	if err != nil {
+		s.logger.Error("finder.FindNearby failed", slog.Any("error", err))
+		return domain.NearbyResponse{}, err
+	}
+
+	return domain.NearbyResponse{Incidents: incidents, RadiusKM: radius, Limit: limit}, nil
+}
+
+// CheckLocationBatch проверяет точки, накопленные клиентом, по одному снимку
+// активных инцидентов. Точки одного пользователя применяются по времени,
+// чтобы входы и выходы шли в том порядке, в каком были. В отличие от
+// одиночной проверки ошибка сохранения возвращается: клиент должен
+// отправить пакет повторно, а не выбросить его.
+func (s *publicIncidentService) CheckLocationBatch(ctx context.Context, req domain.LocationCheckBatchRequest) (domain.LocationCheckBatchResponse, error) {
+	if len(req.Points) == 0 || len(req.Points) > domain.MaxCheckBatchPoints {
+		return domain.LocationCheckBatchResponse{}, fmt.Errorf("points: expected 1..%d: %w", domain.MaxCheckBatchPoints, e.ErrInvalidInput)
+	}
+	s.logger.Info("location batch check START", slog.Int("points", len(req.Points)))
+
+	snap, err := s.active.get(ctx)
+	if err != nil {
+		s.logger.Error("active incidents load failed", slog.Any("error", err))
+		return domain.LocationCheckBatchResponse{}, err
+	}
+	byID := snap.summaries
+	now := time.Now().UTC()
+
+	results := make([]domain.LocationCheckPointResult, len(req.Points))
+	order := make([]int, 0, len(req.Points))
+	userIDs := make([]uuid.UUID, len(req.Points))
+	for i, p := range req.Points {
+		results[i].Index = i
+		if err := validatePoint(p, now); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		userIDs[i], _ = uuid.Parse(p.UserID)
+		order = append(order, i)
 	}
-	s.logger.Info("check saved (attempted)")
+	sort.SliceStable(order, func(a, b int) bool {
+		return req.Points[order[a]].Timestamp.Before(req.Points[order[b]].Timestamp)
+	})
 
-	if len(ids) > 0 {
-		payload := domain.WebhookPayload{
+	// Точки старше последней применённой проверки пользователя только
+	// сохраняются: трекер и присутствие они бы откатили в прошлое.
+	applied, err := s.checkSaver.LastApplied(ctx, uniqueIDs(userIDs, order))
+	if err != nil {
+		s.logger.Error("last applied check lookup failed", slog.Any("error", err))
+		return domain.LocationCheckBatchResponse{}, err
+	}
+	if applied == nil {
+		applied = make(map[uuid.UUID]time.Time)
+	}
+
+	checks := make([]*domain.LocationCheck, 0, len(order))
+	trs := make([]domain.AlertTransitions, 0, len(order))
+	for _, i := range order {
+		p := req.Points[i]
+		checkedAt := p.Timestamp.UTC()
+
+		inside, _ := classifyIncidents(snap.grid.Candidates(p.Lat, p.Lng), p.Lat, p.Lng, checkedAt, 0)
+		ids := make([]uuid.UUID, 0, len(inside))
+		summaries := make([]domain.IncidentSummary, 0, len(inside))
+		for _, in := range inside {
+			ids = append(ids, in.Incident.ID)
+			summaries = append(summaries, in.Incident)
+		}
+
+		var tr domain.AlertTransitions
+		if checkedAt.Before(applied[userIDs[i]]) {
+			results[i].Stale = true
+		} else {
+			tr = s.track(ctx, p.UserID, ids, checkedAt)
+			applied[userIDs[i]] = checkedAt
+		}
+		results[i].Incidents = summaries
+		results[i].New = tr.Entered
+		results[i].Known = tr.Known
+
+		checks = append(checks, &domain.LocationCheck{
+			UserID:      userIDs[i],
+			Lat:         p.Lat,
+			Lng:         p.Lng,
+			IncidentIDs: ids,
+			CheckedAt:   checkedAt,
+		})
+		trs = append(trs, tr)
+	}
+
+	events := func(j int, transitions []domain.OccupancyTransition) ([]domain.WebhookEvent, error) {
+		p := req.Points[order[j]]
+		single := domain.LocationCheckRequest{UserID: p.UserID, Lat: p.Lat, Lng: p.Lng}
+		return s.checkEvents(single, checks[j].CheckedAt, byID, trs[j], transitions)
+	}
+	if err := s.checkSaver.SaveChecks(ctx, checks, events); err != nil {
+		s.logger.Error("save checks failed", slog.Int("checks", len(checks)), slog.Any("error", err))
+		// Откат в обратном порядке: повторно присланный пакет даст те же входы.
+		for j := len(checks) - 1; j >= 0; j-- {
+			s.revert(ctx, req.Points[order[j]].UserID, trs[j], checks[j].CheckedAt)
+		}
+		return domain.LocationCheckBatchResponse{}, err
+	}
+
+	s.logger.Info("location batch check END",
+		slog.Int("points", len(req.Points)),
+		slog.Int("accepted", len(checks)),
+	)
+	return domain.LocationCheckBatchResponse{Results: results, Accepted: len(checks)}, nil
+}
+
+func validatePoint(p domain.LocationCheckPoint, now time.Time) error {
+	if _, err := uuid.Parse(p.UserID); err != nil {
+		return e.ErrInvalidUserID
+	}
+	if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+		return e.ErrInvalidCoordinates
+	}
+	if p.Timestamp.IsZero() {
+		return fmt.Errorf("timestamp is required")
+	}
+	if p.Timestamp.After(now.Add(maxCheckClockSkew)) {
+		return fmt.Errorf("timestamp is in the future")
+	}
+	if p.Timestamp.Before(now.Add(-domain.MaxCheckPointAge)) {
+		return fmt.Errorf("timestamp is older than %d days", int(domain.MaxCheckPointAge/(24*time.Hour)))
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uuid.UUID, order []int) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(order))
+	out := make([]uuid.UUID, 0, len(order))
+	for _, i := range order {
+		if !seen[ids[i]] {
+			seen[ids[i]] = true
+			out = append(out, ids[i])
+		}
+	}
+	return out
+}
+
+// checkEvents — события сохраняемой проверки: алерты по трекеру и по одному
+// событию на каждый вход или выход.
+func (s *publicIncidentService) checkEvents(req domain.LocationCheckRequest, checkedAt time.Time, byID map[uuid.UUID]domain.IncidentSummary, tr domain.AlertTransitions, transitions []domain.OccupancyTransition) ([]domain.WebhookEvent, error) {
+	evs := s.alertEvents(req, checkedAt, byID, tr)
+	for _, t := range transitions {
+		ev, err := t.Event(summaryOf(byID, t.IncidentID))
+		if err != nil {
+			return nil, err
+		}
+		evs = append(evs, ev)
+	}
+	return evs, nil
+}
+
+// alertEvents — location.hit по новым попаданиям и location.exited по зонам,
+// из которых пользователь вышел.
+func (s *publicIncidentService) alertEvents(req domain.LocationCheckRequest, checkedAt time.Time, byID map[uuid.UUID]domain.IncidentSummary, tr domain.AlertTransitions) []domain.WebhookEvent {
+	var events []domain.WebhookEvent
+	add := func(t domain.WebhookEventType, ids []uuid.UUID) {
+		if len(ids) == 0 {
+			return
+		}
+		incidents := make([]domain.IncidentSummary, 0, len(ids))
+		for _, id := range ids {
+			incidents = append(incidents, summaryOf(byID, id))
+		}
+		ev, err := domain.NewWebhookEvent(t, checkedAt, domain.WebhookPayload{
 			UserID:    req.UserID,
 			Lat:       req.Lat,
 			Lng:       req.Lng,
-			Incidents: ids,
+			Incidents: incidents,
 			CheckedAt: checkedAt,
+		})
+		if err != nil {
+			s.logger.Error("build webhook event failed", slog.String("type", string(t)), slog.Any("error", err))
+			return
 		}
+		events = append(events, ev)
+	}
+	add(domain.WebhookEventLocationHit, tr.Entered)
+	add(domain.WebhookEventLocationExited, tr.Exited)
+	return events
+}
 
-		if err := s.webhookQueue.Enqueue(ctx, payload); err != nil {
-			s.logger.Error("enqueue webhook failed", slog.Any("error", err))
-		} else {
-			s.logger.Info("webhook enqueued", slog.String("user_id", req.UserID), slog.Int("incidents", len(ids)))
-		}
-	} else {
-		s.logger.Debug("no incidents nearby")
+func summariesByID(active []domain.CachedIncident) map[uuid.UUID]domain.IncidentSummary {
+	byID := make(map[uuid.UUID]domain.IncidentSummary, len(active))
+	for _, inc := range active {
+		byID[inc.ID] = inc.Summary()
 	}
+	return byID
+}
 
-	s.logger.Info("location check END", slog.Int("incidents_found", len(ids)))
-	return domain.LocationCheckResponse{Incidents: idsToStrings(ids)}, nil
+// summaryOf — сводка активного инцидента; про неактивный известен только id.
+func summaryOf(byID map[uuid.UUID]domain.IncidentSummary, id uuid.UUID) domain.IncidentSummary {
+	if sum, ok := byID[id]; ok {
+		return sum
+	}
+	return domain.IncidentSummary{ID: id}
 }
 
-func idsToStrings(ids []uuid.UUID) []string {
-	strs := make([]string, len(ids))
-	for i, id := range ids {
-		strs[i] = id.String()
+// track сверяет попадания с уже отправленными алертами. Без трекера или при
+// его ошибке все попадания считаются новыми: лучше повторный алерт, чем
+// пропущенный.
+func (s *publicIncidentService) track(ctx context.Context, userID string, ids []uuid.UUID, now time.Time) domain.AlertTransitions {
+	if s.alerts == nil {
+		return domain.AlertTransitions{Entered: ids}
+	}
+	tr, err := s.alerts.Track(ctx, userID, ids, now)
+	if err != nil {
+		s.logger.Error("alerts.Track failed", slog.Any("error", err))
+		return domain.AlertTransitions{Entered: ids}
 	}
-	return strs
+	return tr
 }
-func filterNearby(incidents []domain.CachedIncident, lat, lng float64) []domain.NearbyIncident {
-	nearby := make([]domain.NearbyIncident, 0)
-	for _, inc := range incidents {
-		dist := haversine(lat, lng, inc.Lat, inc.Lng)
 
-		if dist <= inc.RadiusKM {
-			nearby = append(nearby, domain.NearbyIncident{
-				ID:         inc.ID,
-				Lat:        inc.Lat,
-				Lng:        inc.Lng,
-				RadiusKM:   inc.RadiusKM,
-				DistanceKM: dist,
-			})
-		}
+// revert откатывает состояние алертов для несохранённой проверки. Ошибка
+// только логируется: в худшем случае алерт будет подавлен до конца окна.
+func (s *publicIncidentService) revert(ctx context.Context, userID string, tr domain.AlertTransitions, now time.Time) {
+	if s.alerts == nil {
+		return
+	}
+	if err := s.alerts.Revert(ctx, userID, tr, now); err != nil {
+		s.logger.Error("alerts.Revert failed", slog.String("user_id", userID), slog.Any("error", err))
 	}
-	return nearby
 }
 
 func haversine(lat1, lon1, lat2, lon2 float64) float64 {
@@ -2,14 +2,20 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"log/slog"
+	"math"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 
 	"redCollar/internal/domain"
 	"redCollar/internal/service"
+	"redCollar/pkg/e"
 
 	mock_service "redCollar/internal/service/mocks"
 )
@@ -28,14 +34,14 @@ func TestService_CheckLocation_Delegates_OK_EmptyIncidents(t *testing.T) {
 		Lng:    37.61,
 	}
 
-	want := domain.LocationCheckResponse{Incidents: []string{}}
+	want := domain.LocationCheckResponse{Incidents: []domain.IncidentSummary{}}
 
 	publicSvc.EXPECT().
 		CheckLocation(gomock.Any(), req).
 		Return(want, nil).
 		Times(1)
 
-	svc := service.NewService(nil, publicSvc, nil)
+	svc := service.NewService(nil, publicSvc, nil, nil, nil, nil, nil)
 
 	got, err := svc.CheckLocation(context.Background(), req)
 	if err != nil {
@@ -61,9 +67,9 @@ func TestService_CheckLocation_Delegates_OK_WithIncidents(t *testing.T) {
 	}
 
 	want := domain.LocationCheckResponse{
-		Incidents: []string{
-			"11111111-1111-1111-1111-111111111111",
-			"22222222-2222-2222-2222-222222222222",
+		Incidents: []domain.IncidentSummary{
+			{ID: uuid.MustParse("11111111-1111-1111-1111-111111111111"), Title: "Flood", Severity: domain.SeverityCritical, Category: domain.CategoryFlood},
+			{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222"), Title: "Road closure", Severity: domain.SeverityLow, Category: domain.CategoryRoadClosure},
 		},
 	}
 
@@ -72,7 +78,7 @@ func TestService_CheckLocation_Delegates_OK_WithIncidents(t *testing.T) {
 		Return(want, nil).
 		Times(1)
 
-	svc := service.NewService(nil, publicSvc, nil)
+	svc := service.NewService(nil, publicSvc, nil, nil, nil, nil, nil)
 
 	got, err := svc.CheckLocation(context.Background(), req)
 	if err != nil {
@@ -104,7 +110,7 @@ func TestService_CheckLocation_Delegates_ErrorPropagated(t *testing.T) {
 		Return(domain.LocationCheckResponse{}, wantErr).
 		Times(1)
 
-	svc := service.NewService(nil, publicSvc, nil)
+	svc := service.NewService(nil, publicSvc, nil, nil, nil, nil, nil)
 
 	_, err := svc.CheckLocation(context.Background(), req)
 	if err == nil {
@@ -144,7 +150,7 @@ func TestService_CheckLocation_PassesContextValue(t *testing.T) {
 		}).
 		Times(1)
 
-	svc := service.NewService(nil, publicSvc, nil)
+	svc := service.NewService(nil, publicSvc, nil, nil, nil, nil, nil)
 
 	_, err := svc.CheckLocation(ctx, req)
 	if err != nil {
@@ -170,26 +176,626 @@ func TestService_CheckLocation_MultipleCalls(t *testing.T) {
 		Lat:    3,
 		Lng:    4,
 	}
+	idA, idB := uuid.New(), uuid.New()
 
 	publicSvc.EXPECT().
 		CheckLocation(gomock.Any(), req1).
-		Return(domain.LocationCheckResponse{Incidents: []string{"a"}}, nil).
+		Return(domain.LocationCheckResponse{Incidents: []domain.IncidentSummary{{ID: idA}}}, nil).
 		Times(1)
 
 	publicSvc.EXPECT().
 		CheckLocation(gomock.Any(), req2).
-		Return(domain.LocationCheckResponse{Incidents: []string{"b"}}, nil).
+		Return(domain.LocationCheckResponse{Incidents: []domain.IncidentSummary{{ID: idB}}}, nil).
 		Times(1)
 
-	svc := service.NewService(nil, publicSvc, nil)
+	svc := service.NewService(nil, publicSvc, nil, nil, nil, nil, nil)
 
 	r1, err := svc.CheckLocation(context.Background(), req1)
-	if err != nil || len(r1.Incidents) != 1 || r1.Incidents[0] != "a" {
+	if err != nil || len(r1.Incidents) != 1 || r1.Incidents[0].ID != idA {
 		t.Fatalf("unexpected r1=%+v err=%v", r1, err)
 	}
 
 	r2, err := svc.CheckLocation(context.Background(), req2)
-	if err != nil || len(r2.Incidents) != 1 || r2.Incidents[0] != "b" {
+	if err != nil || len(r2.Incidents) != 1 || r2.Incidents[0].ID != idB {
 		t.Fatalf("unexpected r2=%+v err=%v", r2, err)
 	}
 }
+
+// --- Alerts ---
+
+// recordingSaver отдаёт в events заранее заданные переходы, как это сделал бы репозиторий.
+type recordingSaver struct {
+	transitions []domain.OccupancyTransition
+	checks      []*domain.LocationCheck
+	events      []domain.WebhookEvent
+	applied     map[uuid.UUID]time.Time
+	err         error
+}
+
+func (s *recordingSaver) SaveCheck(_ context.Context, check *domain.LocationCheck, events domain.CheckEventFunc) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.checks = append(s.checks, check)
+	evs, err := events(s.transitions)
+	if err != nil {
+		return err
+	}
+	s.events = append(s.events, evs...)
+	return nil
+}
+
+func (s *recordingSaver) SaveChecks(_ context.Context, checks []*domain.LocationCheck, events domain.CheckBatchEventFunc) error {
+	if s.err != nil {
+		return s.err
+	}
+	for i, check := range checks {
+		s.checks = append(s.checks, check)
+		evs, err := events(i, s.transitions)
+		if err != nil {
+			return err
+		}
+		s.events = append(s.events, evs...)
+	}
+	return nil
+}
+
+func (s *recordingSaver) LastApplied(_ context.Context, _ []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	return s.applied, nil
+}
+
+func alertTestIncidents() (domain.CachedIncident, domain.CachedIncident) {
+	near := domain.CachedIncident{ID: uuid.New(), Title: "Пожар", Lat: 55.75, Lng: 37.61, RadiusKM: 1}
+	far := domain.CachedIncident{ID: uuid.New(), Title: "Потоп", Lat: 59.93, Lng: 30.33, RadiusKM: 1}
+	return near, far
+}
+
+func TestPublicIncidentService_CheckLocation_OnlyNewIncidentsAlert(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	alerts := mock_service.NewMockAlertTracker(ctrl)
+	saver := &recordingSaver{}
+
+	near, far := alertTestIncidents()
+	left := uuid.New()
+	req := domain.LocationCheckRequest{UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61}
+
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{near, far}, nil).Times(1)
+	alerts.EXPECT().
+		Track(gomock.Any(), req.UserID, []uuid.UUID{near.ID}, gomock.Any()).
+		Return(domain.AlertTransitions{Known: []uuid.UUID{near.ID}, Exited: []uuid.UUID{far.ID, left}}, nil).
+		Times(1)
+
+	svc := service.NewPublicIncidentService(cache, saver, nil, alerts, slog.New(slog.DiscardHandler), 1, 0)
+
+	resp, err := svc.CheckLocation(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(resp.Incidents) != 1 || len(resp.New) != 0 || len(resp.Known) != 1 || resp.Known[0] != near.ID {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	// Известный инцидент не даёт location.hit, выход — только location.exited.
+	if len(saver.checks) != 1 || len(saver.events) != 1 {
+		t.Fatalf("expected 1 check and 1 event, got %d and %d", len(saver.checks), len(saver.events))
+	}
+	ev := saver.events[0]
+	var p domain.WebhookPayload
+	if err := json.Unmarshal(ev.Data, &p); err != nil {
+		t.Fatalf("unmarshal data: %v", err)
+	}
+	if ev.Type != domain.WebhookEventLocationExited || len(p.Incidents) != 2 {
+		t.Fatalf("unexpected event: %s %s", ev.Type, ev.Data)
+	}
+	if p.Incidents[0].Title != far.Title || p.Incidents[1].ID != left || p.Incidents[1].Title != "" {
+		t.Fatalf("unexpected exited incidents: %+v", p.Incidents)
+	}
+}
+
+func TestPublicIncidentService_CheckLocation_TrackerErrorAlertsAll(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	alerts := mock_service.NewMockAlertTracker(ctrl)
+	saver := &recordingSaver{}
+
+	near, far := alertTestIncidents()
+	req := domain.LocationCheckRequest{UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61}
+
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{near, far}, nil).Times(1)
+	alerts.EXPECT().
+		Track(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.AlertTransitions{}, errors.New("redis down")).
+		Times(1)
+
+	svc := service.NewPublicIncidentService(cache, saver, nil, alerts, slog.New(slog.DiscardHandler), 1, 0)
+
+	resp, err := svc.CheckLocation(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(resp.New) != 1 || resp.New[0] != near.ID {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if len(saver.events) != 1 || saver.events[0].Type != domain.WebhookEventLocationHit {
+		t.Fatalf("expected a single location.hit, got %+v", saver.events)
+	}
+}
+
+func TestPublicIncidentService_CheckLocation_OccupancyEvents(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	near, far := alertTestIncidents()
+	near.Category = domain.CategoryFire
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	at := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	saver := &recordingSaver{transitions: []domain.OccupancyTransition{
+		{UserID: userID, IncidentID: near.ID, Kind: domain.OccupancyEntered, Lat: f64ptr(55.75), Lng: f64ptr(37.61), At: at},
+		{UserID: userID, IncidentID: far.ID, Kind: domain.OccupancyExited, Lat: f64ptr(55.75), Lng: f64ptr(37.61), At: at},
+	}}
+
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{near, far}, nil).Times(1)
+
+	// Без трекера алертов каждое попадание — location.hit.
+	svc := service.NewPublicIncidentService(cache, saver, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	if _, err := svc.CheckLocation(context.Background(), domain.LocationCheckRequest{UserID: userID.String(), Lat: 55.75, Lng: 37.61}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	if len(saver.events) != 3 {
+		t.Fatalf("expected hit + 2 occupancy events, got %d", len(saver.events))
+	}
+	want := []domain.WebhookEventType{
+		domain.WebhookEventLocationHit,
+		domain.WebhookEventUserEnteredIncident,
+		domain.WebhookEventUserExitedIncident,
+	}
+	for i, ev := range saver.events {
+		if ev.Type != want[i] {
+			t.Fatalf("event %d: expected %q got %q", i, want[i], ev.Type)
+		}
+	}
+
+	var p domain.OccupancyPayload
+	if err := json.Unmarshal(saver.events[1].Data, &p); err != nil {
+		t.Fatalf("unmarshal data: %v", err)
+	}
+	if p.UserID != userID.String() || p.Incident.ID != near.ID || p.Incident.Category != domain.CategoryFire || !p.At.Equal(at) {
+		t.Fatalf("unexpected payload: %+v", p)
+	}
+}
+
+func TestPublicIncidentService_CheckLocation_SaveErrorReturned(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	near, far := alertTestIncidents()
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{near, far}, nil).Times(1)
+
+	boom := errors.New("tx failed")
+	svc := service.NewPublicIncidentService(cache, &recordingSaver{err: boom}, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	resp, err := svc.CheckLocation(context.Background(), domain.LocationCheckRequest{
+		UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61,
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected %v, got %v", boom, err)
+	}
+	if len(resp.Incidents) != 0 || len(resp.New) != 0 {
+		t.Fatalf("expected empty response, got %+v", resp)
+	}
+}
+
+// memTracker — AlertTracker без окна: вход — первое попадание после отката
+// или вообще первое.
+type memTracker struct {
+	notified map[string]bool
+}
+
+func (m *memTracker) Track(_ context.Context, userID string, hits []uuid.UUID, _ time.Time) (domain.AlertTransitions, error) {
+	var tr domain.AlertTransitions
+	for _, id := range hits {
+		if m.notified[userID+id.String()] {
+			tr.Known = append(tr.Known, id)
+			continue
+		}
+		m.notified[userID+id.String()] = true
+		tr.Entered = append(tr.Entered, id)
+	}
+	return tr, nil
+}
+
+func (m *memTracker) Revert(_ context.Context, userID string, tr domain.AlertTransitions, _ time.Time) error {
+	for _, id := range tr.Entered {
+		delete(m.notified, userID+id.String())
+	}
+	return nil
+}
+
+func TestPublicIncidentService_CheckLocation_SaveErrorRevertsAlerts(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	near, far := alertTestIncidents()
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{near, far}, nil).Times(2)
+
+	saver := &recordingSaver{err: errors.New("tx failed")}
+	svc := service.NewPublicIncidentService(cache, saver, nil, &memTracker{notified: map[string]bool{}}, slog.New(slog.DiscardHandler), 1, 0)
+	req := domain.LocationCheckRequest{UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61}
+
+	if _, err := svc.CheckLocation(context.Background(), req); err == nil {
+		t.Fatalf("expected save error")
+	}
+
+	// Повтор после ошибки — всё ещё вход, и location.hit уходит.
+	saver.err = nil
+	resp, err := svc.CheckLocation(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !reflect.DeepEqual(resp.New, []uuid.UUID{near.ID}) || len(resp.Known) != 0 {
+		t.Fatalf("expected %v to be new, got %+v", near.ID, resp)
+	}
+	if len(saver.events) != 1 || saver.events[0].Type != domain.WebhookEventLocationHit {
+		t.Fatalf("expected location.hit, got %+v", saver.events)
+	}
+}
+
+func TestPublicIncidentService_CheckLocationBatch_OrdersAndValidatesPoints(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	near, far := alertTestIncidents()
+	saver := &recordingSaver{}
+
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{near, far}, nil).Times(1)
+
+	svc := service.NewPublicIncidentService(cache, saver, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	user := "00000000-0000-0000-0000-000000000001"
+	t0 := time.Now().UTC().Add(-time.Hour)
+	req := domain.LocationCheckBatchRequest{Points: []domain.LocationCheckPoint{
+		{UserID: user, Lat: 55.75, Lng: 37.61, Timestamp: t0.Add(2 * time.Minute)},
+		{UserID: "bad", Lat: 55.75, Lng: 37.61, Timestamp: t0},
+		{UserID: user, Lat: 10, Lng: 10, Timestamp: t0},
+		{UserID: user, Lat: 95, Lng: 10, Timestamp: t0},
+		{UserID: user, Lat: 10, Lng: 10, Timestamp: time.Now().Add(time.Hour)},
+		{UserID: user, Lat: 10, Lng: 10, Timestamp: time.Now().Add(-domain.MaxCheckPointAge - time.Hour)},
+	}}
+
+	resp, err := svc.CheckLocationBatch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	if resp.Accepted != 2 || len(resp.Results) != 6 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	for i, r := range resp.Results {
+		if r.Index != i {
+			t.Fatalf("result %d has index %d", i, r.Index)
+		}
+		if wantErr := i != 0 && i != 2; (r.Error != "") != wantErr {
+			t.Fatalf("result %d: unexpected error %q", i, r.Error)
+		}
+	}
+	if len(resp.Results[0].Incidents) != 1 || resp.Results[0].Incidents[0].ID != near.ID || len(resp.Results[2].Incidents) != 0 {
+		t.Fatalf("unexpected hits: %+v", resp.Results)
+	}
+
+	// Точки пользователя сохраняются по времени, а не в порядке запроса.
+	if len(saver.checks) != 2 || !saver.checks[0].CheckedAt.Equal(t0) || saver.checks[1].Lat != 55.75 {
+		t.Fatalf("unexpected saved checks: %+v", saver.checks)
+	}
+	if len(saver.events) != 1 || saver.events[0].Type != domain.WebhookEventLocationHit {
+		t.Fatalf("expected a single location.hit, got %+v", saver.events)
+	}
+}
+
+func TestPublicIncidentService_CheckLocationBatch_StalePointsDoNotTrack(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	alerts := mock_service.NewMockAlertTracker(ctrl)
+	near, far := alertTestIncidents()
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{near, far}, nil).Times(1)
+
+	// Живая проверка уже была в 12:00; пакет за 10:00–13:00 применяется только с 12:00.
+	user := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	live := time.Now().UTC().Add(-2 * time.Hour)
+	saver := &recordingSaver{applied: map[uuid.UUID]time.Time{user: live}}
+
+	alerts.EXPECT().
+		Track(gomock.Any(), user.String(), []uuid.UUID{near.ID}, live.Add(time.Hour)).
+		Return(domain.AlertTransitions{Known: []uuid.UUID{near.ID}}, nil).
+		Times(1)
+
+	svc := service.NewPublicIncidentService(cache, saver, nil, alerts, slog.New(slog.DiscardHandler), 1, 0)
+
+	resp, err := svc.CheckLocationBatch(context.Background(), domain.LocationCheckBatchRequest{Points: []domain.LocationCheckPoint{
+		{UserID: user.String(), Lat: 55.75, Lng: 37.61, Timestamp: live.Add(time.Hour)},
+		{UserID: user.String(), Lat: 10, Lng: 10, Timestamp: live.Add(-2 * time.Hour)},
+		{UserID: user.String(), Lat: 55.75, Lng: 37.61, Timestamp: live.Add(-time.Hour)},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	// Устаревшие точки сохранены, но без New/Known.
+	if resp.Accepted != 3 || !resp.Results[1].Stale || !resp.Results[2].Stale || resp.Results[0].Stale {
+		t.Fatalf("unexpected stale flags: %+v", resp.Results)
+	}
+	if len(resp.Results[2].Incidents) != 1 || len(resp.Results[2].Known) != 0 || len(resp.Results[2].New) != 0 {
+		t.Fatalf("unexpected stale result: %+v", resp.Results[2])
+	}
+	if !reflect.DeepEqual(resp.Results[0].Known, []uuid.UUID{near.ID}) {
+		t.Fatalf("unexpected live result: %+v", resp.Results[0])
+	}
+}
+
+func TestPublicIncidentService_CheckLocationBatch_SaveErrorReturned(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	saver := &recordingSaver{err: errors.New("db down")}
+
+	cache.EXPECT().GetActive(gomock.Any()).Return(nil, nil).Times(1)
+
+	svc := service.NewPublicIncidentService(cache, saver, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	req := domain.LocationCheckBatchRequest{Points: []domain.LocationCheckPoint{
+		{UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61, Timestamp: time.Now()},
+	}}
+	if _, err := svc.CheckLocationBatch(context.Background(), req); !errors.Is(err, saver.err) {
+		t.Fatalf("expected save error, got %v", err)
+	}
+}
+
+func TestPublicIncidentService_CheckLocationBatch_ResendAfterSaveErrorIsNew(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	near, far := alertTestIncidents()
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{near, far}, nil).Times(2)
+
+	saver := &recordingSaver{err: errors.New("db down")}
+	svc := service.NewPublicIncidentService(cache, saver, nil, &memTracker{notified: map[string]bool{}}, slog.New(slog.DiscardHandler), 1, 0)
+
+	now := time.Now()
+	req := domain.LocationCheckBatchRequest{Points: []domain.LocationCheckPoint{
+		{UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61, Timestamp: now.Add(-time.Minute)},
+		{UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61, Timestamp: now},
+	}}
+	if _, err := svc.CheckLocationBatch(context.Background(), req); err == nil {
+		t.Fatalf("expected save error")
+	}
+
+	saver.err = nil
+	resp, err := svc.CheckLocationBatch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Results[0].New, []uuid.UUID{near.ID}) || !reflect.DeepEqual(resp.Results[1].Known, []uuid.UUID{near.ID}) {
+		t.Fatalf("unexpected results after resend: %+v", resp.Results)
+	}
+}
+
+func TestPublicIncidentService_CheckLocationBatch_TooManyPoints(t *testing.T) {
+	t.Parallel()
+
+	svc := service.NewPublicIncidentService(nil, &recordingSaver{}, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	req := domain.LocationCheckBatchRequest{Points: make([]domain.LocationCheckPoint, domain.MaxCheckBatchPoints+1)}
+	if _, err := svc.CheckLocationBatch(context.Background(), req); !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+type stubFinder struct {
+	lat, lng, radius float64
+	limit            int
+	res              []domain.NearbyIncident
+	err              error
+}
+
+func (f *stubFinder) FindNearby(_ context.Context, lat, lng, radiusKm float64, limit int) ([]domain.NearbyIncident, error) {
+	f.lat, f.lng, f.radius, f.limit = lat, lng, radiusKm, limit
+	return f.res, f.err
+}
+
+func TestPublicIncidentService_NearbyIncidents_DefaultsAndCap(t *testing.T) {
+	t.Parallel()
+
+	near := domain.NearbyIncident{ID: uuid.New(), Title: "Пожар", DistanceKM: 0.4}
+	finder := &stubFinder{res: []domain.NearbyIncident{near}}
+	svc := service.NewPublicIncidentService(nil, &recordingSaver{}, finder, nil, slog.New(slog.DiscardHandler), 2, 0)
+
+	resp, err := svc.NearbyIncidents(context.Background(), domain.NearbyRequest{Lat: 55.75, Lng: 37.61})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if finder.radius != 2 || finder.limit != 20 || finder.lat != 55.75 || finder.lng != 37.61 {
+		t.Fatalf("unexpected finder call: %+v", finder)
+	}
+	if resp.RadiusKM != 2 || resp.Limit != 20 || len(resp.Incidents) != 1 || resp.Incidents[0].ID != near.ID {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	if _, err := svc.NearbyIncidents(context.Background(), domain.NearbyRequest{Lat: 55.75, Lng: 37.61, RadiusKM: 500, Limit: 5}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if finder.radius != domain.MaxNearbyRadiusKM || finder.limit != 5 {
+		t.Fatalf("radius not capped: %+v", finder)
+	}
+}
+
+func TestPublicIncidentService_NearbyIncidents_Errors(t *testing.T) {
+	t.Parallel()
+
+	finder := &stubFinder{err: errors.New("db down")}
+	svc := service.NewPublicIncidentService(nil, &recordingSaver{}, finder, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	if _, err := svc.NearbyIncidents(context.Background(), domain.NearbyRequest{Lat: 91, Lng: 37.61}); !errors.Is(err, e.ErrInvalidCoordinates) {
+		t.Fatalf("expected ErrInvalidCoordinates, got %v", err)
+	}
+	if _, err := svc.NearbyIncidents(context.Background(), domain.NearbyRequest{Lat: 55.75, Lng: 37.61}); !errors.Is(err, finder.err) {
+		t.Fatalf("expected finder error, got %v", err)
+	}
+}
+
+func TestPublicIncidentService_CheckLocationV2_InsideAndApproaching(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	saver := &recordingSaver{}
+
+	// 0.01° широты ≈ 1.112 км.
+	fire := domain.CachedIncident{ID: uuid.New(), Title: "Пожар", Lat: 55.75, Lng: 37.61, RadiusKM: 1}
+	gas := domain.CachedIncident{ID: uuid.New(), Title: "Газ", Lat: 55.762, Lng: 37.61, RadiusKM: 1}
+	far := domain.CachedIncident{ID: uuid.New(), Title: "Потоп", Lat: 55.80, Lng: 37.61, RadiusKM: 1}
+	zone := domain.CachedIncident{
+		ID: uuid.New(), Title: "Перекрытие", Lat: 55.735, Lng: 37.615,
+		Geometry: &domain.Geometry{Type: domain.GeometryPolygon, Polygons: []domain.Polygon{{
+			{{37.60, 55.73}, {37.62, 55.73}, {37.62, 55.747}, {37.60, 55.747}, {37.60, 55.73}},
+		}}},
+	}
+
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{far, gas, zone, fire}, nil).Times(1)
+
+	svc := service.NewPublicIncidentService(cache, saver, nil, nil, slog.New(slog.DiscardHandler), 1, 0.5)
+
+	resp, err := svc.CheckLocationV2(context.Background(), domain.LocationCheckRequest{
+		UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.7505, Lng: 37.61,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	if resp.BufferKM != 0.5 || len(resp.Inside) != 1 || len(resp.Approaching) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	in := resp.Inside[0]
+	if in.Incident.ID != fire.ID || !in.Inside || in.InBuffer || math.Abs(in.DistanceKM-0.056) > 0.005 || math.Abs(in.DistanceToEdgeKM-0.944) > 0.005 {
+		t.Fatalf("unexpected inside: %+v", in)
+	}
+
+	// До края полигона ~0.39 км, до окружности gas ~0.28 км.
+	if resp.Approaching[0].Incident.ID != gas.ID || resp.Approaching[1].Incident.ID != zone.ID {
+		t.Fatalf("unexpected approaching order: %+v", resp.Approaching)
+	}
+	for _, a := range resp.Approaching {
+		if a.Inside || !a.InBuffer || a.DistanceToEdgeKM > 0.5 {
+			t.Fatalf("unexpected approaching: %+v", a)
+		}
+	}
+	if math.Abs(resp.Approaching[1].DistanceToEdgeKM-0.389) > 0.01 {
+		t.Fatalf("unexpected polygon edge distance: %v", resp.Approaching[1].DistanceToEdgeKM)
+	}
+
+	// Сохраняется и алертит только то, что внутри.
+	if len(saver.checks) != 1 || !reflect.DeepEqual(saver.checks[0].IncidentIDs, []uuid.UUID{fire.ID}) {
+		t.Fatalf("unexpected saved check: %+v", saver.checks)
+	}
+	if !reflect.DeepEqual(resp.New, []uuid.UUID{fire.ID}) {
+		t.Fatalf("unexpected new: %v", resp.New)
+	}
+}
+
+// versionedCache — кэш с версией, как redis.IncidentCache.
+type versionedCache struct {
+	*mock_service.MockIncidentCacheService
+	*mock_service.MockIncidentCacheVersion
+}
+
+func TestPublicIncidentService_CheckLocation_IndexRebuiltOnVersionChange(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := versionedCache{
+		mock_service.NewMockIncidentCacheService(ctrl),
+		mock_service.NewMockIncidentCacheVersion(ctrl),
+	}
+	near, far := alertTestIncidents()
+	moved := near
+	moved.ID, moved.Lat = uuid.New(), 55.80
+
+	// Список скачивается только при смене версии, GetActive не зовётся вовсе.
+	gomock.InOrder(
+		cache.MockIncidentCacheVersion.EXPECT().Version(gomock.Any()).Return("v1", nil),
+		cache.MockIncidentCacheVersion.EXPECT().GetActiveVersion(gomock.Any()).Return([]domain.CachedIncident{near, far}, "v1", nil),
+		cache.MockIncidentCacheVersion.EXPECT().Version(gomock.Any()).Return("v1", nil),
+		cache.MockIncidentCacheVersion.EXPECT().Version(gomock.Any()).Return("v2", nil),
+		cache.MockIncidentCacheVersion.EXPECT().GetActiveVersion(gomock.Any()).Return([]domain.CachedIncident{moved, far}, "v2", nil),
+	)
+
+	svc := service.NewPublicIncidentService(cache, &recordingSaver{}, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+	req := domain.LocationCheckRequest{UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61}
+
+	for i, want := range []int{1, 1, 0} {
+		resp, err := svc.CheckLocation(context.Background(), req)
+		if err != nil {
+			t.Fatalf("call %d: unexpected err: %v", i, err)
+		}
+		if len(resp.Incidents) != want {
+			t.Fatalf("call %d: expected %d incidents, got %+v", i, want, resp.Incidents)
+		}
+	}
+}
+
+func TestPublicIncidentService_CheckLocation_VersionError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := versionedCache{
+		mock_service.NewMockIncidentCacheService(ctrl),
+		mock_service.NewMockIncidentCacheVersion(ctrl),
+	}
+	boom := errors.New("redis down")
+	cache.MockIncidentCacheVersion.EXPECT().Version(gomock.Any()).Return("", boom)
+
+	svc := service.NewPublicIncidentService(cache, &recordingSaver{}, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+	_, err := svc.CheckLocation(context.Background(), domain.LocationCheckRequest{
+		UserID: "00000000-0000-0000-0000-000000000001", Lat: 55.75, Lng: 37.61,
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected %v, got %v", boom, err)
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/internal/geoindex"
+	"redCollar/pkg/e"
+	"redCollar/pkg/polyline"
+)
+
+const (
+	earthRadiusKm = 6371.0
+	// maxRouteStepKM — длиннее сегменты дробятся: внутри сегмента считаем
+	// в локальной плоской проекции, и на длинных отрезках она врёт.
+	maxRouteStepKM = 5.0
+	// routeJoinEpsKM — участки, между которыми меньше, считаются одним.
+	routeJoinEpsKM = 1e-6
+)
+
+// CheckRoute находит активные инциденты, через которые проходит маршрут, и
+// участки маршрута внутри каждого. Считается по кэшу, как и проверка точки.
+func (s *publicIncidentService) CheckRoute(ctx context.Context, req domain.RouteCheckRequest) (domain.RouteCheckResponse, error) {
+	path, err := routePath(req)
+	if err != nil {
+		s.logger.Warn("invalid route", slog.Any("error", err))
+		return domain.RouteCheckResponse{}, err
+	}
+
+	snap, err := s.active.get(ctx)
+	if err != nil {
+		s.logger.Error("active incidents load failed", slog.Any("error", err))
+		return domain.RouteCheckResponse{}, err
+	}
+
+	length, hits := intersectRoute(snap.grid.Incidents(), path, time.Now().UTC())
+	s.logger.Info("route check done",
+		slog.Int("positions", len(path)),
+		slog.Float64("length_km", length),
+		slog.Int("incidents", len(hits)),
+	)
+	return domain.RouteCheckResponse{LengthKM: length, Incidents: hits}, nil
+}
+
+// routePath приводит маршрут из запроса к вершинам [lng, lat] и проверяет их.
+func routePath(req domain.RouteCheckRequest) ([]domain.Position, error) {
+	if (req.Route == nil) == (req.Polyline == "") {
+		return nil, fmt.Errorf("%w: exactly one of route or polyline is required", e.ErrInvalidInput)
+	}
+
+	var path []domain.Position
+	if req.Route != nil {
+		path = req.Route.Coordinates
+	} else {
+		precision := req.Precision
+		if precision == 0 {
+			precision = 5
+		}
+		points, err := polyline.Decode(req.Polyline, precision)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", e.ErrInvalidInput, err)
+		}
+		path = make([]domain.Position, len(points))
+		for i, p := range points {
+			path[i] = domain.Position{p.Lng, p.Lat}
+		}
+	}
+
+	if len(path) < 2 || len(path) > domain.MaxRoutePositions {
+		return nil, fmt.Errorf("%w: route must have 2..%d positions", e.ErrInvalidInput, domain.MaxRoutePositions)
+	}
+	for _, p := range path {
+		if p[0] < -180 || p[0] > 180 || p[1] < -90 || p[1] > 90 {
+			return nil, fmt.Errorf("%w: position out of range", e.ErrInvalidInput)
+		}
+	}
+
+	// Длину проверяем до densify: иначе несколько тысяч вершин через полмира
+	// превращаются в десятки миллионов точек.
+	var length float64
+	for i := 1; i < len(path); i++ {
+		length += haversine(path[i-1][1], path[i-1][0], path[i][1], path[i][0])
+	}
+	if length > domain.MaxRouteLengthKM {
+		return nil, fmt.Errorf("%w: route is longer than %d km", e.ErrInvalidInput, domain.MaxRouteLengthKM)
+	}
+	return path, nil
+}
+
+func segmentBox(a, b domain.Position) geoindex.Box {
+	return geoindex.Box{
+		MinLng: math.Min(a[0], b[0]), MinLat: math.Min(a[1], b[1]),
+		MaxLng: math.Max(a[0], b[0]), MaxLat: math.Max(a[1], b[1]),
+	}
+}
+
+// densify дробит сегменты длиннее maxRouteStepKM равными частями.
+func densify(path []domain.Position) []domain.Position {
+	out := make([]domain.Position, 0, len(path))
+	out = append(out, path[0])
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		if n := math.Ceil(haversine(a[1], a[0], b[1], b[0]) / maxRouteStepKM); n > 1 {
+			for k := 1.0; k < n; k++ {
+				t := k / n
+				out = append(out, domain.Position{a[0] + (b[0]-a[0])*t, a[1] + (b[1]-a[1])*t})
+			}
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// intersectRoute возвращает длину маршрута и инциденты, активные в now,
+// с участками маршрута внутри их зон.
+func intersectRoute(incidents []domain.CachedIncident, path []domain.Position, now time.Time) (float64, []domain.RouteIncident) {
+	path = densify(path)
+
+	cum := make([]float64, len(path))
+	for i := 1; i < len(path); i++ {
+		cum[i] = cum[i-1] + haversine(path[i-1][1], path[i-1][0], path[i][1], path[i][0])
+	}
+
+	segs := make([]geoindex.Box, len(path)-1)
+	route := segmentBox(path[0], path[0])
+	for i := range segs {
+		segs[i] = segmentBox(path[i], path[i+1])
+		route = geoindex.Box{
+			MinLng: math.Min(route.MinLng, segs[i].MinLng), MinLat: math.Min(route.MinLat, segs[i].MinLat),
+			MaxLng: math.Max(route.MaxLng, segs[i].MaxLng), MaxLat: math.Max(route.MaxLat, segs[i].MaxLat),
+		}
+	}
+
+	hits := make([]domain.RouteIncident, 0)
+	for _, inc := range incidents {
+		if !inc.ActiveAt(now) {
+			continue
+		}
+		box := geoindex.BoxOf(inc, 0)
+		if !box.Overlaps(route) {
+			continue
+		}
+
+		var intervals []domain.RouteInterval
+		for i := range segs {
+			if !box.Overlaps(segs[i]) {
+				continue
+			}
+			segLen := cum[i+1] - cum[i]
+			if segLen == 0 {
+				continue
+			}
+			for _, span := range segmentInside(inc, path[i], path[i+1]) {
+				iv := domain.RouteInterval{StartKM: cum[i] + span[0]*segLen, EndKM: cum[i] + span[1]*segLen}
+				if n := len(intervals); n > 0 && iv.StartKM-intervals[n-1].EndKM < routeJoinEpsKM {
+					intervals[n-1].EndKM = iv.EndKM
+					continue
+				}
+				intervals = append(intervals, iv)
+			}
+		}
+		if len(intervals) > 0 {
+			hits = append(hits, domain.RouteIncident{Incident: inc.Summary(), Intersections: intervals})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Intersections[0].StartKM < hits[j].Intersections[0].StartKM
+	})
+	return cum[len(cum)-1], hits
+}
+
+// segmentInside — части сегмента a→b внутри зоны инцидента как отрезки
+// параметра t ∈ [0, 1]. Точка сегмента — линейная интерполяция координат.
+func segmentInside(inc domain.CachedIncident, a, b domain.Position) [][2]float64 {
+	if inc.Geometry != nil {
+		return polygonSpans(inc.Geometry, a, b)
+	}
+
+	// Круг: локальная проекция в км с началом в a, |t·d − c|² = r².
+	kx := deg2rad(1) * earthRadiusKm * math.Cos(deg2rad((a[1]+b[1])/2))
+	ky := deg2rad(1) * earthRadiusKm
+	dx, dy := (b[0]-a[0])*kx, (b[1]-a[1])*ky
+	cx, cy := (inc.Lng-a[0])*kx, (inc.Lat-a[1])*ky
+
+	qa := dx*dx + dy*dy
+	qb := -2 * (dx*cx + dy*cy)
+	qc := cx*cx + cy*cy - inc.RadiusKM*inc.RadiusKM
+	disc := qb*qb - 4*qa*qc
+	if disc < 0 {
+		return nil
+	}
+	sq := math.Sqrt(disc)
+	t0 := math.Max((-qb-sq)/(2*qa), 0)
+	t1 := math.Min((-qb+sq)/(2*qa), 1)
+	if t0 >= t1 {
+		return nil
+	}
+	return [][2]float64{{t0, t1}}
+}
+
+// polygonSpans режет сегмент по пересечениям с рёбрами всех колец и
+// проверяет середину каждого куска через Contains.
+func polygonSpans(g *domain.Geometry, a, b domain.Position) [][2]float64 {
+	ts := []float64{0, 1}
+	rx, ry := b[0]-a[0], b[1]-a[1]
+	for _, poly := range g.Polygons {
+		for _, ring := range poly {
+			for i := 1; i < len(ring); i++ {
+				p, q := ring[i-1], ring[i]
+				sx, sy := q[0]-p[0], q[1]-p[1]
+				den := rx*sy - ry*sx
+				if den == 0 {
+					continue
+				}
+				wx, wy := p[0]-a[0], p[1]-a[1]
+				t := (wx*sy - wy*sx) / den
+				u := (wx*ry - wy*rx) / den
+				if t > 0 && t < 1 && u >= 0 && u <= 1 {
+					ts = append(ts, t)
+				}
+			}
+		}
+	}
+	sort.Float64s(ts)
+
+	var spans [][2]float64
+	for i := 1; i < len(ts); i++ {
+		t0, t1 := ts[i-1], ts[i]
+		if t1 <= t0 {
+			continue
+		}
+		mid := (t0 + t1) / 2
+		if !g.Contains(a[1]+ry*mid, a[0]+rx*mid) {
+			continue
+		}
+		if n := len(spans); n > 0 && spans[n-1][1] == t0 {
+			spans[n-1][1] = t1
+			continue
+		}
+		spans = append(spans, [2]float64{t0, t1})
+	}
+	return spans
+}
@@ -0,0 +1,170 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"redCollar/internal/domain"
+	"redCollar/internal/service"
+	"redCollar/pkg/e"
+
+	mock_service "redCollar/internal/service/mocks"
+)
+
+func routeOf(coords ...domain.Position) *domain.LineString {
+	return &domain.LineString{Coordinates: coords}
+}
+
+func TestPublicIncidentService_CheckRoute_CircleIntervals(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	ended := time.Now().Add(-time.Hour)
+	fire := domain.CachedIncident{ID: uuid.New(), Title: "Пожар", Lat: 55.75, Lng: 37.61, RadiusKM: 1}
+	gas := domain.CachedIncident{ID: uuid.New(), Title: "Газ", Lat: 55.75, Lng: 37.57, RadiusKM: 0.5}
+	far := domain.CachedIncident{ID: uuid.New(), Title: "Потоп", Lat: 59.93, Lng: 30.33, RadiusKM: 1}
+	over := domain.CachedIncident{ID: uuid.New(), Title: "Завершён", Lat: 55.75, Lng: 37.63, RadiusKM: 1, EndsAt: &ended}
+
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{fire, far, over, gas}, nil).Times(1)
+
+	svc := service.NewPublicIncidentService(cache, &recordingSaver{}, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	resp, err := svc.CheckRoute(context.Background(), domain.RouteCheckRequest{
+		Route: routeOf(domain.Position{37.55, 55.75}, domain.Position{37.67, 55.75}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	// 0.01° долготы на широте 55.75 ≈ 0.626 км.
+	const kmPerCentiDeg = 0.6263
+	if math.Abs(resp.LengthKM-12*kmPerCentiDeg) > 0.01 {
+		t.Fatalf("unexpected length: %v", resp.LengthKM)
+	}
+	if len(resp.Incidents) != 2 || resp.Incidents[0].Incident.ID != gas.ID || resp.Incidents[1].Incident.ID != fire.ID {
+		t.Fatalf("unexpected incidents: %+v", resp.Incidents)
+	}
+
+	for _, tc := range []struct {
+		got        []domain.RouteInterval
+		start, end float64
+	}{
+		{resp.Incidents[0].Intersections, 2*kmPerCentiDeg - 0.5, 2*kmPerCentiDeg + 0.5},
+		{resp.Incidents[1].Intersections, 6*kmPerCentiDeg - 1, 6*kmPerCentiDeg + 1},
+	} {
+		if len(tc.got) != 1 || math.Abs(tc.got[0].StartKM-tc.start) > 0.02 || math.Abs(tc.got[0].EndKM-tc.end) > 0.02 {
+			t.Fatalf("expected [%.3f, %.3f], got %+v", tc.start, tc.end, tc.got)
+		}
+	}
+}
+
+func TestPublicIncidentService_CheckRoute_PolygonReentered(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	zone := domain.CachedIncident{
+		ID: uuid.New(), Title: "Перекрытие", Lat: 55.75, Lng: 37.61,
+		Geometry: &domain.Geometry{Type: domain.GeometryPolygon, Polygons: []domain.Polygon{{
+			{{37.60, 55.74}, {37.62, 55.74}, {37.62, 55.76}, {37.60, 55.76}, {37.60, 55.74}},
+		}}},
+	}
+
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{zone}, nil).Times(1)
+
+	svc := service.NewPublicIncidentService(cache, &recordingSaver{}, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	// Проезд через зону, разворот снаружи и обратно через неё же.
+	resp, err := svc.CheckRoute(context.Background(), domain.RouteCheckRequest{
+		Route: routeOf(
+			domain.Position{37.59, 55.745},
+			domain.Position{37.63, 55.745},
+			domain.Position{37.63, 55.755},
+			domain.Position{37.59, 55.755},
+		),
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	if len(resp.Incidents) != 1 {
+		t.Fatalf("unexpected incidents: %+v", resp.Incidents)
+	}
+	got := resp.Incidents[0].Intersections
+	if len(got) != 2 {
+		t.Fatalf("expected 2 intervals, got %+v", got)
+	}
+	for i, iv := range got {
+		if w := iv.EndKM - iv.StartKM; math.Abs(w-2*0.626) > 0.02 {
+			t.Fatalf("interval %d: unexpected width %.3f", i, w)
+		}
+	}
+	if got[0].EndKM >= got[1].StartKM {
+		t.Fatalf("intervals out of order: %+v", got)
+	}
+}
+
+func TestPublicIncidentService_CheckRoute_InvalidInput(t *testing.T) {
+	t.Parallel()
+
+	// Кэш не должен трогаться: запрос отбраковывается раньше.
+	svc := service.NewPublicIncidentService(nil, &recordingSaver{}, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	// Зигзаг через полмира: после дробления это были бы десятки миллионов точек.
+	zigzag := make([]domain.Position, domain.MaxRoutePositions)
+	for i := range zigzag {
+		zigzag[i] = domain.Position{float64(i%2) * 179, 0}
+	}
+
+	for name, req := range map[string]domain.RouteCheckRequest{
+		"empty":         {},
+		"both":          {Route: routeOf(domain.Position{37.6, 55.7}, domain.Position{37.7, 55.8}), Polyline: "_p~iF~ps|U_ulLnnqC"},
+		"single vertex": {Route: routeOf(domain.Position{37.6, 55.7})},
+		"out of range":  {Route: routeOf(domain.Position{37.6, 55.7}, domain.Position{190, 55.8})},
+		"bad polyline":  {Polyline: "_p~iF~ps|"},
+		"too long":      {Route: routeOf(zigzag...)},
+	} {
+		if _, err := svc.CheckRoute(context.Background(), req); !errors.Is(err, e.ErrInvalidInput) {
+			t.Fatalf("%s: expected ErrInvalidInput, got %v", name, err)
+		}
+	}
+}
+
+func TestPublicIncidentService_CheckRoute_Polyline(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock_service.NewMockIncidentCacheService(ctrl)
+	// Вторая вершина примера Google — (40.7, -120.95).
+	inc := domain.CachedIncident{ID: uuid.New(), Title: "Пожар", Lat: 40.7, Lng: -120.95, RadiusKM: 2}
+
+	cache.EXPECT().GetActive(gomock.Any()).Return([]domain.CachedIncident{inc}, nil).Times(1)
+
+	svc := service.NewPublicIncidentService(cache, &recordingSaver{}, nil, nil, slog.New(slog.DiscardHandler), 1, 0)
+
+	resp, err := svc.CheckRoute(context.Background(), domain.RouteCheckRequest{Polyline: "_p~iF~ps|U_ulLnnqC_mqNvxq`@"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(resp.Incidents) != 1 || len(resp.Incidents[0].Intersections) != 1 {
+		t.Fatalf("unexpected incidents: %+v", resp.Incidents)
+	}
+	iv := resp.Incidents[0].Intersections[0]
+	if math.Abs(iv.EndKM-iv.StartKM-4) > 0.1 {
+		t.Fatalf("expected ~4 km through the incident, got %+v", iv)
+	}
+}
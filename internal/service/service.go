@@ -11,18 +11,21 @@ import (
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 type AdminIncidentService interface {
 	Create(ctx context.Context, req domain.CreateIncidentRequest) (uuid.UUID, error)
-	List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error) // ← ИСПРАВЛЕНО
+	List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error)
 	Get(ctx context.Context, id uuid.UUID) (*domain.Incident, error)
 	Update(ctx context.Context, id uuid.UUID, req domain.UpdateIncidentRequest) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 type IncidentRepository interface {
-	Create(ctx context.Context, incident *domain.Incident) error
-	List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error)
+	Create(ctx context.Context, incident *domain.Incident, event domain.IncidentEventFunc) error
+	List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error)
 	Get(ctx context.Context, id uuid.UUID) (*domain.Incident, error)
-	Update(ctx context.Context, incident *domain.Incident) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, incident *domain.Incident, event domain.IncidentEventFunc) error
+	Delete(ctx context.Context, id uuid.UUID, event domain.IncidentEventFunc) error
 	ListActive(ctx context.Context) ([]*domain.Incident, error)
+	ActivateDue(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+	ExpireDue(ctx context.Context, now time.Time, event domain.IncidentEventFunc) ([]uuid.UUID, error)
+	NextScheduleBoundary(ctx context.Context, now time.Time) (*time.Time, error)
 }
 
 type IncidentCacheService interface {
@@ -30,28 +33,125 @@ type IncidentCacheService interface {
 	SetActive(ctx context.Context, incidents []domain.CachedIncident, ttl time.Duration) error
 }
 
+// IncidentCacheVersion — необязательное расширение кэша: версия списка
+// активных инцидентов. Если кэш его реализует, публичный сервис держит
+// локальный индекс и перестраивает его только при смене версии.
+type IncidentCacheVersion interface {
+	Version(ctx context.Context) (string, error)
+	GetActiveVersion(ctx context.Context) ([]domain.CachedIncident, string, error)
+}
+
+// AlertTracker отделяет новые попадания пользователя в инциденты от уже известных.
+type AlertTracker interface {
+	Track(ctx context.Context, userID string, hits []uuid.UUID, now time.Time) (domain.AlertTransitions, error)
+	// Revert откатывает переходы проверки, которую не удалось сохранить.
+	Revert(ctx context.Context, userID string, tr domain.AlertTransitions, now time.Time) error
+}
+
 type PublicIncidentService interface {
 	CheckLocation(ctx context.Context, req domain.LocationCheckRequest) (domain.LocationCheckResponse, error)
+	CheckLocationV2(ctx context.Context, req domain.LocationCheckRequest) (domain.LocationCheckV2Response, error)
+	CheckLocationBatch(ctx context.Context, req domain.LocationCheckBatchRequest) (domain.LocationCheckBatchResponse, error)
+	CheckRoute(ctx context.Context, req domain.RouteCheckRequest) (domain.RouteCheckResponse, error)
+	NearbyIncidents(ctx context.Context, req domain.NearbyRequest) (domain.NearbyResponse, error)
 }
 
 type StatsService interface {
 	GetStats(ctx context.Context, req domain.StatsRequest) (*domain.IncidentStats, error)
+	GetSeries(ctx context.Context, req domain.StatsSeriesRequest) (*domain.IncidentStats, error)
+	GetIncidentStats(ctx context.Context, id uuid.UUID, req domain.IncidentStatsRequest) (*domain.IncidentHitStats, error)
+}
+
+type StatsRepository interface {
+	CountUniqueUsers(ctx context.Context, minutes int) (int64, error)
+	CountTotalChecks(ctx context.Context, minutes int) (int64, error)
+	CountRange(ctx context.Context, from, to time.Time) (domain.StatsPoint, error)
+	CheckSeries(ctx context.Context, from, to time.Time, bucket domain.StatsBucket) ([]domain.StatsPoint, error)
+	HitsByIncident(ctx context.Context, from, to time.Time, limit int) ([]domain.IncidentHits, error)
+	IncidentHitStats(ctx context.Context, id uuid.UUID, from, to time.Time) (domain.IncidentHitStats, error)
+}
+
+type CheckHistoryService interface {
+	ListChecks(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error)
+	IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error)
+	IncidentOccupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error)
+	UserTimeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error)
+}
+
+type CheckHistoryRepository interface {
+	List(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error)
+	IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error)
+	Occupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error)
+	Timeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error)
+}
+type WebhookDeadLetterService interface {
+	ListDeadLetters(ctx context.Context, req domain.ListDeadLettersRequest) (domain.DeadLetterPage, error)
+	GetDeadLetter(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id uuid.UUID) error
+	DeleteDeadLetter(ctx context.Context, id uuid.UUID) error
+	PurgeDeadLetters(ctx context.Context) (int64, error)
+}
+
+type DeadLetterStore interface {
+	ListDeadLetters(ctx context.Context, offset, limit int) ([]domain.DeadLetter, int64, error)
+	GetDeadLetter(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id uuid.UUID) error
+	DeleteDeadLetter(ctx context.Context, id uuid.UUID) error
+	PurgeDeadLetters(ctx context.Context) (int64, error)
+}
+type WebhookSubscriptionService interface {
+	CreateSubscription(ctx context.Context, req domain.CreateWebhookSubscriptionRequest) (domain.CreateWebhookSubscriptionResponse, error)
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id uuid.UUID, req domain.UpdateWebhookSubscriptionRequest) error
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	RotateSecret(ctx context.Context, id uuid.UUID, req domain.RotateWebhookSecretRequest) (domain.RotateWebhookSecretResponse, error)
+}
+
+type WebhookSubscriptionRepository interface {
+	Create(ctx context.Context, sub *domain.WebhookSubscription) error
+	List(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	ListActive(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	Get(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error)
+	Update(ctx context.Context, sub *domain.WebhookSubscription) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookDeliveryLogService interface {
+	ListDeliveries(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error)
+}
+
+type WebhookDeliveryRepository interface {
+	Save(ctx context.Context, a *domain.WebhookDeliveryAttempt) error
+	List(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error)
 }
 type ctxKey string
 type Service struct {
 	AdminIncidentService  AdminIncidentService
 	PublicIncidentService PublicIncidentService
 	StatsService          StatsService
+	CheckHistoryService   CheckHistoryService
+	DeadLetterService     WebhookDeadLetterService
+	SubscriptionService   WebhookSubscriptionService
+	DeliveryLogService    WebhookDeliveryLogService
 }
 
 func NewService(
 	adminIncidentService AdminIncidentService,
 	publicIncidentService PublicIncidentService,
 	statsService StatsService,
+	checkHistoryService CheckHistoryService,
+	deadLetterService WebhookDeadLetterService,
+	subscriptionService WebhookSubscriptionService,
+	deliveryLogService WebhookDeliveryLogService,
 ) *Service {
 	return &Service{
 		AdminIncidentService:  adminIncidentService,
 		PublicIncidentService: publicIncidentService,
 		StatsService:          statsService,
+		CheckHistoryService:   checkHistoryService,
+		DeadLetterService:     deadLetterService,
+		SubscriptionService:   subscriptionService,
+		DeliveryLogService:    deliveryLogService,
 	}
 }
@@ -2,13 +2,19 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"redCollar/internal/domain"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
 )
 
-type StatsRepository interface {
-	CountUniqueUsers(ctx context.Context, minutes int) (int64, error)
-	CountTotalChecks(ctx context.Context, minutes int) (int64, error)
-}
+const (
+	// maxSeriesPoints ограничивает размер ответа: минутные корзины — максимум ~7 дней.
+	maxSeriesPoints = 10080
+	// maxIncidentHits — сколько инцидентов отдаём в разбивке по попаданиям.
+	maxIncidentHits = 1000
+)
 
 type statsService struct {
 	repo StatsRepository
@@ -40,3 +46,67 @@ func (s *statsService) GetStats(ctx context.Context, req domain.StatsRequest) (*
 		Minutes:     minutes,
 	}, nil
 }
+
+func (s *statsService) GetSeries(ctx context.Context, req domain.StatsSeriesRequest) (*domain.IncidentStats, error) {
+	if req.Bucket == "" {
+		req.Bucket = domain.BucketHour
+	}
+	step := req.Bucket.Duration()
+	if step == 0 {
+		return nil, fmt.Errorf("unknown bucket %q: %w", req.Bucket, e.ErrInvalidInput)
+	}
+	if !req.To.After(req.From) {
+		return nil, fmt.Errorf("to must be after from: %w", e.ErrInvalidInput)
+	}
+	if req.To.Sub(req.From)/step > maxSeriesPoints {
+		return nil, fmt.Errorf("too many buckets, max %d: %w", maxSeriesPoints, e.ErrInvalidInput)
+	}
+
+	totals, err := s.repo.CountRange(ctx, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := s.repo.CheckSeries(ctx, req.From, req.To, req.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := s.repo.HitsByIncident(ctx, req.From, req.To, maxIncidentHits)
+	if err != nil {
+		return nil, err
+	}
+
+	top := hits
+	if len(top) > req.Top {
+		top = top[:req.Top]
+	}
+
+	return &domain.IncidentStats{
+		UserCount:         totals.UniqueUsers,
+		TotalChecks:       totals.TotalChecks,
+		From:              &req.From,
+		To:                &req.To,
+		Bucket:            req.Bucket,
+		ChecksWithHits:    totals.ChecksWithHits,
+		ChecksWithoutHits: totals.ChecksWithoutHits,
+		Series:            series,
+		IncidentHits:      hits,
+		TopIncidents:      top,
+	}, nil
+}
+
+func (s *statsService) GetIncidentStats(ctx context.Context, id uuid.UUID, req domain.IncidentStatsRequest) (*domain.IncidentHitStats, error) {
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("empty incident id: %w", e.ErrInvalidInput)
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.To.After(req.From) {
+		return nil, fmt.Errorf("to must be after from: %w", e.ErrInvalidInput)
+	}
+
+	stats, err := s.repo.IncidentHitStats(ctx, id, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
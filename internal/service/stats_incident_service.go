@@ -3,8 +3,18 @@ package service
 import (
 	"context"
 	"redCollar/internal/domain"
+
+	"github.com/google/uuid"
 )
 
 func (s *Service) GetStats(ctx context.Context, req domain.StatsRequest) (*domain.IncidentStats, error) {
 	return s.StatsService.GetStats(ctx, req)
 }
+
+func (s *Service) GetSeries(ctx context.Context, req domain.StatsSeriesRequest) (*domain.IncidentStats, error) {
+	return s.StatsService.GetSeries(ctx, req)
+}
+
+func (s *Service) GetIncidentStats(ctx context.Context, id uuid.UUID, req domain.IncidentStatsRequest) (*domain.IncidentHitStats, error) {
+	return s.StatsService.GetIncidentStats(ctx, id, req)
+}
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 
 	"redCollar/internal/domain"
 	"redCollar/internal/service"
+	"redCollar/pkg/e"
 
 	mock_service "redCollar/internal/service/mocks" // <-- поправь
 )
@@ -32,7 +35,7 @@ func TestService_GetStats_Delegates_OK(t *testing.T) {
 		Return(want, nil).
 		Times(1)
 
-	svc := service.NewService(nil, nil, statsSvc)
+	svc := service.NewService(nil, nil, statsSvc, nil, nil, nil, nil)
 
 	got, err := svc.GetStats(context.Background(), req)
 	if err != nil {
@@ -59,7 +62,7 @@ func TestService_GetStats_Delegates_ErrorPropagated(t *testing.T) {
 		Return(nil, wantErr).
 		Times(1)
 
-	svc := service.NewService(nil, nil, statsSvc)
+	svc := service.NewService(nil, nil, statsSvc, nil, nil, nil, nil)
 
 	_, err := svc.GetStats(context.Background(), req)
 	if err == nil {
@@ -94,7 +97,7 @@ func TestService_GetStats_PassesContextValue(t *testing.T) {
 		}).
 		Times(1)
 
-	svc := service.NewService(nil, nil, statsSvc)
+	svc := service.NewService(nil, nil, statsSvc, nil, nil, nil, nil)
 
 	_, err := svc.GetStats(ctx, req)
 	if err != nil {
@@ -123,7 +126,7 @@ func TestService_GetStats_MultipleCalls_DifferentInputs(t *testing.T) {
 		Return(&domain.IncidentStats{UserCount: 2}, nil).
 		Times(1)
 
-	svc := service.NewService(nil, nil, statsSvc)
+	svc := service.NewService(nil, nil, statsSvc, nil, nil, nil, nil)
 
 	s1, err := svc.GetStats(context.Background(), req1)
 	if err != nil || s1.UserCount != 1 {
@@ -135,3 +138,100 @@ func TestService_GetStats_MultipleCalls_DifferentInputs(t *testing.T) {
 		t.Fatalf("unexpected s2=%+v err=%v", s2, err)
 	}
 }
+
+func TestStatsService_GetSeries_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockStatsRepository(ctrl)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+	hits := []domain.IncidentHits{
+		{IncidentID: uuid.New(), Hits: 10, UniqueUsers: 4},
+		{IncidentID: uuid.New(), Hits: 7, UniqueUsers: 7},
+		{IncidentID: uuid.New(), Hits: 1, UniqueUsers: 1},
+	}
+
+	repo.EXPECT().CountRange(gomock.Any(), from, to).
+		Return(domain.StatsPoint{TotalChecks: 30, ChecksWithHits: 12, ChecksWithoutHits: 18, UniqueUsers: 9}, nil)
+	repo.EXPECT().CheckSeries(gomock.Any(), from, to, domain.BucketHour).
+		Return([]domain.StatsPoint{{Bucket: from}, {Bucket: from.Add(time.Hour)}, {Bucket: from.Add(2 * time.Hour)}}, nil)
+	repo.EXPECT().HitsByIncident(gomock.Any(), from, to, gomock.Any()).
+		Return(hits, nil)
+
+	svc := service.NewStatsService(repo)
+
+	got, err := svc.GetSeries(context.Background(), domain.StatsSeriesRequest{From: from, To: to, Top: 2})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.TotalChecks != 30 || got.UserCount != 9 || got.ChecksWithHits != 12 || got.ChecksWithoutHits != 18 {
+		t.Fatalf("unexpected totals: %+v", got)
+	}
+	if got.Bucket != domain.BucketHour || len(got.Series) != 3 {
+		t.Fatalf("unexpected series: %+v", got)
+	}
+	if len(got.IncidentHits) != 3 || len(got.TopIncidents) != 2 || got.TopIncidents[0].Hits != 10 {
+		t.Fatalf("unexpected incident hits: %+v / %+v", got.IncidentHits, got.TopIncidents)
+	}
+}
+
+func TestStatsService_GetSeries_TooManyBuckets(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewStatsService(mock_service.NewMockStatsRepository(ctrl))
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := svc.GetSeries(context.Background(), domain.StatsSeriesRequest{
+		From:   from,
+		To:     from.AddDate(0, 1, 0),
+		Bucket: domain.BucketMinute,
+	})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestStatsService_GetIncidentStats_ReversedRange(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewStatsService(mock_service.NewMockStatsRepository(ctrl))
+
+	from := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	_, err := svc.GetIncidentStats(context.Background(), uuid.New(), domain.IncidentStatsRequest{From: from, To: from.Add(-time.Hour)})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestStatsService_GetIncidentStats_OK(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockStatsRepository(ctrl)
+	id := uuid.New()
+
+	repo.EXPECT().
+		IncidentHitStats(gomock.Any(), id, time.Time{}, time.Time{}).
+		Return(domain.IncidentHitStats{IncidentID: id, TotalHits: 3, UniqueUsers: 1}, nil).
+		Times(1)
+
+	got, err := service.NewStatsService(repo).GetIncidentStats(context.Background(), id, domain.IncidentStatsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.TotalHits != 3 || got.UniqueUsers != 1 {
+		t.Fatalf("unexpected stats: %+v", got)
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"redCollar/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func (s *Service) ListDeadLetters(ctx context.Context, req domain.ListDeadLettersRequest) (domain.DeadLetterPage, error) {
+	return s.DeadLetterService.ListDeadLetters(ctx, req)
+}
+
+func (s *Service) GetDeadLetter(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error) {
+	return s.DeadLetterService.GetDeadLetter(ctx, id)
+}
+
+func (s *Service) ReplayDeadLetter(ctx context.Context, id uuid.UUID) error {
+	return s.DeadLetterService.ReplayDeadLetter(ctx, id)
+}
+
+func (s *Service) DeleteDeadLetter(ctx context.Context, id uuid.UUID) error {
+	return s.DeadLetterService.DeleteDeadLetter(ctx, id)
+}
+
+func (s *Service) PurgeDeadLetters(ctx context.Context) (int64, error) {
+	return s.DeadLetterService.PurgeDeadLetters(ctx)
+}
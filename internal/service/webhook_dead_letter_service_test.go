@@ -0,0 +1,72 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"redCollar/internal/domain"
+	"redCollar/internal/service"
+	mock_service "redCollar/internal/service/mocks"
+	"redCollar/pkg/e"
+)
+
+func TestDeadLetterService_List_PageToOffset(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_service.NewMockDeadLetterStore(ctrl)
+	store.EXPECT().
+		ListDeadLetters(gomock.Any(), 20, 10).
+		Return([]domain.DeadLetter{{}}, int64(21), nil).
+		Times(1)
+
+	svc := service.NewDeadLetterService(store)
+
+	page, err := svc.ListDeadLetters(context.Background(), domain.ListDeadLettersRequest{Page: 3, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if page.Total != 21 || len(page.Items) != 1 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+}
+
+func TestDeadLetterService_Replay_NilID(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewDeadLetterService(mock_service.NewMockDeadLetterStore(ctrl))
+
+	if err := svc.ReplayDeadLetter(context.Background(), uuid.Nil); !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestService_GetDeadLetter_Delegates(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dlSvc := mock_service.NewMockWebhookDeadLetterService(ctrl)
+	id := uuid.New()
+
+	dlSvc.EXPECT().
+		GetDeadLetter(gomock.Any(), id).
+		Return(nil, e.ErrNotFound).
+		Times(1)
+
+	svc := service.NewService(nil, nil, nil, nil, dlSvc, nil, nil)
+
+	if _, err := svc.GetDeadLetter(context.Background(), id); !errors.Is(err, e.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	"redCollar/internal/domain"
+)
+
+func (s *Service) ListDeliveries(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error) {
+	return s.DeliveryLogService.ListDeliveries(ctx, req)
+}
@@ -0,0 +1,36 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"redCollar/internal/domain"
+	"redCollar/internal/service"
+	mock_service "redCollar/internal/service/mocks"
+)
+
+func TestDeliveryLogService_ListDeliveries_DefaultLimit(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockWebhookDeliveryRepository(ctrl)
+
+	repo.EXPECT().
+		List(gomock.Any(), domain.ListWebhookDeliveriesRequest{Limit: 20, Status: domain.WebhookDeliveryFailed}).
+		Return(domain.WebhookDeliveryPage{Items: []*domain.WebhookDeliveryAttempt{{Status: domain.WebhookDeliveryFailed}}}, nil).
+		Times(1)
+
+	svc := service.NewService(nil, nil, nil, nil, nil, nil, service.NewWebhookDeliveryLogService(repo))
+
+	page, err := svc.ListDeliveries(context.Background(), domain.ListWebhookDeliveriesRequest{Status: domain.WebhookDeliveryFailed})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(page.Items) != 1 {
+		t.Fatalf("expected 1 delivery got=%d", len(page.Items))
+	}
+}
@@ -3,104 +3,395 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"redCollar/internal/redis"
+	"redCollar/pkg/backoff"
 	"redCollar/pkg/e"
+	"redCollar/pkg/hostlimit"
+	"redCollar/pkg/webhooksig"
+	"strings"
 
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
 	"log/slog"
 	"redCollar/internal/config"
 	"redCollar/internal/domain"
+
+	"github.com/google/uuid"
 )
 
+// subscriptionsTTL — как часто перечитываем подписки; правки через API
+// доходят до отправителя с этой задержкой.
+const subscriptionsTTL = 30 * time.Second
+
+// hostBusyDelay — через сколько повторить доставку, если все слоты хоста заняты.
+const hostBusyDelay = 500 * time.Millisecond
+
+// responseSnippetLimit — сколько байт ответа получателя сохраняем в журнал.
+const responseSnippetLimit = 512
+
 type WebhookSender struct {
-	logger *slog.Logger
-	cfg    config.WebhookConfig
-	queue  *redis.WebhookQueue
-	http   *http.Client
+	logger     *slog.Logger
+	cfg        config.WebhookConfig
+	queue      *redis.WebhookQueue
+	subs       WebhookSubscriptionRepository
+	deliveries WebhookDeliveryRepository
+	http       *http.Client
+	backoff    backoff.Exponential
+	hosts      *hostlimit.Limiter
+
+	mu       sync.Mutex
+	cached   []*domain.WebhookSubscription
+	cachedAt time.Time
 }
 
-func NewWebhookSender(logger *slog.Logger, cfg config.WebhookConfig, q *redis.WebhookQueue) *WebhookSender {
+func NewWebhookSender(logger *slog.Logger, cfg config.WebhookConfig, q *redis.WebhookQueue, subs WebhookSubscriptionRepository, deliveries WebhookDeliveryRepository) *WebhookSender {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
 	return &WebhookSender{
-		logger: logger,
-		cfg:    cfg,
-		queue:  q,
-		http:   &http.Client{Timeout: 5 * time.Second},
+		logger:     logger,
+		cfg:        cfg,
+		queue:      q,
+		subs:       subs,
+		deliveries: deliveries,
+		http:       &http.Client{Timeout: 5 * time.Second},
+		backoff:    backoff.Exponential{Base: cfg.RetryBaseDelay, Max: cfg.RetryMaxDelay},
+		hosts:      hostlimit.New(cfg.PerHostLimit, cfg.BreakerThreshold, cfg.BreakerCooldown, hostBusyDelay),
 	}
 }
+
+// Run запускает cfg.Workers обработчиков очереди и возвращается, когда все
+// они остановились. После отмены ctx новые сообщения не берутся, а начатые
+// отправки получают DrainTimeout на завершение — поэтому Run нужно дождаться
+// до закрытия Redis.
 func (s *WebhookSender) Run(ctx context.Context) {
-	s.logger.Info("webhookSender STARTED", slog.String("url", s.cfg.URL))
+	s.logger.Info("webhookSender STARTED", slog.String("url", s.cfg.URL), slog.Int("workers", s.cfg.Workers))
+
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	var wg sync.WaitGroup
+	for range s.cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx, workCtx)
+		}()
+	}
 
-	for {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.logger.Info("webhookSender draining", slog.Duration("timeout", s.cfg.DrainTimeout))
 		select {
-		case <-ctx.Done():
-			s.logger.Info("webhookSender STOPPED", slog.String("reason", ctx.Err().Error()))
-			return
-		default:
+		case <-done:
+		case <-time.After(s.cfg.DrainTimeout):
+			s.logger.Warn("webhookSender drain timeout, interrupting in-flight sends")
+			cancelWork()
+			<-done
 		}
+	}
+
+	s.logger.Info("webhookSender STOPPED", slog.String("reason", context.Cause(ctx).Error()))
+}
 
-		payload, err := s.queue.BRPop(ctx, 5*time.Second)
+// work берёт сообщения, пока не отменён ctx; обрабатывает их в workCtx,
+// который переживает остановку на время дренажа.
+func (s *WebhookSender) work(ctx, workCtx context.Context) {
+	for ctx.Err() == nil {
+		d, err := s.queue.Claim(ctx, 5*time.Second)
 		if err != nil {
-			if errors.Is(err, e.ErrWebHookEmpty) {
+			if errors.Is(err, e.ErrWebHookEmpty) || ctx.Err() != nil {
 				continue
 			}
-			s.logger.Error("BRPop failed", slog.Any("error", err))
+			s.logger.Error("claim webhook failed", slog.Any("error", err))
 			time.Sleep(500 * time.Millisecond)
 			continue
 		}
 
-		s.logger.Info("sending webhook", slog.String("user_id", payload.UserID))
-		s.sendWithRetry(ctx, payload)
+		if d.SubscriptionID == nil {
+			s.fanOut(workCtx, d)
+			continue
+		}
+		s.deliver(workCtx, d)
 	}
 }
-func (s *WebhookSender) sendWithRetry(ctx context.Context, p domain.WebhookPayload) {
-	const maxRetries = 3
 
-	body, err := json.Marshal(p)
+// fanOut превращает событие в отдельные доставки для каждой подходящей подписки.
+func (s *WebhookSender) fanOut(ctx context.Context, d redis.WebhookDelivery) {
+	subs, err := s.subscriptions(ctx)
 	if err != nil {
-		s.logger.Error("marshal webhook payload failed", slog.String("error", err.Error()))
+		s.logger.Error("load webhook subscriptions failed", slog.Any("error", err))
+		s.fail(ctx, d, err)
 		return
 	}
 
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if ctx.Err() != nil {
-			s.logger.Info("stop retries due to context cancel")
-			return
+	now := time.Now().UTC()
+	msgs := make([]domain.WebhookMessage, 0, len(subs))
+	for _, sub := range subs {
+		ev, ok := sub.Match(d.Envelope)
+		if !ok {
+			continue
 		}
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+		subID := sub.ID
+		msgs = append(msgs, domain.WebhookMessage{
+			ID:             uuid.New(),
+			Envelope:       ev,
+			SubscriptionID: &subID,
+			EnqueuedAt:     now,
+		})
+	}
+
+	if err := s.queue.FanOut(ctx, d, msgs); err != nil {
+		s.logger.Error("fan out webhook failed", slog.String("id", d.ID.String()), slog.Any("error", err))
+		return
+	}
+	s.logger.Info("webhook event fanned out",
+		slog.String("id", d.ID.String()),
+		slog.String("event", string(d.Envelope.Type)),
+		slog.String("event_id", d.Envelope.ID.String()),
+		slog.Int("deliveries", len(msgs)),
+	)
+}
+
+// subscriptions — активные подписки из БД плюс WEBHOOK_URL как подписка
+// по умолчанию (uuid.Nil) на все события.
+func (s *WebhookSender) subscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.cachedAt) < subscriptionsTTL {
+		return s.cached, nil
+	}
+
+	subs := make([]*domain.WebhookSubscription, 0)
+	if s.cfg.URL != "" && !s.cfg.Disabled {
+		subs = append(subs, &domain.WebhookSubscription{
+			ID:             uuid.Nil,
+			URL:            s.cfg.URL,
+			Secret:         s.cfg.Secret,
+			PreviousSecret: s.cfg.PreviousSecret,
+			Active:         true,
+		})
+	}
+	if s.subs != nil {
+		active, err := s.subs.ListActive(ctx)
 		if err != nil {
-			s.logger.Error("create webhook request failed", slog.String("error", err.Error()))
-			return
+			return nil, err
 		}
+		subs = append(subs, active...)
+	}
 
-		req.Header.Set("Content-Type", "application/json")
+	s.cached, s.cachedAt = subs, time.Now()
+	return subs, nil
+}
 
-		resp, err := s.http.Do(req)
-		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			_ = resp.Body.Close()
-			return
+func (s *WebhookSender) subscription(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	subs, err := s.subscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		if sub.ID == id {
+			return sub, nil
 		}
-		if resp != nil {
-			_ = resp.Body.Close()
+	}
+	return nil, nil
+}
+
+// deliver делает одну попытку доставки подписке. Неудачное сообщение
+// откладывается с экспоненциальной задержкой и не держит остальные.
+func (s *WebhookSender) deliver(ctx context.Context, d redis.WebhookDelivery) {
+	sub, err := s.subscription(ctx, *d.SubscriptionID)
+	if err != nil {
+		s.logger.Error("load webhook subscriptions failed", slog.Any("error", err))
+		s.fail(ctx, d, err)
+		return
+	}
+	if sub == nil {
+		s.logger.Warn("webhook subscription gone, dropping delivery",
+			slog.String("id", d.ID.String()),
+			slog.String("subscription_id", d.SubscriptionID.String()),
+		)
+		if err := s.queue.Ack(ctx, d); err != nil {
+			s.logger.Error("ack webhook failed", slog.String("id", d.ID.String()), slog.Any("error", err))
 		}
+		return
+	}
 
-		reason := "unknown"
-		if err != nil {
-			reason = err.Error()
-		} else if resp != nil {
-			reason = resp.Status
+	host := webhookHost(sub.URL)
+	if at, ok := s.hosts.Acquire(host, time.Now()); !ok {
+		if err := s.queue.Postpone(ctx, d, at); err != nil {
+			s.logger.Error("postpone webhook failed", slog.String("id", d.ID.String()), slog.Any("error", err))
 		}
+		s.logger.Debug("webhook postponed", slog.String("id", d.ID.String()), slog.String("host", host), slog.Time("until", at))
+		return
+	}
 
-		s.logger.Warn("webhook failed",
-			slog.Int("attempt", attempt),
-			slog.String("url", s.cfg.URL),
-			slog.String("reason", reason),
-		)
+	s.logger.Info("sending webhook",
+		slog.String("id", d.ID.String()),
+		slog.String("subscription_id", sub.ID.String()),
+		slog.String("event", string(d.Envelope.Type)),
+	)
+
+	res, err := s.send(ctx, sub, d)
+	if s.hosts.Release(host, err == nil, time.Now()) {
+		s.logger.Warn("webhook receiver paused", slog.String("host", host), slog.Duration("cooldown", s.cfg.BreakerCooldown))
+	}
+	if err == nil {
+		if err := s.queue.Ack(ctx, d); err != nil {
+			s.logger.Error("ack webhook failed", slog.String("id", d.ID.String()), slog.Any("error", err))
+		}
+		s.record(ctx, sub, d, res, domain.WebhookDeliverySucceeded, nil)
+		return
+	}
+
+	s.logger.Warn("webhook failed",
+		slog.String("id", d.ID.String()),
+		slog.Int("attempt", d.Attempts+1),
+		slog.String("url", sub.URL),
+		slog.String("reason", err.Error()),
+	)
+	s.record(ctx, sub, d, res, s.fail(ctx, d, err), err)
+}
+
+// fail откладывает повтор или переносит сообщение в dead letters, если
+// исчерпаны попытки или следующая попытка вышла бы за MaxAge.
+// Возвращает итог попытки для журнала; пустой — доставку прервала остановка.
+func (s *WebhookSender) fail(ctx context.Context, d redis.WebhookDelivery, err error) domain.WebhookDeliveryStatus {
+	if ctx.Err() != nil {
+		// Остаётся в processing; планировщик вернёт его в очередь через VisibilityTimeout.
+		s.logger.Info("webhook interrupted by shutdown", slog.String("id", d.ID.String()))
+		return ""
+	}
+
+	attempt := d.Attempts + 1
+	now := time.Now()
+	next := now.Add(s.backoff.Delay(attempt))
+
+	reason := err.Error()
+	switch {
+	case attempt >= s.cfg.MaxAttempts:
+	case s.cfg.MaxAge > 0 && next.Sub(d.EnqueuedAt) > s.cfg.MaxAge:
+		reason = "max age exceeded: " + reason
+	default:
+		if err := s.queue.Retry(ctx, d, next); err != nil {
+			s.logger.Error("schedule webhook retry failed", slog.String("id", d.ID.String()), slog.Any("error", err))
+		} else {
+			s.logger.Info("webhook retry scheduled", slog.String("id", d.ID.String()), slog.Time("next_attempt_at", next))
+		}
+		return domain.WebhookDeliveryFailed
+	}
+
+	if err := s.queue.DeadLetter(ctx, d, reason, now); err != nil {
+		s.logger.Error("dead-letter webhook failed", slog.String("id", d.ID.String()), slog.Any("error", err))
+		return domain.WebhookDeliveryDead
+	}
+	s.logger.Error("webhook moved to dead letters", slog.String("id", d.ID.String()), slog.Int("attempts", attempt))
+	return domain.WebhookDeliveryDead
+}
+
+// record пишет попытку в журнал доставок. Ошибка журнала на доставку не
+// влияет, а прерванная остановкой попытка не пишется — она повторится.
+func (s *WebhookSender) record(ctx context.Context, sub *domain.WebhookSubscription, d redis.WebhookDelivery, res sendResult, status domain.WebhookDeliveryStatus, sendErr error) {
+	if s.deliveries == nil || status == "" || ctx.Err() != nil {
+		return
+	}
+
+	a := &domain.WebhookDeliveryAttempt{
+		DeliveryID:      d.ID,
+		EventID:         d.Envelope.ID,
+		SubscriptionID:  sub.ID,
+		URL:             sub.URL,
+		UserID:          d.Envelope.UserID(),
+		Event:           d.Envelope.Type,
+		Attempt:         d.Attempts + 1,
+		Status:          status,
+		PayloadHash:     res.payloadHash,
+		StatusCode:      res.statusCode,
+		LatencyMs:       res.latency.Milliseconds(),
+		ResponseSnippet: res.snippet,
+	}
+	if sendErr != nil {
+		a.Error = sendErr.Error()
+	}
+
+	if err := s.deliveries.Save(ctx, a); err != nil {
+		s.logger.Error("save webhook delivery attempt failed", slog.String("id", d.ID.String()), slog.Any("error", err))
+	}
+}
+
+// webhookHost — ключ лимитов и circuit breaker: host[:port] получателя.
+func webhookHost(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	return u.Host
+}
+
+// sendResult — детали попытки для журнала доставок.
+type sendResult struct {
+	payloadHash string
+	statusCode  int
+	latency     time.Duration
+	snippet     string
+}
+
+// send подписывает тело секретами подписки: X-Timestamp меняется на каждой
+// попытке, X-Delivery-ID — нет, по нему получатель отсекает дубли.
+func (s *WebhookSender) send(ctx context.Context, sub *domain.WebhookSubscription, d redis.WebhookDelivery) (sendResult, error) {
+	var res sendResult
+
+	body, err := json.Marshal(d.Envelope)
+	if err != nil {
+		return res, fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	res.payloadHash = hex.EncodeToString(sum[:])
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return res, fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", string(d.Envelope.Type))
+	now := time.Now()
+	webhooksig.SetHeaders(req.Header, d.ID.String(), sub.SigningSecrets(now), now, body)
+
+	start := time.Now()
+	resp, err := s.http.Do(req)
+	res.latency = time.Since(start)
+	if err != nil {
+		return res, err
+	}
+	defer resp.Body.Close()
 
-		time.Sleep(time.Duration(attempt) * time.Second)
+	res.statusCode = resp.StatusCode
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, responseSnippetLimit))
+	// Postgres TEXT не принимает NUL и битый UTF-8.
+	res.snippet = strings.ToValidUTF8(strings.ReplaceAll(string(snippet), "\x00", ""), "")
 
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return res, errors.New(resp.Status)
 	}
+	return res, nil
 }
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"redCollar/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func (s *Service) CreateSubscription(ctx context.Context, req domain.CreateWebhookSubscriptionRequest) (domain.CreateWebhookSubscriptionResponse, error) {
+	return s.SubscriptionService.CreateSubscription(ctx, req)
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return s.SubscriptionService.ListSubscriptions(ctx)
+}
+
+func (s *Service) GetSubscription(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	return s.SubscriptionService.GetSubscription(ctx, id)
+}
+
+func (s *Service) UpdateSubscription(ctx context.Context, id uuid.UUID, req domain.UpdateWebhookSubscriptionRequest) error {
+	return s.SubscriptionService.UpdateSubscription(ctx, id, req)
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return s.SubscriptionService.DeleteSubscription(ctx, id)
+}
+
+func (s *Service) RotateSecret(ctx context.Context, id uuid.UUID, req domain.RotateWebhookSecretRequest) (domain.RotateWebhookSecretResponse, error) {
+	return s.SubscriptionService.RotateSecret(ctx, id, req)
+}
@@ -0,0 +1,208 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"redCollar/internal/domain"
+	"redCollar/internal/service"
+	mock_service "redCollar/internal/service/mocks"
+	"redCollar/pkg/e"
+)
+
+func TestSubscriptionService_Create_GeneratesSecret(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockWebhookSubscriptionRepository(ctrl)
+
+	var saved *domain.WebhookSubscription
+	repo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub *domain.WebhookSubscription) error {
+			saved = sub
+			return nil
+		}).
+		Times(1)
+
+	svc := service.NewWebhookSubscriptionService(repo, time.Hour)
+
+	res, err := svc.CreateSubscription(context.Background(), domain.CreateWebhookSubscriptionRequest{
+		URL:        "https://example.com/hook",
+		Categories: []domain.IncidentCategory{domain.CategoryFire},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if res.Secret == "" || res.Secret != saved.Secret {
+		t.Fatalf("expected generated secret to be saved and returned")
+	}
+	if res.ID != saved.ID || !saved.Active {
+		t.Fatalf("unexpected saved subscription: %+v", saved)
+	}
+}
+
+func TestSubscriptionService_Create_InvalidURL(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewWebhookSubscriptionService(mock_service.NewMockWebhookSubscriptionRepository(ctrl), time.Hour)
+
+	_, err := svc.CreateSubscription(context.Background(), domain.CreateWebhookSubscriptionRequest{URL: "ftp://example.com"})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestSubscriptionService_Update_ClearArea(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockWebhookSubscriptionRepository(ctrl)
+	id := uuid.New()
+	active := false
+
+	repo.EXPECT().
+		Get(gomock.Any(), id).
+		Return(&domain.WebhookSubscription{
+			ID:     id,
+			URL:    "https://example.com/hook",
+			Active: true,
+			Area:   &domain.Geometry{Type: domain.GeometryPolygon},
+		}, nil).
+		Times(1)
+	repo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub *domain.WebhookSubscription) error {
+			if sub.Area != nil || sub.Active {
+				t.Fatalf("unexpected update: %+v", sub)
+			}
+			return nil
+		}).
+		Times(1)
+
+	svc := service.NewWebhookSubscriptionService(repo, time.Hour)
+
+	err := svc.UpdateSubscription(context.Background(), id, domain.UpdateWebhookSubscriptionRequest{ClearArea: true, Active: &active})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestSubscriptionService_RotateSecret_KeepsPreviousDuringGrace(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockWebhookSubscriptionRepository(ctrl)
+	id := uuid.New()
+
+	repo.EXPECT().
+		Get(gomock.Any(), id).
+		Return(&domain.WebhookSubscription{ID: id, URL: "https://example.com/hook", Secret: "old-secret-0123456789", Active: true}, nil).
+		Times(1)
+
+	var saved *domain.WebhookSubscription
+	repo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub *domain.WebhookSubscription) error {
+			saved = sub
+			return nil
+		}).
+		Times(1)
+
+	svc := service.NewWebhookSubscriptionService(repo, time.Hour)
+
+	before := time.Now()
+	res, err := svc.RotateSecret(context.Background(), id, domain.RotateWebhookSecretRequest{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if res.Secret == "" || res.Secret == "old-secret-0123456789" || saved.Secret != res.Secret {
+		t.Fatalf("expected new generated secret, got %q", res.Secret)
+	}
+	if saved.PreviousSecret != "old-secret-0123456789" {
+		t.Fatalf("expected old secret kept as previous, got %q", saved.PreviousSecret)
+	}
+	if res.PreviousSecretExpiresAt.Before(before.Add(time.Hour)) {
+		t.Fatalf("unexpected grace expiry: %v", res.PreviousSecretExpiresAt)
+	}
+	if got := saved.SigningSecrets(before); len(got) != 2 {
+		t.Fatalf("expected both secrets during grace, got %v", got)
+	}
+	if got := saved.SigningSecrets(before.Add(2 * time.Hour)); len(got) != 1 || got[0] != res.Secret {
+		t.Fatalf("expected only new secret after grace, got %v", got)
+	}
+}
+
+func TestSubscriptionService_Update_SecretRotates(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockWebhookSubscriptionRepository(ctrl)
+	id := uuid.New()
+
+	repo.EXPECT().
+		Get(gomock.Any(), id).
+		Return(&domain.WebhookSubscription{ID: id, URL: "https://example.com/hook", Secret: "old-secret-0123456789", Active: true}, nil).
+		Times(1)
+
+	var saved *domain.WebhookSubscription
+	repo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub *domain.WebhookSubscription) error {
+			saved = sub
+			return nil
+		}).
+		Times(1)
+
+	svc := service.NewWebhookSubscriptionService(repo, time.Hour)
+
+	secret := "new-secret-0123456789"
+	before := time.Now()
+	if err := svc.UpdateSubscription(context.Background(), id, domain.UpdateWebhookSubscriptionRequest{Secret: &secret}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if saved.Secret != secret || saved.PreviousSecret != "old-secret-0123456789" {
+		t.Fatalf("expected old secret kept as previous, got %q / %q", saved.Secret, saved.PreviousSecret)
+	}
+	if got := saved.SigningSecrets(before); len(got) != 2 {
+		t.Fatalf("expected both secrets during grace, got %v", got)
+	}
+}
+
+func TestSubscriptionService_RotateSecret_SameSecret(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_service.NewMockWebhookSubscriptionRepository(ctrl)
+	id := uuid.New()
+
+	repo.EXPECT().
+		Get(gomock.Any(), id).
+		Return(&domain.WebhookSubscription{ID: id, Secret: "same-secret-0123456789"}, nil).
+		Times(1)
+
+	svc := service.NewWebhookSubscriptionService(repo, time.Hour)
+
+	_, err := svc.RotateSecret(context.Background(), id, domain.RotateWebhookSecretRequest{Secret: "same-secret-0123456789"})
+	if !errors.Is(err, e.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+)
+
+type deadLetterService struct {
+	store DeadLetterStore
+}
+
+func NewDeadLetterService(store DeadLetterStore) WebhookDeadLetterService {
+	return &deadLetterService{store: store}
+}
+
+func (s *deadLetterService) ListDeadLetters(ctx context.Context, req domain.ListDeadLettersRequest) (domain.DeadLetterPage, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	items, total, err := s.store.ListDeadLetters(ctx, (req.Page-1)*req.Limit, req.Limit)
+	if err != nil {
+		return domain.DeadLetterPage{}, err
+	}
+	return domain.DeadLetterPage{Items: items, Total: total}, nil
+}
+
+func (s *deadLetterService) GetDeadLetter(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error) {
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("empty dead letter id: %w", e.ErrInvalidInput)
+	}
+	return s.store.GetDeadLetter(ctx, id)
+}
+
+func (s *deadLetterService) ReplayDeadLetter(ctx context.Context, id uuid.UUID) error {
+	if id == uuid.Nil {
+		return fmt.Errorf("empty dead letter id: %w", e.ErrInvalidInput)
+	}
+	return s.store.ReplayDeadLetter(ctx, id)
+}
+
+func (s *deadLetterService) DeleteDeadLetter(ctx context.Context, id uuid.UUID) error {
+	if id == uuid.Nil {
+		return fmt.Errorf("empty dead letter id: %w", e.ErrInvalidInput)
+	}
+	return s.store.DeleteDeadLetter(ctx, id)
+}
+
+func (s *deadLetterService) PurgeDeadLetters(ctx context.Context) (int64, error) {
+	return s.store.PurgeDeadLetters(ctx)
+}
+
+type deliveryLogService struct {
+	repo WebhookDeliveryRepository
+}
+
+func NewWebhookDeliveryLogService(repo WebhookDeliveryRepository) WebhookDeliveryLogService {
+	return &deliveryLogService{repo: repo}
+}
+
+func (s *deliveryLogService) ListDeliveries(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error) {
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	return s.repo.List(ctx, req)
+}
+
+type subscriptionService struct {
+	repo          WebhookSubscriptionRepository
+	rotationGrace time.Duration
+}
+
+func NewWebhookSubscriptionService(repo WebhookSubscriptionRepository, rotationGrace time.Duration) WebhookSubscriptionService {
+	return &subscriptionService{repo: repo, rotationGrace: rotationGrace}
+}
+
+func (s *subscriptionService) CreateSubscription(ctx context.Context, req domain.CreateWebhookSubscriptionRequest) (domain.CreateWebhookSubscriptionResponse, error) {
+	sub := &domain.WebhookSubscription{
+		ID:         uuid.New(),
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Categories: req.Categories,
+		Severities: req.Severities,
+		Area:       req.Area,
+		Active:     true,
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	if sub.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return domain.CreateWebhookSubscriptionResponse{}, err
+		}
+		sub.Secret = secret
+	}
+	if err := validateSubscription(sub); err != nil {
+		return domain.CreateWebhookSubscriptionResponse{}, err
+	}
+
+	if err := s.repo.Create(ctx, sub); err != nil {
+		return domain.CreateWebhookSubscriptionResponse{}, err
+	}
+	return domain.CreateWebhookSubscriptionResponse{ID: sub.ID, Secret: sub.Secret}, nil
+}
+
+func (s *subscriptionService) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *subscriptionService) GetSubscription(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *subscriptionService) UpdateSubscription(ctx context.Context, id uuid.UUID, req domain.UpdateWebhookSubscriptionRequest) error {
+	if req.Area != nil && req.ClearArea {
+		return fmt.Errorf("area and clear_area are mutually exclusive: %w", e.ErrInvalidInput)
+	}
+	sub, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if req.URL != nil {
+		sub.URL = *req.URL
+	}
+	if req.Secret != nil && *req.Secret != sub.Secret {
+		// Смена секрета через PATCH — та же ротация: старый действует ещё rotationGrace.
+		s.rotate(sub, *req.Secret)
+	}
+	if req.EventTypes != nil {
+		sub.EventTypes = *req.EventTypes
+	}
+	if req.Categories != nil {
+		sub.Categories = *req.Categories
+	}
+	if req.Severities != nil {
+		sub.Severities = *req.Severities
+	}
+	if req.Area != nil {
+		sub.Area = req.Area
+	}
+	if req.ClearArea {
+		sub.Area = nil
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	if err := validateSubscription(sub); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, sub)
+}
+
+func (s *subscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// RotateSecret делает текущий секрет предыдущим на rotationGrace и ставит новый:
+// пока получатель переключается, доставки подписаны обоими.
+func (s *subscriptionService) RotateSecret(ctx context.Context, id uuid.UUID, req domain.RotateWebhookSecretRequest) (domain.RotateWebhookSecretResponse, error) {
+	sub, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return domain.RotateWebhookSecretResponse{}, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return domain.RotateWebhookSecretResponse{}, err
+		}
+	}
+	if secret == sub.Secret {
+		return domain.RotateWebhookSecretResponse{}, fmt.Errorf("new secret equals current: %w", e.ErrInvalidInput)
+	}
+
+	expiresAt := s.rotate(sub, secret)
+	if err := s.repo.Update(ctx, sub); err != nil {
+		return domain.RotateWebhookSecretResponse{}, err
+	}
+	return domain.RotateWebhookSecretResponse{ID: sub.ID, Secret: secret, PreviousSecretExpiresAt: expiresAt}, nil
+}
+
+// rotate ставит новый секрет, оставляя текущий предыдущим до возвращаемого момента.
+func (s *subscriptionService) rotate(sub *domain.WebhookSubscription, secret string) time.Time {
+	expiresAt := time.Now().UTC().Add(s.rotationGrace)
+	sub.PreviousSecret, sub.PreviousSecretExpiresAt = sub.Secret, &expiresAt
+	sub.Secret = secret
+	return expiresAt
+}
+
+func validateSubscription(sub *domain.WebhookSubscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be absolute http(s): %w", e.ErrInvalidInput)
+	}
+	for _, t := range sub.EventTypes {
+		if !t.Valid() {
+			return fmt.Errorf("unknown event type %q: %w", t, e.ErrInvalidInput)
+		}
+	}
+	for _, c := range sub.Categories {
+		if !c.Valid() {
+			return fmt.Errorf("unknown category %q: %w", c, e.ErrInvalidInput)
+		}
+	}
+	for _, sv := range sub.Severities {
+		if !sv.Valid() {
+			return fmt.Errorf("unknown severity %q: %w", sv, e.ErrInvalidInput)
+		}
+	}
+	if sub.Area != nil {
+		if err := sub.Area.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"redCollar/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// insertChecks пишет пакет проверок одним COPY, затем применяет их по
+// порядку, как insertCheck: присутствие и события. Порядок задаёт вызывающий —
+// для присутствия проверки одного пользователя должны идти по времени.
+func insertChecks(ctx context.Context, tx pgx.Tx, checks []*domain.LocationCheck, events domain.CheckBatchEventFunc) error {
+	ids := make([]uuid.UUID, len(checks))
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"location_checks"},
+		[]string{"id", "user_id", "lat", "lng", "incident_ids", "checked_at"},
+		pgx.CopyFromSlice(len(checks), func(i int) ([]any, error) {
+			c := checks[i]
+			ids[i] = c.ID
+			incidentIDs := c.IncidentIDs
+			if incidentIDs == nil {
+				// COPY не подставляет DEFAULT, а колонка NOT NULL.
+				incidentIDs = []uuid.UUID{}
+			}
+			return []any{c.ID, c.UserID, c.Lat, c.Lng, incidentIDs, c.CheckedAt}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("copy checks: %w", err)
+	}
+
+	if err := backfillRollups(ctx, tx, ids); err != nil {
+		return fmt.Errorf("backfill rollups: %w", err)
+	}
+
+	for i, check := range checks {
+		var fn domain.CheckEventFunc
+		if events != nil {
+			fn = func(transitions []domain.OccupancyTransition) ([]domain.WebhookEvent, error) {
+				return events(i, transitions)
+			}
+		}
+		if err := applyCheck(ctx, tx, check, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillRollups досчитывает в агрегаты проверки пакета, которые старше
+// водяного знака свёртки: RollupChecks за ними уже не вернётся. FOR SHARE
+// держит водяной знак до коммита — свёртка, начатая позже, увидит пакет
+// целиком и свернёт то, что не старше знака.
+func backfillRollups(ctx context.Context, tx pgx.Tx, ids []uuid.UUID) error {
+	var watermark time.Time
+	err := tx.QueryRow(ctx,
+		`SELECT rolled_up_to FROM location_checks_rollup_state WHERE name = $1 FOR SHARE`,
+		rollupStateName,
+	).Scan(&watermark)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Свёртка ещё не запускалась и начнёт с самой старой проверки.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, q := range []string{`
+INSERT INTO location_checks_rollup_minute AS r (bucket, total_checks, checks_with_hits)
+SELECT date_bin('1 minute', checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'),
+       COUNT(*),
+       COUNT(*) FILTER (WHERE cardinality(incident_ids) > 0)
+FROM location_checks
+WHERE id = ANY($1::uuid[]) AND checked_at < $2
+GROUP BY 1
+ON CONFLICT (bucket) DO UPDATE
+SET total_checks     = r.total_checks + EXCLUDED.total_checks,
+    checks_with_hits = r.checks_with_hits + EXCLUDED.checks_with_hits
+`, `
+INSERT INTO location_checks_rollup_hour AS r (bucket, total_checks, checks_with_hits)
+SELECT date_bin('1 hour', checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'),
+       COUNT(*),
+       COUNT(*) FILTER (WHERE cardinality(incident_ids) > 0)
+FROM location_checks
+WHERE id = ANY($1::uuid[]) AND checked_at < $2
+GROUP BY 1
+ON CONFLICT (bucket) DO UPDATE
+SET total_checks     = r.total_checks + EXCLUDED.total_checks,
+    checks_with_hits = r.checks_with_hits + EXCLUDED.checks_with_hits
+`, `
+INSERT INTO location_checks_rollup_users (bucket, user_id)
+SELECT DISTINCT date_bin('1 minute', checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'), user_id
+FROM location_checks
+WHERE id = ANY($1::uuid[]) AND checked_at < $2
+ON CONFLICT DO NOTHING
+`, `
+INSERT INTO location_checks_rollup_incident_minute AS r (bucket, incident_id, hits)
+SELECT date_bin('1 minute', c.checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'), h.incident_id, COUNT(*)
+FROM location_checks c
+CROSS JOIN LATERAL unnest(c.incident_ids) AS h(incident_id)
+WHERE c.id = ANY($1::uuid[]) AND c.checked_at < $2
+GROUP BY 1, 2
+ON CONFLICT (bucket, incident_id) DO UPDATE
+SET hits = r.hits + EXCLUDED.hits
+`, `
+INSERT INTO location_checks_rollup_incident_users (bucket, incident_id, user_id)
+SELECT DISTINCT date_bin('1 minute', c.checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'), h.incident_id, c.user_id
+FROM location_checks c
+CROSS JOIN LATERAL unnest(c.incident_ids) AS h(incident_id)
+WHERE c.id = ANY($1::uuid[]) AND c.checked_at < $2
+ON CONFLICT DO NOTHING
+`} {
+		if _, err := tx.Exec(ctx, q, ids, watermark); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -22,39 +22,52 @@ func NewIncidentPublic(pool *pgxpool.Pool, logger *slog.Logger) *IncidentPublic
 	return &IncidentPublic{pool: pool, logger: logger}
 }
 
-func (p *IncidentPublic) FindNearby(ctx context.Context, lat, lng, radiusKm float64) ([]uuid.UUID, error) {
+// FindNearby — активные инциденты не дальше radiusKm от точки, ближние первыми.
+// Расстояние — до центра круга или до полигона (0, если точка внутри).
+func (p *IncidentPublic) FindNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]domain.NearbyIncident, error) {
 	const op = "postgres.Incident.FindNearby"
 
 	if lat < -90 || lat > 90 || lng < -180 || lng > 180 || radiusKm <= 0 {
 		return nil, fmt.Errorf("%s: %w", op, e.ErrInvalidInput)
 	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
 
 	const query = `
-SELECT id
+SELECT id, title, description, severity, category,
+       ST_Y(geo_point::geometry) AS lat,
+       ST_X(geo_point::geometry) AS lng,
+       COALESCE(radius_km, 0) AS radius_km,
+       ST_Distance(COALESCE(geo_area, geo_point), ST_MakePoint($1, $2)::geography) / 1000 AS distance_km
 FROM incidents
 WHERE status = 'active'
+  AND (starts_at IS NULL OR starts_at <= NOW())
+  AND (ends_at IS NULL OR ends_at > NOW())
   AND ST_DWithin(
-    geo_point,
+    COALESCE(geo_area, geo_point),
     ST_MakePoint($1, $2)::geography,
     $3 * 1000
   )
+ORDER BY distance_km, id
+LIMIT $4
 `
 
-	rows, err := p.pool.Query(ctx, query, lng, lat, radiusKm)
+	rows, err := p.pool.Query(ctx, query, lng, lat, radiusKm, limit)
 	if err != nil {
 		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
 		return nil, e.WrapError(ctx, op, err)
 	}
 	defer rows.Close()
 
-	ids := make([]uuid.UUID, 0, 8)
+	incidents := make([]domain.NearbyIncident, 0, limit)
 	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
+		var n domain.NearbyIncident
+		if err := rows.Scan(&n.ID, &n.Title, &n.Description, &n.Severity, &n.Category, &n.Lat, &n.Lng, &n.RadiusKM, &n.DistanceKM); err != nil {
 			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
 			return nil, e.WrapError(ctx, op, err)
 		}
-		ids = append(ids, id)
+		incidents = append(incidents, n)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -62,10 +75,10 @@ WHERE status = 'active'
 		return nil, e.WrapError(ctx, op, err)
 	}
 
-	return ids, nil
+	return incidents, nil
 }
 
-func (p *IncidentPublic) SaveCheck(ctx context.Context, check *domain.LocationCheck) error {
+func (p *IncidentPublic) SaveCheck(ctx context.Context, check *domain.LocationCheck, events domain.CheckEventFunc) error {
 	const op = "postgres.LocationCheck.Save"
 
 	if check == nil {
@@ -78,11 +91,6 @@ func (p *IncidentPublic) SaveCheck(ctx context.Context, check *domain.LocationCh
 		return fmt.Errorf("%s: %w", op, e.ErrInvalidCoordinates)
 	}
 
-	const query = `
-INSERT INTO location_checks (id, user_id, lat, lng, incident_ids, checked_at)
-VALUES ($1, $2, $3, $4, $5, $6)
-`
-
 	if check.ID == uuid.Nil {
 		check.ID = uuid.New()
 	}
@@ -90,20 +98,20 @@ VALUES ($1, $2, $3, $4, $5, $6)
 		check.CheckedAt = time.Now().UTC()
 	}
 
-	_, err := p.pool.Exec(ctx, query,
-		check.ID,
-		check.UserID,
-		check.Lat,
-		check.Lng,
-		check.IncidentIDs,
-		check.CheckedAt,
-	)
+	tx, err := p.pool.Begin(ctx)
 	if err != nil {
-		p.logger.Error("db exec failed",
-			slog.String("op", op),
-			slog.Any("error", err),
-			slog.String("user_id", check.UserID.String()),
-		)
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertCheck(ctx, tx, check, events); err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err), slog.String("user_id", check.UserID.String()))
+		return e.WrapError(ctx, op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
 		return e.WrapError(ctx, op, err)
 	}
 
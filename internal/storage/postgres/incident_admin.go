@@ -2,11 +2,14 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"redCollar/internal/domain"
+	"redCollar/pkg/cursor"
 	"redCollar/pkg/e"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,12 +26,26 @@ func NewIncidentAdmin(pool *pgxpool.Pool, logger *slog.Logger) *IncidentAdmin {
 	return &IncidentAdmin{pool: pool, logger: logger}
 }
 
-func (p *IncidentAdmin) Create(ctx context.Context, incident *domain.Incident) error {
+func (p *IncidentAdmin) Create(ctx context.Context, incident *domain.Incident, event domain.IncidentEventFunc) error {
 	const op = "postgres.Incident.Create"
 
 	query := `
-		INSERT INTO incidents (id, geo_point, radius_km, status, created_at)
-		VALUES ($1, ST_SetSRID(ST_MakePoint($2, $3), 4326), $4, $5, $6)
+		INSERT INTO incidents (id, geo_point, radius_km, geo_area, status, created_at,
+		                       title, description, severity, category, starts_at, ends_at)
+		VALUES (
+			$1,
+			COALESCE(
+				ST_PointOnSurface(ST_SetSRID(ST_GeomFromGeoJSON($5::text), 4326)),
+				ST_SetSRID(ST_MakePoint($2, $3), 4326)
+			)::geography,
+			NULLIF($4::double precision, 0),
+			ST_Multi(ST_SetSRID(ST_GeomFromGeoJSON($5::text), 4326))::geography,
+			$6,
+			$7,
+			$8, $9, $10, $11,
+			$12, $13
+		)
+		RETURNING ST_Y(geo_point::geometry), ST_X(geo_point::geometry)
 	`
 
 	if incident.ID == uuid.Nil {
@@ -41,15 +58,40 @@ func (p *IncidentAdmin) Create(ctx context.Context, incident *domain.Incident) e
 		fmt.Println("WARNING: Incident Status is empty-----------")
 		incident.Status = domain.IncidentActive
 	}
+	if incident.Severity == "" {
+		incident.Severity = domain.SeverityMedium
+	}
+	if incident.Category == "" {
+		incident.Category = domain.CategoryOther
+	}
 
-	_, err := p.pool.Exec(ctx, query,
+	area, err := geometryParam(incident.Geometry)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, query,
 		incident.ID,
 		incident.Lng,
 		incident.Lat,
 		incident.RadiusKM,
+		area,
 		incident.Status,
 		incident.CreatedAt,
-	)
+		incident.Title,
+		incident.Description,
+		incident.Severity,
+		incident.Category,
+		incident.StartsAt,
+		incident.EndsAt,
+	).Scan(&incident.Lat, &incident.Lng)
 	if err != nil {
 		p.logger.Error("db exec failed",
 			slog.String("op", op),
@@ -58,12 +100,23 @@ func (p *IncidentAdmin) Create(ctx context.Context, incident *domain.Incident) e
 		return e.WrapError(ctx, op, err)
 	}
 
+	if err := writeIncidentEvents(ctx, tx, event, incident); err != nil {
+		p.logger.Error("outbox write failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+
 	return nil
 }
 
-func (p *IncidentAdmin) List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error) {
+func (p *IncidentAdmin) List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error) {
 	const op = "postgres.Incident.List"
 
+	page, limit := req.Page, req.Limit
 	if page < 1 {
 		page = 1
 	}
@@ -72,80 +125,164 @@ func (p *IncidentAdmin) List(ctx context.Context, page, limit int) ([]*domain.In
 	}
 	offset := (page - 1) * limit
 
-	const countQuery = `SELECT COUNT(*) FROM incidents`
+	where, args := incidentFilter(req)
+
+	countQuery := `SELECT COUNT(*) FROM incidents` + where
 
 	var total int64
-	if err := p.pool.QueryRow(ctx, countQuery).Scan(&total); err != nil {
+	if err := p.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
 		p.logger.Error("db count failed", slog.String("op", op), slog.Any("error", err))
-		return nil, 0, e.WrapError(ctx, op, err)
+		return domain.IncidentPage{}, e.WrapError(ctx, op, err)
 	}
 
-	const listQuery = `
-		SELECT id,
-			   ST_Y(geo_point::geometry) AS lat,
-			   ST_X(geo_point::geometry) AS lng,
-			   radius_km,
-			   status,
-			   created_at
-		FROM incidents
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+	var listQuery string
+	if req.CursorMode {
+		// Берём на одну строку больше, чтобы понять, есть ли следующая страница.
+		where, args = incidentKeyset(where, args, req.After, req.SortDir)
+		listQuery = fmt.Sprintf(`
+			SELECT `+incidentColumns+`
+			FROM incidents`+where+`
+			ORDER BY %s
+			LIMIT $%d
+		`, incidentOrder(domain.SortByCreatedAt, req.SortDir), len(args)+1)
+		args = append(args, limit+1)
+	} else {
+		listQuery = fmt.Sprintf(`
+			SELECT `+incidentColumns+`
+			FROM incidents`+where+`
+			ORDER BY %s
+			LIMIT $%d OFFSET $%d
+		`, incidentOrder(req.SortBy, req.SortDir), len(args)+1, len(args)+2)
+		args = append(args, limit, offset)
+	}
 
-	rows, err := p.pool.Query(ctx, listQuery, limit, offset)
+	rows, err := p.pool.Query(ctx, listQuery, args...)
 	if err != nil {
 		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
-		return nil, 0, e.WrapError(ctx, op, err)
+		return domain.IncidentPage{}, e.WrapError(ctx, op, err)
 	}
 	defer rows.Close()
 
 	var incidents []*domain.Incident
 	for rows.Next() {
-		var inc domain.Incident
-		if err := rows.Scan(
-			&inc.ID,
-			&inc.Lat,
-			&inc.Lng,
-			&inc.RadiusKM,
-			&inc.Status,
-			&inc.CreatedAt,
-		); err != nil {
+		inc, err := scanIncident(rows)
+		if err != nil {
 			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
-			return nil, 0, e.WrapError(ctx, op, err)
+			return domain.IncidentPage{}, e.WrapError(ctx, op, err)
 		}
-		incidents = append(incidents, &inc)
+		incidents = append(incidents, inc)
 	}
 	if err := rows.Err(); err != nil {
 		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
-		return nil, 0, e.WrapError(ctx, op, err)
+		return domain.IncidentPage{}, e.WrapError(ctx, op, err)
 	}
 
-	return incidents, total, nil
+	res := domain.IncidentPage{Items: incidents, Total: total}
+	if req.CursorMode && len(incidents) > limit {
+		res.Items = incidents[:limit]
+		last := res.Items[limit-1]
+		res.NextCursor = cursor.New(last.CreatedAt, last.ID).Encode()
+	}
+
+	return res, nil
+}
+
+// incidentKeyset дописывает к WHERE условие «после курсора» в направлении сортировки.
+func incidentKeyset(where string, args []any, after *cursor.Cursor, dir domain.SortDirection) (string, []any) {
+	if after == nil {
+		return where, args
+	}
+
+	op := "<"
+	if dir == domain.SortAsc {
+		op = ">"
+	}
+	args = append(args, after.At, after.ID)
+	cond := fmt.Sprintf("(created_at, id) %s ($%d, $%d)", op, len(args)-1, len(args))
+
+	if where == "" {
+		return "\n\t\tWHERE " + cond, args
+	}
+	return where + "\n\t\t  AND " + cond, args
+}
+
+// incidentFilter собирает WHERE для списка; одни и те же аргументы идут и в
+// COUNT, и в выборку, поэтому total учитывает фильтры.
+func incidentFilter(req domain.ListIncidentsRequest) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if req.Status != "" {
+		conds = append(conds, "status = "+arg(req.Status))
+	}
+	if !req.CreatedFrom.IsZero() {
+		conds = append(conds, "created_at >= "+arg(req.CreatedFrom))
+	}
+	if !req.CreatedTo.IsZero() {
+		conds = append(conds, "created_at < "+arg(req.CreatedTo))
+	}
+	if req.RadiusMin > 0 {
+		conds = append(conds, "radius_km >= "+arg(req.RadiusMin))
+	}
+	if req.RadiusMax > 0 {
+		conds = append(conds, "radius_km <= "+arg(req.RadiusMax))
+	}
+	if b := req.BBox; b != nil {
+		conds = append(conds, fmt.Sprintf(
+			"ST_Intersects(COALESCE(geo_area, geo_point), ST_MakeEnvelope(%s, %s, %s, %s, 4326)::geography)",
+			arg(b.MinLng), arg(b.MinLat), arg(b.MaxLng), arg(b.MaxLat),
+		))
+	}
+	if n := req.Near; n != nil {
+		conds = append(conds, fmt.Sprintf(
+			"ST_DWithin(COALESCE(geo_area, geo_point), ST_SetSRID(ST_MakePoint(%s, %s), 4326)::geography, %s * 1000)",
+			arg(n.Lng), arg(n.Lat), arg(n.WithinKM),
+		))
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "\n\t\tWHERE " + strings.Join(conds, "\n\t\t  AND "), args
+}
+
+// incidentOrder — только whitelisted колонки, пользовательский ввод в SQL не попадает.
+func incidentOrder(by domain.IncidentSortField, dir domain.SortDirection) string {
+	col := "created_at"
+	switch by {
+	case domain.SortByRadius:
+		col = "radius_km"
+	case domain.SortByStatus:
+		col = "status"
+	case domain.SortBySeverity:
+		col = "CASE severity WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 WHEN 'critical' THEN 4 END"
+	case domain.SortByTitle:
+		col = "title"
+	}
+
+	d := "DESC"
+	if dir == domain.SortAsc {
+		d = "ASC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, id %s", col, d, d)
 }
 
 func (p *IncidentAdmin) Get(ctx context.Context, id uuid.UUID) (*domain.Incident, error) {
 	const op = "postgres.Incident.Get"
 
 	const query = `
-		SELECT id,
-			   ST_Y(geo_point::geometry) AS lat,
-			   ST_X(geo_point::geometry) AS lng,
-			   radius_km,
-			   status,
-			   created_at
+		SELECT ` + incidentColumns + `
 		FROM incidents
 		WHERE id = $1
 	`
 
-	var inc domain.Incident
-	err := p.pool.QueryRow(ctx, query, id).Scan(
-		&inc.ID,
-		&inc.Lat,
-		&inc.Lng,
-		&inc.RadiusKM,
-		&inc.Status,
-		&inc.CreatedAt,
-	)
+	inc, err := scanIncident(p.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w", op, e.ErrNotFound)
@@ -154,54 +291,124 @@ func (p *IncidentAdmin) Get(ctx context.Context, id uuid.UUID) (*domain.Incident
 		return nil, e.WrapError(ctx, op, err)
 	}
 
-	return &inc, nil
+	return inc, nil
 }
 
-func (p *IncidentAdmin) Update(ctx context.Context, incident *domain.Incident) error {
+func (p *IncidentAdmin) Update(ctx context.Context, incident *domain.Incident, event domain.IncidentEventFunc) error {
 	const op = "postgres.Incident.Update"
 
 	const query = `
 		UPDATE incidents
-		SET geo_point = ST_SetSRID(ST_MakePoint($2, $3), 4326),
-			radius_km = $4,
-			status    = $5
+		SET geo_point   = COALESCE(
+				ST_PointOnSurface(ST_SetSRID(ST_GeomFromGeoJSON($5::text), 4326)),
+				ST_SetSRID(ST_MakePoint($2, $3), 4326)
+			)::geography,
+			radius_km   = NULLIF($4::double precision, 0),
+			geo_area    = ST_Multi(ST_SetSRID(ST_GeomFromGeoJSON($5::text), 4326))::geography,
+			status      = $6,
+			title       = $7,
+			description = $8,
+			severity    = $9,
+			category    = $10,
+			starts_at   = $11,
+			ends_at     = $12
 		WHERE id = $1
+		RETURNING ST_Y(geo_point::geometry), ST_X(geo_point::geometry)
 	`
 
-	cmd, err := p.pool.Exec(ctx, query,
+	area, err := geometryParam(incident.Geometry)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, query,
 		incident.ID,
 		incident.Lng,
 		incident.Lat,
 		incident.RadiusKM,
+		area,
 		incident.Status,
-	)
+		incident.Title,
+		incident.Description,
+		incident.Severity,
+		incident.Category,
+		incident.StartsAt,
+		incident.EndsAt,
+	).Scan(&incident.Lat, &incident.Lng)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+		}
 		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err), slog.String("id", incident.ID.String()))
 		return e.WrapError(ctx, op, err)
 	}
-	if cmd.RowsAffected() == 0 {
-		return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+
+	if err := writeIncidentEvents(ctx, tx, event, incident); err != nil {
+		p.logger.Error("outbox write failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+
+	// Снятый, истёкший или перенесённый инцидент больше никого не содержит.
+	if incident.Status != domain.IncidentActive {
+		if err := closeOccupancy(ctx, tx, time.Now().UTC(), incident); err != nil {
+			p.logger.Error("close occupancy failed", slog.String("op", op), slog.Any("error", err))
+			return e.WrapError(ctx, op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
 	}
 
 	return nil
 }
 
-func (p *IncidentAdmin) Delete(ctx context.Context, id uuid.UUID) error {
+func (p *IncidentAdmin) Delete(ctx context.Context, id uuid.UUID, event domain.IncidentEventFunc) error {
 	const op = "postgres.Incident.Delete"
 
 	const query = `
 		UPDATE incidents
 		SET status = 'inactive'
-		WHERE id = $1 AND status = 'active'
-	`
+		WHERE id = $1 AND status IN ('active', 'scheduled')
+		RETURNING ` + incidentColumns
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
 
-	cmd, err := p.pool.Exec(ctx, query, id)
+	inc, err := scanIncident(tx.QueryRow(ctx, query, id))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+		}
 		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err), slog.String("id", id.String()))
 		return e.WrapError(ctx, op, err)
 	}
-	if cmd.RowsAffected() == 0 {
-		return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+
+	if err := writeIncidentEvents(ctx, tx, event, inc); err != nil {
+		p.logger.Error("outbox write failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+
+	if err := closeOccupancy(ctx, tx, time.Now().UTC(), inc); err != nil {
+		p.logger.Error("close occupancy failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
 	}
 
 	return nil
@@ -211,14 +418,11 @@ func (p *IncidentAdmin) ListActive(ctx context.Context) ([]*domain.Incident, err
 	const op = "postgres.Incident.ListActive"
 
 	const query = `
-		SELECT id,
-			   ST_Y(geo_point::geometry) AS lat,
-			   ST_X(geo_point::geometry) AS lng,
-			   radius_km,
-			   status,
-			   created_at
+		SELECT ` + incidentColumns + `
 		FROM incidents
 		WHERE status = 'active'
+		  AND (starts_at IS NULL OR starts_at <= NOW())
+		  AND (ends_at IS NULL OR ends_at > NOW())
 	`
 
 	rows, err := p.pool.Query(ctx, query)
@@ -230,19 +434,12 @@ func (p *IncidentAdmin) ListActive(ctx context.Context) ([]*domain.Incident, err
 
 	var incidents []*domain.Incident
 	for rows.Next() {
-		var inc domain.Incident
-		if err := rows.Scan(
-			&inc.ID,
-			&inc.Lat,
-			&inc.Lng,
-			&inc.RadiusKM,
-			&inc.Status,
-			&inc.CreatedAt,
-		); err != nil {
+		inc, err := scanIncident(rows)
+		if err != nil {
 			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
 			return nil, e.WrapError(ctx, op, err)
 		}
-		incidents = append(incidents, &inc)
+		incidents = append(incidents, inc)
 	}
 	if err := rows.Err(); err != nil {
 		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
@@ -251,3 +448,201 @@ func (p *IncidentAdmin) ListActive(ctx context.Context) ([]*domain.Incident, err
 
 	return incidents, nil
 }
+
+func (p *IncidentAdmin) ActivateDue(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	const op = "postgres.Incident.ActivateDue"
+
+	const query = `
+		UPDATE incidents
+		SET status = 'active'
+		WHERE status = 'scheduled'
+		  AND starts_at <= $1
+		  AND (ends_at IS NULL OR ends_at > $1)
+		RETURNING id
+	`
+
+	return p.updateReturningIDs(ctx, op, query, now)
+}
+
+func (p *IncidentAdmin) ExpireDue(ctx context.Context, now time.Time, event domain.IncidentEventFunc) ([]uuid.UUID, error) {
+	const op = "postgres.Incident.ExpireDue"
+
+	const query = `
+		UPDATE incidents
+		SET status = 'expired'
+		WHERE status IN ('active', 'scheduled')
+		  AND ends_at <= $1
+		RETURNING ` + incidentColumns
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, now)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	var expired []*domain.Incident
+	for rows.Next() {
+		inc, err := scanIncident(rows)
+		if err != nil {
+			rows.Close()
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return nil, e.WrapError(ctx, op, err)
+		}
+		expired = append(expired, inc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	if err := writeIncidentEvents(ctx, tx, event, expired...); err != nil {
+		p.logger.Error("outbox write failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	if err := closeOccupancy(ctx, tx, now, expired...); err != nil {
+		p.logger.Error("close occupancy failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	ids := make([]uuid.UUID, len(expired))
+	for i, inc := range expired {
+		ids[i] = inc.ID
+	}
+	return ids, nil
+}
+
+// NextScheduleBoundary — ближайший после now момент, когда какой-то инцидент
+// должен стартовать или завершиться. nil, если таких нет.
+func (p *IncidentAdmin) NextScheduleBoundary(ctx context.Context, now time.Time) (*time.Time, error) {
+	const op = "postgres.Incident.NextScheduleBoundary"
+
+	const query = `
+		SELECT MIN(boundary) FROM (
+			SELECT starts_at AS boundary
+			FROM incidents
+			WHERE status = 'scheduled' AND starts_at > $1
+			UNION ALL
+			SELECT ends_at
+			FROM incidents
+			WHERE status IN ('active', 'scheduled') AND ends_at > $1
+		) b
+	`
+
+	var next *time.Time
+	if err := p.pool.QueryRow(ctx, query, now).Scan(&next); err != nil {
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	return next, nil
+}
+
+func (p *IncidentAdmin) updateReturningIDs(ctx context.Context, op, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return nil, e.WrapError(ctx, op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	return ids, nil
+}
+
+// incidentColumns — общий список колонок для scanIncident. Полигон из одной части
+// отдаётся как Polygon, чтобы тип совпадал с тем, что прислал клиент.
+const incidentColumns = `id,
+			   ST_Y(geo_point::geometry) AS lat,
+			   ST_X(geo_point::geometry) AS lng,
+			   COALESCE(radius_km, 0) AS radius_km,
+			   ST_AsGeoJSON(
+			       CASE WHEN ST_NumGeometries(geo_area::geometry) = 1
+			            THEN ST_GeometryN(geo_area::geometry, 1)
+			            ELSE geo_area::geometry
+			       END
+			   ) AS geo_area,
+			   status,
+			   created_at,
+			   title,
+			   description,
+			   severity,
+			   category,
+			   starts_at,
+			   ends_at`
+
+func scanIncident(row pgx.Row) (*domain.Incident, error) {
+	var (
+		inc  domain.Incident
+		area *string
+	)
+	if err := row.Scan(
+		&inc.ID,
+		&inc.Lat,
+		&inc.Lng,
+		&inc.RadiusKM,
+		&area,
+		&inc.Status,
+		&inc.CreatedAt,
+		&inc.Title,
+		&inc.Description,
+		&inc.Severity,
+		&inc.Category,
+		&inc.StartsAt,
+		&inc.EndsAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if area != nil {
+		var g domain.Geometry
+		if err := json.Unmarshal([]byte(*area), &g); err != nil {
+			return nil, err
+		}
+		inc.Geometry = &g
+	}
+
+	return &inc, nil
+}
+
+func geometryParam(g *domain.Geometry) (*string, error) {
+	if g == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	str := string(b)
+	return &str, nil
+}
@@ -17,6 +17,10 @@ type Postgres struct {
 	IncidentAdmin IncidentRepository
 	Stat          StatsRepository
 	Geo           GeoRepository
+	History       CheckHistoryRepository
+	Webhooks      WebhookSubscriptionRepository
+	DeliveryLog   WebhookDeliveryRepository
+	EventOutbox   OutboxRepository
 }
 
 func NewPostgres(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Postgres, error) {
@@ -67,6 +71,10 @@ func NewPostgres(ctx context.Context, cfg *config.Config, logger *slog.Logger) (
 		IncidentAdmin: NewIncidentAdmin(pool, logger),
 		Geo:           NewIncidentPublic(pool, logger),
 		Stat:          NewStats(pool, logger),
+		History:       NewLocationChecks(pool, logger),
+		Webhooks:      NewWebhookSubscriptions(pool, logger),
+		DeliveryLog:   NewWebhookDeliveries(pool, logger),
+		EventOutbox:   NewOutbox(pool, logger),
 	}
 
 	logger.Info("Postgres repositories created")
@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/cursor"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LocationChecks struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewLocationChecks(pool *pgxpool.Pool, logger *slog.Logger) *LocationChecks {
+	return &LocationChecks{pool: pool, logger: logger}
+}
+
+func (p *LocationChecks) List(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error) {
+	const op = "postgres.LocationCheck.List"
+
+	limit := req.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if req.UserID != uuid.Nil {
+		conds = append(conds, "user_id = "+arg(req.UserID))
+	}
+	if !req.From.IsZero() {
+		conds = append(conds, "checked_at >= "+arg(req.From))
+	}
+	if !req.To.IsZero() {
+		conds = append(conds, "checked_at < "+arg(req.To))
+	}
+	if req.IncidentID != uuid.Nil {
+		conds = append(conds, "incident_ids @> ARRAY["+arg(req.IncidentID)+"::uuid]")
+	}
+	if req.HadHits {
+		conds = append(conds, "cardinality(incident_ids) > 0")
+	}
+	if b := req.BBox; b != nil {
+		conds = append(conds, fmt.Sprintf("lng BETWEEN %s AND %s AND lat BETWEEN %s AND %s",
+			arg(b.MinLng), arg(b.MaxLng), arg(b.MinLat), arg(b.MaxLat)))
+	}
+	if c := req.After; c != nil {
+		conds = append(conds, fmt.Sprintf("(checked_at, id) < (%s, %s)", arg(c.At), arg(c.ID)))
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = "\nWHERE " + strings.Join(conds, "\n  AND ")
+	}
+
+	query := `
+SELECT id, user_id, lat, lng, incident_ids, checked_at
+FROM location_checks` + where + `
+ORDER BY checked_at DESC, id DESC
+LIMIT ` + arg(limit+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return domain.CheckPage{}, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	checks := make([]*domain.LocationCheck, 0, limit+1)
+	for rows.Next() {
+		var c domain.LocationCheck
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Lat, &c.Lng, &c.IncidentIDs, &c.CheckedAt); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return domain.CheckPage{}, e.WrapError(ctx, op, err)
+		}
+		checks = append(checks, &c)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return domain.CheckPage{}, e.WrapError(ctx, op, err)
+	}
+
+	res := domain.CheckPage{Items: checks}
+	if len(checks) > limit {
+		res.Items = checks[:limit]
+		last := res.Items[limit-1]
+		res.NextCursor = cursor.New(last.CheckedAt, last.ID).Encode()
+	}
+
+	return res, nil
+}
+
+// IncidentVisitors — пользователи, хотя бы одна проверка которых попала в инцидент.
+func (p *LocationChecks) IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error) {
+	const op = "postgres.LocationCheck.IncidentVisitors"
+
+	limit := req.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	args := []any{incidentID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := []string{"incident_ids @> ARRAY[$1::uuid]"}
+	if !req.From.IsZero() {
+		conds = append(conds, "checked_at >= "+arg(req.From))
+	}
+	if !req.To.IsZero() {
+		conds = append(conds, "checked_at < "+arg(req.To))
+	}
+
+	having := ""
+	if c := req.After; c != nil {
+		having = fmt.Sprintf("\nHAVING (MAX(checked_at), user_id) < (%s, %s)", arg(c.At), arg(c.ID))
+	}
+
+	query := `
+SELECT user_id, MIN(checked_at), MAX(checked_at), COUNT(*)
+FROM location_checks
+WHERE ` + strings.Join(conds, "\n  AND ") + `
+GROUP BY user_id` + having + `
+ORDER BY MAX(checked_at) DESC, user_id DESC
+LIMIT ` + arg(limit+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return domain.VisitorPage{}, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	visitors := make([]domain.IncidentVisitor, 0, limit+1)
+	for rows.Next() {
+		var v domain.IncidentVisitor
+		if err := rows.Scan(&v.UserID, &v.FirstSeenAt, &v.LastSeenAt, &v.Checks); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return domain.VisitorPage{}, e.WrapError(ctx, op, err)
+		}
+		visitors = append(visitors, v)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return domain.VisitorPage{}, e.WrapError(ctx, op, err)
+	}
+
+	res := domain.VisitorPage{Items: visitors}
+	if len(visitors) > limit {
+		res.Items = visitors[:limit]
+		last := res.Items[limit-1]
+		res.NextCursor = cursor.New(last.LastSeenAt, last.UserID).Encode()
+	}
+
+	return res, nil
+}
+
+// PurgeChecks удаляет до limit проверок старше cutoff, но не новее водяного знака
+// свёртки — иначе агрегаты потеряли бы данные. Если archive задан, удалённые
+// строки сначала уходят в архив; ошибка архивации откатывает удаление.
+func (p *LocationChecks) PurgeChecks(ctx context.Context, cutoff time.Time, limit int, archive func([]*domain.LocationCheck) error) (int64, error) {
+	const op = "postgres.LocationCheck.PurgeChecks"
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	const query = `
+DELETE FROM location_checks
+WHERE id IN (
+    SELECT id
+    FROM location_checks
+    WHERE checked_at < LEAST($1::timestamptz, COALESCE(
+        (SELECT rolled_up_to FROM location_checks_rollup_state WHERE name = $3),
+        '-infinity'::timestamptz))
+    ORDER BY checked_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, lat, lng, incident_ids, checked_at
+`
+
+	rows, err := tx.Query(ctx, query, cutoff, limit, rollupStateName)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+
+	var deleted []*domain.LocationCheck
+	for rows.Next() {
+		var c domain.LocationCheck
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Lat, &c.Lng, &c.IncidentIDs, &c.CheckedAt); err != nil {
+			rows.Close()
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return 0, e.WrapError(ctx, op, err)
+		}
+		deleted = append(deleted, &c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+
+	if len(deleted) == 0 {
+		return 0, nil
+	}
+
+	if archive != nil {
+		if err := archive(deleted); err != nil {
+			return 0, fmt.Errorf("%s: archive: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+
+	return int64(len(deleted)), nil
+}
@@ -0,0 +1,365 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/cursor"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// insertCheck пишет проверку, обновляет присутствие пользователя в инцидентах
+// и кладёт в outbox события, которые вернёт events. Всё в транзакции tx.
+func insertCheck(ctx context.Context, tx pgx.Tx, check *domain.LocationCheck, events domain.CheckEventFunc) error {
+	const query = `
+INSERT INTO location_checks (id, user_id, lat, lng, incident_ids, checked_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+	_, err := tx.Exec(ctx, query,
+		check.ID,
+		check.UserID,
+		check.Lat,
+		check.Lng,
+		check.IncidentIDs,
+		check.CheckedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert check: %w", err)
+	}
+
+	return applyCheck(ctx, tx, check, events)
+}
+
+// applyCheck — всё, что следует за записью уже вставленной проверки:
+// присутствие и события в outbox. Проверка старше уже применённой для
+// пользователя остаётся только в истории.
+func applyCheck(ctx context.Context, tx pgx.Tx, check *domain.LocationCheck, events domain.CheckEventFunc) error {
+	transitions, stale, err := trackOccupancy(ctx, tx, check)
+	if err != nil {
+		return fmt.Errorf("track occupancy: %w", err)
+	}
+
+	if stale || events == nil {
+		return nil
+	}
+	evs, err := events(transitions)
+	if err != nil {
+		return fmt.Errorf("build events: %w", err)
+	}
+	if err := writeOutbox(ctx, tx, evs...); err != nil {
+		return fmt.Errorf("write outbox: %w", err)
+	}
+	return nil
+}
+
+// trackOccupancy сверяет попадания проверки с текущим присутствием пользователя:
+// новые инциденты — вход, пропавшие — выход. Проверки одного пользователя
+// сериализуются advisory-локом, иначе параллельные запросы дали бы два входа.
+// stale — проверка старше последней применённой, присутствие не тронуто.
+func trackOccupancy(ctx context.Context, tx pgx.Tx, check *domain.LocationCheck) (_ []domain.OccupancyTransition, stale bool, _ error) {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1::text, 0))`, check.UserID); err != nil {
+		return nil, false, err
+	}
+
+	// Точки из будущего (допустимый разброс часов клиента) запоминаются как
+	// now(), иначе живая проверка сразу после пакета оказалась бы устаревшей.
+	const stateQuery = `
+INSERT INTO user_check_state AS s (user_id, applied_at)
+VALUES ($1, LEAST($2, now()))
+ON CONFLICT (user_id) DO UPDATE
+SET applied_at = GREATEST(s.applied_at, EXCLUDED.applied_at)
+WHERE s.applied_at <= $2
+RETURNING applied_at
+`
+	var appliedAt time.Time
+	err := tx.QueryRow(ctx, stateQuery, check.UserID, check.CheckedAt).Scan(&appliedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	hits := check.IncidentIDs
+	if hits == nil {
+		// NULL в ANY() не совпадает ни с чем, и выход бы не зафиксировался.
+		hits = []uuid.UUID{}
+	}
+
+	var transitions []domain.OccupancyTransition
+	add := func(incidentID uuid.UUID, kind domain.OccupancyKind) {
+		transitions = append(transitions, domain.OccupancyTransition{
+			ID:         uuid.New(),
+			UserID:     check.UserID,
+			IncidentID: incidentID,
+			Kind:       kind,
+			Reason:     domain.TransitionCheck,
+			CheckID:    &check.ID,
+			Lat:        &check.Lat,
+			Lng:        &check.Lng,
+			At:         check.CheckedAt,
+		})
+	}
+
+	const exitQuery = `
+DELETE FROM incident_occupancy
+WHERE user_id = $1 AND NOT (incident_id = ANY($2::uuid[]))
+RETURNING incident_id
+`
+	exited, err := collectIDs(tx.Query(ctx, exitQuery, check.UserID, hits))
+	if err != nil {
+		return nil, false, err
+	}
+	for _, id := range exited {
+		add(id, domain.OccupancyExited)
+	}
+
+	if len(hits) > 0 {
+		// xmax = 0 только у только что вставленных строк — это и есть вход.
+		const enterQuery = `
+INSERT INTO incident_occupancy (user_id, incident_id, entered_at, last_seen_at)
+SELECT $1, id, $3, $3 FROM unnest($2::uuid[]) AS id
+ON CONFLICT (user_id, incident_id) DO UPDATE
+SET last_seen_at = GREATEST(incident_occupancy.last_seen_at, EXCLUDED.last_seen_at)
+RETURNING incident_id, (xmax = 0)
+`
+		rows, err := tx.Query(ctx, enterQuery, check.UserID, hits, check.CheckedAt)
+		if err != nil {
+			return nil, false, err
+		}
+		for rows.Next() {
+			var (
+				id       uuid.UUID
+				inserted bool
+			)
+			if err := rows.Scan(&id, &inserted); err != nil {
+				rows.Close()
+				return nil, false, err
+			}
+			if inserted {
+				add(id, domain.OccupancyEntered)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, false, err
+		}
+	}
+
+	const historyQuery = `
+INSERT INTO incident_transitions (id, user_id, incident_id, kind, reason, check_id, lat, lng, at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+	for _, t := range transitions {
+		if _, err := tx.Exec(ctx, historyQuery, t.ID, t.UserID, t.IncidentID, string(t.Kind), string(t.Reason), t.CheckID, t.Lat, t.Lng, t.At); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return transitions, false, nil
+}
+
+// closeOccupancy выводит всех пользователей из закрытых инцидентов: без этого
+// они числились бы внутри, пока не пришлют следующую проверку. Выходы пишутся
+// в историю и в outbox той же транзакцией, что и закрытие.
+func closeOccupancy(ctx context.Context, tx pgx.Tx, at time.Time, incidents ...*domain.Incident) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*domain.Incident, len(incidents))
+	ids := make([]uuid.UUID, 0, len(incidents))
+	for _, inc := range incidents {
+		byID[inc.ID] = inc
+		ids = append(ids, inc.ID)
+	}
+
+	rows, err := tx.Query(ctx, `
+DELETE FROM incident_occupancy
+WHERE incident_id = ANY($1::uuid[])
+RETURNING user_id, incident_id
+`, ids)
+	if err != nil {
+		return err
+	}
+	var transitions []domain.OccupancyTransition
+	for rows.Next() {
+		t := domain.OccupancyTransition{ID: uuid.New(), Kind: domain.OccupancyExited, Reason: domain.TransitionIncidentClosed, At: at}
+		if err := rows.Scan(&t.UserID, &t.IncidentID); err != nil {
+			rows.Close()
+			return err
+		}
+		transitions = append(transitions, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	events := make([]domain.WebhookEvent, 0, len(transitions))
+	for _, t := range transitions {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO incident_transitions (id, user_id, incident_id, kind, reason, at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, t.ID, t.UserID, t.IncidentID, string(t.Kind), string(t.Reason), t.At); err != nil {
+			return err
+		}
+		ev, err := t.Event(byID[t.IncidentID].Summary())
+		if err != nil {
+			return err
+		}
+		events = append(events, ev)
+	}
+	return writeOutbox(ctx, tx, events...)
+}
+
+func collectIDs(rows pgx.Rows, err error) ([]uuid.UUID, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Occupants — пользователи, которые по последней проверке находятся внутри
+// инцидента. Не присылавшие проверок дольше OccupantStaleAfter не считаются.
+func (p *LocationChecks) Occupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error) {
+	const op = "postgres.LocationCheck.Occupants"
+
+	limit := req.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	args := []any{incidentID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := []string{"incident_id = $1", "last_seen_at >= " + arg(time.Now().Add(-domain.OccupantStaleAfter))}
+	if c := req.After; c != nil {
+		conds = append(conds, fmt.Sprintf("(entered_at, user_id) < (%s, %s)", arg(c.At), arg(c.ID)))
+	}
+
+	query := `
+SELECT user_id, entered_at, last_seen_at
+FROM incident_occupancy
+WHERE ` + strings.Join(conds, "\n  AND ") + `
+ORDER BY entered_at DESC, user_id DESC
+LIMIT ` + arg(limit+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return domain.OccupantPage{}, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	occupants := make([]domain.Occupant, 0, limit+1)
+	for rows.Next() {
+		var o domain.Occupant
+		if err := rows.Scan(&o.UserID, &o.EnteredAt, &o.LastSeenAt); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return domain.OccupantPage{}, e.WrapError(ctx, op, err)
+		}
+		occupants = append(occupants, o)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return domain.OccupantPage{}, e.WrapError(ctx, op, err)
+	}
+
+	res := domain.OccupantPage{Items: occupants}
+	if len(occupants) > limit {
+		res.Items = occupants[:limit]
+		last := res.Items[limit-1]
+		res.NextCursor = cursor.New(last.EnteredAt, last.UserID).Encode()
+	}
+
+	return res, nil
+}
+
+// Timeline — входы и выходы пользователя, свежие первыми.
+func (p *LocationChecks) Timeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error) {
+	const op = "postgres.LocationCheck.Timeline"
+
+	limit := req.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	args := []any{userID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := []string{"user_id = $1"}
+	if req.IncidentID != uuid.Nil {
+		conds = append(conds, "incident_id = "+arg(req.IncidentID))
+	}
+	if !req.From.IsZero() {
+		conds = append(conds, "at >= "+arg(req.From))
+	}
+	if !req.To.IsZero() {
+		conds = append(conds, "at < "+arg(req.To))
+	}
+	if c := req.After; c != nil {
+		conds = append(conds, fmt.Sprintf("(at, id) < (%s, %s)", arg(c.At), arg(c.ID)))
+	}
+
+	query := `
+SELECT id, user_id, incident_id, kind, reason, check_id, lat, lng, at
+FROM incident_transitions
+WHERE ` + strings.Join(conds, "\n  AND ") + `
+ORDER BY at DESC, id DESC
+LIMIT ` + arg(limit+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return domain.TimelinePage{}, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.OccupancyTransition, 0, limit+1)
+	for rows.Next() {
+		var t domain.OccupancyTransition
+		if err := rows.Scan(&t.ID, &t.UserID, &t.IncidentID, &t.Kind, &t.Reason, &t.CheckID, &t.Lat, &t.Lng, &t.At); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return domain.TimelinePage{}, e.WrapError(ctx, op, err)
+		}
+		events = append(events, t)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return domain.TimelinePage{}, e.WrapError(ctx, op, err)
+	}
+
+	res := domain.TimelinePage{Items: events}
+	if len(events) > limit {
+		res.Items = events[:limit]
+		last := res.Items[limit-1]
+		res.NextCursor = cursor.New(last.At, last.ID).Encode()
+	}
+
+	return res, nil
+}
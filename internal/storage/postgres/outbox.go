@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Outbox struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewOutbox(pool *pgxpool.Pool, logger *slog.Logger) *Outbox {
+	return &Outbox{pool: pool, logger: logger}
+}
+
+// maxOutboxAttempts — после стольких неудачных публикаций событие
+// помечается failed_at и больше не берётся.
+const maxOutboxAttempts = 100
+
+// Relay публикует до limit неотправленных событий в порядке записи и помечает
+// их отправленными. Строки берутся FOR UPDATE SKIP LOCKED, поэтому несколько
+// инстансов не публикуют одно событие одновременно. На первой ошибке publish
+// проход останавливается: у строки растёт attempts, а уже опубликованные
+// помечаются отправленными. Неразбираемые строки и исчерпавшие попытки
+// помечаются failed_at и пропускаются. Возвращает число опубликованных событий.
+func (p *Outbox) Relay(ctx context.Context, limit int, publish func(domain.WebhookEvent) error) (int, error) {
+	const op = "postgres.Outbox.Relay"
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	const query = `
+SELECT id, payload, attempts
+FROM outbox
+WHERE sent_at IS NULL AND failed_at IS NULL
+ORDER BY created_at, id
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+
+	var batch []outboxRow
+	for rows.Next() {
+		var r outboxRow
+		if err := rows.Scan(&r.id, &r.payload, &r.attempts); err != nil {
+			rows.Close()
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return 0, e.WrapError(ctx, op, err)
+		}
+		batch = append(batch, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	res := relayRows(batch, publish, maxOutboxAttempts)
+
+	for _, f := range res.failed {
+		p.logger.Error("outbox event dropped", slog.String("op", op), slog.String("id", f.id.String()), slog.String("error", f.err))
+		const fail = `UPDATE outbox SET attempts = attempts + 1, last_error = $2, failed_at = NOW() WHERE id = $1`
+		if _, err := tx.Exec(ctx, fail, f.id, f.err); err != nil {
+			p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+			return 0, e.WrapError(ctx, op, err)
+		}
+	}
+
+	var publishErr error
+	if f := res.retry; f != nil {
+		publishErr = fmt.Errorf("%s: publish %s: %s", op, f.id, f.err)
+		const retry = `UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`
+		if _, err := tx.Exec(ctx, retry, f.id, f.err); err != nil {
+			p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+			return 0, e.WrapError(ctx, op, err)
+		}
+	}
+
+	if len(res.sent) > 0 {
+		if _, err := tx.Exec(ctx, `UPDATE outbox SET sent_at = NOW() WHERE id = ANY($1)`, res.sent); err != nil {
+			p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+			return 0, e.WrapError(ctx, op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+
+	return len(res.sent), publishErr
+}
+
+type outboxRow struct {
+	id       uuid.UUID
+	payload  []byte
+	attempts int
+}
+
+type outboxFailure struct {
+	id  uuid.UUID
+	err string
+}
+
+// relayResult — что сделать со строками пачки: sent — опубликованы, failed —
+// больше не пробовать, retry — строка, на которой проход остановился.
+type relayResult struct {
+	sent   []uuid.UUID
+	failed []outboxFailure
+	retry  *outboxFailure
+}
+
+// relayRows публикует строки по порядку. Неразбираемая строка не
+// публикуется никогда и сразу уходит в failed. Ошибка publish обычно значит,
+// что недоступна очередь, поэтому проход на ней останавливается — если только
+// у строки не кончились попытки.
+func relayRows(rows []outboxRow, publish func(domain.WebhookEvent) error, maxAttempts int) relayResult {
+	var res relayResult
+	for _, r := range rows {
+		var ev domain.WebhookEvent
+		if err := json.Unmarshal(r.payload, &ev); err != nil {
+			res.failed = append(res.failed, outboxFailure{id: r.id, err: "decode: " + err.Error()})
+			continue
+		}
+		if err := publish(ev); err != nil {
+			f := outboxFailure{id: r.id, err: err.Error()}
+			if r.attempts+1 >= maxAttempts {
+				res.failed = append(res.failed, f)
+				continue
+			}
+			res.retry = &f
+			break
+		}
+		res.sent = append(res.sent, r.id)
+	}
+	return res
+}
+
+// PurgeSent удаляет события, отправленные раньше before.
+func (p *Outbox) PurgeSent(ctx context.Context, before time.Time) (int64, error) {
+	const op = "postgres.Outbox.PurgeSent"
+
+	cmd, err := p.pool.Exec(ctx, `DELETE FROM outbox WHERE sent_at < $1`, before)
+	if err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+
+	return cmd.RowsAffected(), nil
+}
+
+// writeOutbox кладёт события в outbox в транзакции основного изменения:
+// либо сохраняются и изменение, и события, либо ничего.
+func writeOutbox(ctx context.Context, tx pgx.Tx, events ...domain.WebhookEvent) error {
+	const query = `INSERT INTO outbox (id, event_type, payload) VALUES ($1, $2, $3)`
+
+	for _, ev := range events {
+		payload, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, query, ev.ID, string(ev.Type), payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeIncidentEvents строит событие по каждому изменённому инциденту;
+// nil event — изменение без события.
+func writeIncidentEvents(ctx context.Context, tx pgx.Tx, event domain.IncidentEventFunc, incidents ...*domain.Incident) error {
+	if event == nil {
+		return nil
+	}
+	events := make([]domain.WebhookEvent, 0, len(incidents))
+	for _, inc := range incidents {
+		ev, err := event(inc)
+		if err != nil {
+			return err
+		}
+		events = append(events, ev)
+	}
+	return writeOutbox(ctx, tx, events...)
+}
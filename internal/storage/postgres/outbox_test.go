@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"redCollar/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func outboxRowOf(t *testing.T, attempts int) outboxRow {
+	t.Helper()
+	ev := domain.WebhookEvent{ID: uuid.New(), Type: domain.WebhookEventIncidentCreated}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return outboxRow{id: ev.ID, payload: payload, attempts: attempts}
+}
+
+func TestRelayRows_MalformedPayloadSkipped(t *testing.T) {
+	first, second := outboxRowOf(t, 0), outboxRowOf(t, 0)
+	poison := outboxRow{id: uuid.New(), payload: []byte(`{"id": 42`)}
+
+	var published []uuid.UUID
+	res := relayRows([]outboxRow{poison, first, second}, func(ev domain.WebhookEvent) error {
+		published = append(published, ev.ID)
+		return nil
+	}, maxOutboxAttempts)
+
+	// Битая строка не держит очередь: остальные публикуются в том же проходе.
+	if !reflect.DeepEqual(res.sent, []uuid.UUID{first.id, second.id}) || !reflect.DeepEqual(published, res.sent) {
+		t.Fatalf("unexpected sent: %v (published %v)", res.sent, published)
+	}
+	if len(res.failed) != 1 || res.failed[0].id != poison.id || res.retry != nil {
+		t.Fatalf("expected poison row to fail, got %+v", res)
+	}
+}
+
+func TestRelayRows_PublishErrorStopsPass(t *testing.T) {
+	first, second := outboxRowOf(t, 0), outboxRowOf(t, 0)
+
+	res := relayRows([]outboxRow{first, second}, func(domain.WebhookEvent) error {
+		return errors.New("redis down")
+	}, maxOutboxAttempts)
+
+	if len(res.sent) != 0 || len(res.failed) != 0 || res.retry == nil || res.retry.id != first.id {
+		t.Fatalf("expected retry of the first row, got %+v", res)
+	}
+}
+
+func TestRelayRows_AttemptsExhausted(t *testing.T) {
+	stuck, next := outboxRowOf(t, 2), outboxRowOf(t, 0)
+
+	res := relayRows([]outboxRow{stuck, next}, func(ev domain.WebhookEvent) error {
+		if ev.ID == stuck.id {
+			return errors.New("too large")
+		}
+		return nil
+	}, 3)
+
+	if len(res.failed) != 1 || res.failed[0].id != stuck.id || res.failed[0].err != "too large" {
+		t.Fatalf("expected exhausted row to fail, got %+v", res)
+	}
+	if !reflect.DeepEqual(res.sent, []uuid.UUID{next.id}) || res.retry != nil {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
@@ -3,30 +3,73 @@ package postgres
 import (
 	"context"
 	"redCollar/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type IncidentRepository interface {
-	Create(ctx context.Context, incident *domain.Incident) error
-	List(ctx context.Context, page, limit int) ([]*domain.Incident, int64, error)
+	Create(ctx context.Context, incident *domain.Incident, event domain.IncidentEventFunc) error
+	List(ctx context.Context, req domain.ListIncidentsRequest) (domain.IncidentPage, error)
 	Get(ctx context.Context, id uuid.UUID) (*domain.Incident, error)
-	Update(ctx context.Context, incident *domain.Incident) error
-	Delete(ctx context.Context, id uuid.UUID) error // soft delete
+	Update(ctx context.Context, incident *domain.Incident, event domain.IncidentEventFunc) error
+	Delete(ctx context.Context, id uuid.UUID, event domain.IncidentEventFunc) error // soft delete
 	ListActive(ctx context.Context) ([]*domain.Incident, error)
+	ActivateDue(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+	ExpireDue(ctx context.Context, now time.Time, event domain.IncidentEventFunc) ([]uuid.UUID, error)
+	NextScheduleBoundary(ctx context.Context, now time.Time) (*time.Time, error)
 }
 
 type StatsRepository interface {
-	SaveCheck(ctx context.Context, check *domain.LocationCheck) error
+	SaveCheck(ctx context.Context, check *domain.LocationCheck, events domain.CheckEventFunc) error
+	SaveChecks(ctx context.Context, checks []*domain.LocationCheck, events domain.CheckBatchEventFunc) error
+	LastApplied(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]time.Time, error)
 	CountUniqueUsers(ctx context.Context, minutes int) (int64, error)
 	CountTotalChecks(ctx context.Context, minutes int) (int64, error)
+	CountRange(ctx context.Context, from, to time.Time) (domain.StatsPoint, error)
+	CheckSeries(ctx context.Context, from, to time.Time, bucket domain.StatsBucket) ([]domain.StatsPoint, error)
+	HitsByIncident(ctx context.Context, from, to time.Time, limit int) ([]domain.IncidentHits, error)
+	IncidentHitStats(ctx context.Context, id uuid.UUID, from, to time.Time) (domain.IncidentHitStats, error)
+	RollupChecks(ctx context.Context, upTo time.Time) (domain.RollupResult, error)
+}
+
+type CheckHistoryRepository interface {
+	List(ctx context.Context, req domain.ListChecksRequest) (domain.CheckPage, error)
+	IncidentVisitors(ctx context.Context, incidentID uuid.UUID, req domain.IncidentVisitorsRequest) (domain.VisitorPage, error)
+	PurgeChecks(ctx context.Context, cutoff time.Time, limit int, archive func([]*domain.LocationCheck) error) (int64, error)
+	Occupants(ctx context.Context, incidentID uuid.UUID, req domain.OccupantsRequest) (domain.OccupantPage, error)
+	Timeline(ctx context.Context, userID uuid.UUID, req domain.TimelineRequest) (domain.TimelinePage, error)
+}
+
+type WebhookSubscriptionRepository interface {
+	Create(ctx context.Context, sub *domain.WebhookSubscription) error
+	List(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	ListActive(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	Get(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error)
+	Update(ctx context.Context, sub *domain.WebhookSubscription) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookDeliveryRepository interface {
+	Save(ctx context.Context, a *domain.WebhookDeliveryAttempt) error
+	List(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error)
+	PurgeBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
+
+type OutboxRepository interface {
+	Relay(ctx context.Context, limit int, publish func(domain.WebhookEvent) error) (int, error)
+	PurgeSent(ctx context.Context, before time.Time) (int64, error)
 }
 
 type GeoRepository interface {
-	FindNearby(ctx context.Context, lat, lng, radiusKm float64) ([]uuid.UUID, error)
-	SaveCheck(ctx context.Context, check *domain.LocationCheck) error
+	FindNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]domain.NearbyIncident, error)
+	SaveCheck(ctx context.Context, check *domain.LocationCheck, events domain.CheckEventFunc) error
 }
 
-func (p *Postgres) AdminIncidents() IncidentRepository { return p.IncidentAdmin }
-func (p *Postgres) PublicIncidents() GeoRepository     { return p.Geo }
-func (p *Postgres) Stats() StatsRepository             { return p.Stat }
+func (p *Postgres) AdminIncidents() IncidentRepository           { return p.IncidentAdmin }
+func (p *Postgres) PublicIncidents() GeoRepository               { return p.Geo }
+func (p *Postgres) Stats() StatsRepository                       { return p.Stat }
+func (p *Postgres) Checks() CheckHistoryRepository               { return p.History }
+func (p *Postgres) Subscriptions() WebhookSubscriptionRepository { return p.Webhooks }
+func (p *Postgres) Deliveries() WebhookDeliveryRepository        { return p.DeliveryLog }
+func (p *Postgres) Outbox() OutboxRepository                     { return p.EventOutbox }
@@ -187,7 +187,8 @@ func TestIncidentAdmin_List_OnlyActive_WithPagination(t *testing.T) {
 		t.Fatalf("Create inactive: %v", err)
 	}
 
-	list1, total, err := repo.List(context.Background(), 1, 2)
+	page1, err := repo.List(context.Background(), domain.ListIncidentsRequest{Page: 1, Limit: 2})
+	list1, total := page1.Items, page1.Total
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -202,7 +203,8 @@ func TestIncidentAdmin_List_OnlyActive_WithPagination(t *testing.T) {
 		t.Fatalf("expected DESC order by created_at")
 	}
 
-	list2, total2, err := repo.List(context.Background(), 2, 2)
+	page2, err := repo.List(context.Background(), domain.ListIncidentsRequest{Page: 2, Limit: 2})
+	list2, total2 := page2.Items, page2.Total
 	if err != nil {
 		t.Fatalf("List page2: %v", err)
 	}
@@ -293,7 +295,8 @@ func TestIncidentAdmin_Delete_SoftDelete(t *testing.T) {
 		t.Fatalf("Delete: %v", err)
 	}
 
-	list, total, err := repo.List(context.Background(), 1, 10)
+	page, err := repo.List(context.Background(), domain.ListIncidentsRequest{Page: 1, Limit: 10})
+	list, total := page.Items, page.Total
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
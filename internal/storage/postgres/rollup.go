@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/e"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	rollupStateName = "location_checks"
+	// maxRollupStep — сколько сырых данных сворачиваем за одну транзакцию.
+	maxRollupStep = 6 * time.Hour
+)
+
+// RollupChecks сворачивает сырые проверки от водяного знака до upTo
+// (не больше maxRollupStep за вызов) в минутные/часовые агрегаты,
+// множество (минута, user_id) и минутные попадания по инцидентам.
+// Водяной знак двигается в той же транзакции.
+func (p *StatsRepo) RollupChecks(ctx context.Context, upTo time.Time) (domain.RollupResult, error) {
+	const op = "postgres.LocationCheck.RollupChecks"
+
+	upTo = upTo.UTC().Truncate(time.Minute)
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return domain.RollupResult{}, e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Первый запуск: начинаем с самой старой проверки.
+	const initQuery = `
+INSERT INTO location_checks_rollup_state (name, rolled_up_to)
+SELECT $1, COALESCE(date_bin('1 minute', MIN(checked_at), TIMESTAMPTZ '2000-01-01 00:00:00+00'), $2)
+FROM location_checks
+ON CONFLICT (name) DO NOTHING
+`
+	if _, err := tx.Exec(ctx, initQuery, rollupStateName, upTo); err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+		return domain.RollupResult{}, e.WrapError(ctx, op, err)
+	}
+
+	var from time.Time
+	err = tx.QueryRow(ctx,
+		`SELECT rolled_up_to FROM location_checks_rollup_state WHERE name = $1 FOR UPDATE`,
+		rollupStateName,
+	).Scan(&from)
+	if err != nil {
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err))
+		return domain.RollupResult{}, e.WrapError(ctx, op, err)
+	}
+
+	to := upTo
+	if to.Sub(from) > maxRollupStep {
+		to = from.Add(maxRollupStep)
+	}
+	res := domain.RollupResult{From: from, To: to}
+	if !to.After(from) {
+		return res, nil
+	}
+
+	for _, q := range []string{`
+INSERT INTO location_checks_rollup_minute AS r (bucket, total_checks, checks_with_hits)
+SELECT date_bin('1 minute', checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'),
+       COUNT(*),
+       COUNT(*) FILTER (WHERE cardinality(incident_ids) > 0)
+FROM location_checks
+WHERE checked_at >= $1 AND checked_at < $2
+GROUP BY 1
+ON CONFLICT (bucket) DO UPDATE
+SET total_checks     = r.total_checks + EXCLUDED.total_checks,
+    checks_with_hits = r.checks_with_hits + EXCLUDED.checks_with_hits
+`, `
+INSERT INTO location_checks_rollup_hour AS r (bucket, total_checks, checks_with_hits)
+SELECT date_bin('1 hour', checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'),
+       COUNT(*),
+       COUNT(*) FILTER (WHERE cardinality(incident_ids) > 0)
+FROM location_checks
+WHERE checked_at >= $1 AND checked_at < $2
+GROUP BY 1
+ON CONFLICT (bucket) DO UPDATE
+SET total_checks     = r.total_checks + EXCLUDED.total_checks,
+    checks_with_hits = r.checks_with_hits + EXCLUDED.checks_with_hits
+`, `
+INSERT INTO location_checks_rollup_users (bucket, user_id)
+SELECT DISTINCT date_bin('1 minute', checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'), user_id
+FROM location_checks
+WHERE checked_at >= $1 AND checked_at < $2
+ON CONFLICT DO NOTHING
+`, `
+INSERT INTO location_checks_rollup_incident_minute AS r (bucket, incident_id, hits)
+SELECT date_bin('1 minute', c.checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'), h.incident_id, COUNT(*)
+FROM location_checks c
+CROSS JOIN LATERAL unnest(c.incident_ids) AS h(incident_id)
+WHERE c.checked_at >= $1 AND c.checked_at < $2
+GROUP BY 1, 2
+ON CONFLICT (bucket, incident_id) DO UPDATE
+SET hits = r.hits + EXCLUDED.hits
+`, `
+INSERT INTO location_checks_rollup_incident_users (bucket, incident_id, user_id)
+SELECT DISTINCT date_bin('1 minute', c.checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'), h.incident_id, c.user_id
+FROM location_checks c
+CROSS JOIN LATERAL unnest(c.incident_ids) AS h(incident_id)
+WHERE c.checked_at >= $1 AND c.checked_at < $2
+ON CONFLICT DO NOTHING
+`} {
+		if _, err := tx.Exec(ctx, q, from, to); err != nil {
+			p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+			return domain.RollupResult{}, e.WrapError(ctx, op, err)
+		}
+	}
+
+	err = tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM location_checks WHERE checked_at >= $1 AND checked_at < $2`,
+		from, to,
+	).Scan(&res.Checks)
+	if err != nil {
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err))
+		return domain.RollupResult{}, e.WrapError(ctx, op, err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE location_checks_rollup_state SET rolled_up_to = $2 WHERE name = $1`,
+		rollupStateName, to,
+	); err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+		return domain.RollupResult{}, e.WrapError(ctx, op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
+		return domain.RollupResult{}, e.WrapError(ctx, op, err)
+	}
+
+	return res, nil
+}
+
+// rollupWatermark — граница свёрнутых данных; нулевое время, если свёртка ещё не запускалась.
+func (p *StatsRepo) rollupWatermark(ctx context.Context) (time.Time, error) {
+	var w time.Time
+	err := p.pool.QueryRow(ctx,
+		`SELECT rolled_up_to FROM location_checks_rollup_state WHERE name = $1`,
+		rollupStateName,
+	).Scan(&w)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	return w, err
+}
+
+type timeRange struct {
+	From, To time.Time
+}
+
+// rollupPlan — как разрезать [from, to) между агрегатами и сырыми данными.
+type rollupPlan struct {
+	Hours   []timeRange
+	Minutes []timeRange
+	Raw     []timeRange
+}
+
+// planRange: целые часы — из часовых агрегатов, целые минуты — из минутных,
+// неполные минуты по краям и всё, что новее водяного знака, — из сырых данных.
+func planRange(from, to, watermark time.Time) rollupPlan {
+	var plan rollupPlan
+
+	rolledTo := to
+	if watermark.Before(rolledTo) {
+		rolledTo = watermark
+	}
+	rolledTo = floorTo(rolledTo, time.Minute)
+	minFrom := ceilTo(from, time.Minute)
+
+	if !rolledTo.After(minFrom) {
+		plan.Raw = appendRange(nil, from, to)
+		return plan
+	}
+
+	hourFrom, hourTo := ceilTo(minFrom, time.Hour), floorTo(rolledTo, time.Hour)
+	if hourTo.After(hourFrom) {
+		plan.Hours = appendRange(nil, hourFrom, hourTo)
+		plan.Minutes = appendRange(plan.Minutes, minFrom, hourFrom)
+		plan.Minutes = appendRange(plan.Minutes, hourTo, rolledTo)
+	} else {
+		plan.Minutes = appendRange(nil, minFrom, rolledTo)
+	}
+
+	plan.Raw = appendRange(plan.Raw, from, minFrom)
+	plan.Raw = appendRange(plan.Raw, rolledTo, to)
+
+	return plan
+}
+
+// seriesPlan — planRange для ряда по корзинам bucket: часовой агрегат не
+// делится на минутные корзины, поэтому для них часы берутся из минутных.
+func seriesPlan(from, to, watermark time.Time, bucket domain.StatsBucket) rollupPlan {
+	plan := planRange(from, to, watermark)
+	if bucket == domain.BucketMinute {
+		plan.Minutes, plan.Hours = append(plan.Minutes, plan.Hours...), nil
+	}
+	return plan
+}
+
+func appendRange(rs []timeRange, from, to time.Time) []timeRange {
+	if !to.After(from) {
+		return rs
+	}
+	return append(rs, timeRange{From: from, To: to})
+}
+
+func floorTo(t time.Time, d time.Duration) time.Time {
+	return t.UTC().Truncate(d)
+}
+
+func ceilTo(t time.Time, d time.Duration) time.Time {
+	f := floorTo(t, d)
+	if f.Equal(t) {
+		return f
+	}
+	return f.Add(d)
+}
+
+// rangeCond — "(col >= $i AND col < $j) OR ..." для набора интервалов; FALSE, если пусто.
+func rangeCond(col string, ranges []timeRange, arg func(any) string) string {
+	if len(ranges) == 0 {
+		return "FALSE"
+	}
+	parts := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		parts = append(parts, fmt.Sprintf("(%s >= %s AND %s < %s)", col, arg(r.From), col, arg(r.To)))
+	}
+	return "(" + strings.Join(parts, " OR ") + ")"
+}
+
+// countPlanned считает итоги за [from, to) по плану: агрегаты + сырой хвост.
+func (p *StatsRepo) countPlanned(ctx context.Context, from, to time.Time) (domain.StatsPoint, error) {
+	watermark, err := p.rollupWatermark(ctx)
+	if err != nil {
+		return domain.StatsPoint{}, err
+	}
+	plan := planRange(from, to, watermark)
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	hours := rangeCond("bucket", plan.Hours, arg)
+	minutes := rangeCond("bucket", plan.Minutes, arg)
+	raw := rangeCond("checked_at", plan.Raw, arg)
+	usersRolled := rangeCond("bucket", append(append([]timeRange{}, plan.Hours...), plan.Minutes...), arg)
+	usersRaw := rangeCond("checked_at", plan.Raw, arg)
+
+	query := `
+SELECT COALESCE(SUM(t), 0)::bigint,
+       COALESCE(SUM(h), 0)::bigint,
+       (SELECT COUNT(DISTINCT user_id) FROM (
+            SELECT user_id FROM location_checks_rollup_users WHERE ` + usersRolled + `
+            UNION ALL
+            SELECT user_id FROM location_checks WHERE ` + usersRaw + `
+        ) u)
+FROM (
+    SELECT total_checks AS t, checks_with_hits AS h FROM location_checks_rollup_hour WHERE ` + hours + `
+    UNION ALL
+    SELECT total_checks, checks_with_hits FROM location_checks_rollup_minute WHERE ` + minutes + `
+    UNION ALL
+    SELECT COUNT(*), COUNT(*) FILTER (WHERE cardinality(incident_ids) > 0) FROM location_checks WHERE ` + raw + `
+) s
+`
+
+	pt := domain.StatsPoint{Bucket: from}
+	if err := p.pool.QueryRow(ctx, query, args...).Scan(&pt.TotalChecks, &pt.ChecksWithHits, &pt.UniqueUsers); err != nil {
+		return domain.StatsPoint{}, err
+	}
+	pt.ChecksWithoutHits = pt.TotalChecks - pt.ChecksWithHits
+
+	return pt, nil
+}
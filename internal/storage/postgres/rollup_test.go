@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"redCollar/internal/domain"
+)
+
+func TestPlanRange(t *testing.T) {
+	at := func(h, m, s int) time.Time { return time.Date(2025, 1, 1, h, m, s, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		from, to  time.Time
+		watermark time.Time
+		want      rollupPlan
+	}{
+		{
+			name:      "no rollups yet",
+			from:      at(10, 0, 0),
+			to:        at(12, 0, 0),
+			watermark: time.Time{},
+			want:      rollupPlan{Raw: []timeRange{{at(10, 0, 0), at(12, 0, 0)}}},
+		},
+		{
+			name:      "minutes only and raw tail",
+			from:      at(10, 5, 30),
+			to:        at(10, 50, 0),
+			watermark: at(10, 40, 0),
+			want: rollupPlan{
+				Minutes: []timeRange{{at(10, 6, 0), at(10, 40, 0)}},
+				Raw:     []timeRange{{at(10, 5, 30), at(10, 6, 0)}, {at(10, 40, 0), at(10, 50, 0)}},
+			},
+		},
+		{
+			name:      "hours in the middle",
+			from:      at(9, 30, 10),
+			to:        at(13, 0, 0),
+			watermark: at(12, 20, 0),
+			want: rollupPlan{
+				Hours:   []timeRange{{at(10, 0, 0), at(12, 0, 0)}},
+				Minutes: []timeRange{{at(9, 31, 0), at(10, 0, 0)}, {at(12, 0, 0), at(12, 20, 0)}},
+				Raw:     []timeRange{{at(9, 30, 10), at(9, 31, 0)}, {at(12, 20, 0), at(13, 0, 0)}},
+			},
+		},
+		{
+			name:      "fully rolled up aligned range",
+			from:      at(10, 0, 0),
+			to:        at(11, 0, 0),
+			watermark: at(12, 0, 0),
+			want:      rollupPlan{Hours: []timeRange{{at(10, 0, 0), at(11, 0, 0)}}},
+		},
+		{
+			name:      "watermark before range",
+			from:      at(10, 0, 0),
+			to:        at(10, 30, 0),
+			watermark: at(9, 0, 0),
+			want:      rollupPlan{Raw: []timeRange{{at(10, 0, 0), at(10, 30, 0)}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planRange(tt.from, tt.to, tt.watermark)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("planRange:\n got  %+v\n want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeriesPlan_MinuteBucketsSkipHours(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 1, 1, h, m, 0, 0, time.UTC) }
+
+	hourly := seriesPlan(at(9, 30), at(13, 0), at(12, 20), domain.BucketHour)
+	if len(hourly.Hours) != 1 {
+		t.Fatalf("hour buckets should use hour rollups: %+v", hourly)
+	}
+
+	got := seriesPlan(at(9, 30), at(13, 0), at(12, 20), domain.BucketMinute)
+	want := rollupPlan{
+		Minutes: []timeRange{{at(9, 30), at(10, 0)}, {at(12, 0), at(12, 20)}, {at(10, 0), at(12, 0)}},
+		Raw:     []timeRange{{at(12, 20), at(13, 0)}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("seriesPlan:\n got  %+v\n want %+v", got, want)
+	}
+}
+
+func TestCloseRange_OpenBounds(t *testing.T) {
+	at := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	from, to := closeRange(time.Time{}, time.Time{})
+	if !from.Equal(time.Unix(0, 0)) || !to.Equal(openRangeEnd) {
+		t.Fatalf("unexpected open range: %v - %v", from, to)
+	}
+	if from, to := closeRange(at, at.Add(time.Hour)); !from.Equal(at) || !to.Equal(at.Add(time.Hour)) {
+		t.Fatalf("closed range changed: %v - %v", from, to)
+	}
+
+	// Открытый конец не уводит агрегаты дальше водяного знака.
+	plan := planRange(at, openRangeEnd, at.Add(2*time.Hour))
+	if len(plan.Raw) != 1 || !plan.Raw[0].From.Equal(at.Add(2*time.Hour)) {
+		t.Fatalf("unexpected raw tail: %+v", plan.Raw)
+	}
+}
@@ -21,18 +21,13 @@ func NewStats(pool *pgxpool.Pool, logger *slog.Logger) *StatsRepo {
 	return &StatsRepo{pool: pool, logger: logger}
 }
 
-func (p *StatsRepo) SaveCheck(ctx context.Context, check *domain.LocationCheck) error {
+func (p *StatsRepo) SaveCheck(ctx context.Context, check *domain.LocationCheck, events domain.CheckEventFunc) error {
 	const op = "postgres.LocationCheck.Save"
 
 	if check == nil || check.UserID == uuid.Nil {
 		return fmt.Errorf("%s: %w", op, e.ErrInvalidInput)
 	}
 
-	const query = `
-INSERT INTO location_checks (id, user_id, lat, lng, incident_ids, checked_at)
-VALUES ($1, $2, $3, $4, $5, $6)
-`
-
 	if check.ID == uuid.Nil {
 		check.ID = uuid.New()
 	}
@@ -40,22 +35,100 @@ VALUES ($1, $2, $3, $4, $5, $6)
 		check.CheckedAt = time.Now().UTC()
 	}
 
-	_, err := p.pool.Exec(ctx, query,
-		check.ID,
-		check.UserID,
-		check.Lat,
-		check.Lng,
-		check.IncidentIDs,
-		check.CheckedAt,
-	)
+	tx, err := p.pool.Begin(ctx)
 	if err != nil {
-		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertCheck(ctx, tx, check, events); err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err), slog.String("user_id", check.UserID.String()))
+		return e.WrapError(ctx, op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
 		return e.WrapError(ctx, op, err)
 	}
 
 	return nil
 }
 
+// SaveChecks сохраняет пакет проверок одной транзакцией: либо весь, либо ничего.
+func (p *StatsRepo) SaveChecks(ctx context.Context, checks []*domain.LocationCheck, events domain.CheckBatchEventFunc) error {
+	const op = "postgres.LocationCheck.SaveBatch"
+
+	if len(checks) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	for _, check := range checks {
+		if check == nil || check.UserID == uuid.Nil {
+			return fmt.Errorf("%s: %w", op, e.ErrInvalidInput)
+		}
+		if check.ID == uuid.Nil {
+			check.ID = uuid.New()
+		}
+		if check.CheckedAt.IsZero() {
+			check.CheckedAt = now
+		}
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.logger.Error("db begin failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertChecks(ctx, tx, checks, events); err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err), slog.Int("checks", len(checks)))
+		return e.WrapError(ctx, op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("db commit failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+
+	return nil
+}
+
+// LastApplied — время последней проверки, применённой к присутствию, по
+// каждому из пользователей. Кого нет в ответе, у того проверок ещё не было.
+func (p *StatsRepo) LastApplied(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	const op = "postgres.LocationCheck.LastApplied"
+
+	rows, err := p.pool.Query(ctx,
+		`SELECT user_id, applied_at FROM user_check_state WHERE user_id = ANY($1::uuid[])`,
+		userIDs,
+	)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	res := make(map[uuid.UUID]time.Time, len(userIDs))
+	for rows.Next() {
+		var (
+			id uuid.UUID
+			at time.Time
+		)
+		if err := rows.Scan(&id, &at); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return nil, e.WrapError(ctx, op, err)
+		}
+		res[id] = at
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+	return res, nil
+}
+
 func (p *StatsRepo) CountUniqueUsers(ctx context.Context, minutes int) (int64, error) {
 	const op = "postgres.LocationCheck.CountUniqueUsers"
 
@@ -63,23 +136,18 @@ func (p *StatsRepo) CountUniqueUsers(ctx context.Context, minutes int) (int64, e
 		return 0, fmt.Errorf("%s: %w", op, e.ErrInvalidInput)
 	}
 
-	const query = `
-SELECT COUNT(DISTINCT user_id)
-FROM location_checks
-WHERE checked_at >= NOW() - ($1 * INTERVAL '1 minute')
-`
-
-	var cnt int64
-	if err := p.pool.QueryRow(ctx, query, minutes).Scan(&cnt); err != nil {
+	now := time.Now().UTC()
+	pt, err := p.countPlanned(ctx, now.Add(-time.Duration(minutes)*time.Minute), now)
+	if err != nil {
 		p.logger.Error("db queryrow scan failed",
 			slog.String("op", op),
 			slog.Any("error", err),
 			slog.Int("minutes", minutes),
 		)
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return 0, e.WrapError(ctx, op, err)
 	}
 
-	return cnt, nil
+	return pt.UniqueUsers, nil
 }
 
 func (p *StatsRepo) CountTotalChecks(ctx context.Context, minutes int) (int64, error) {
@@ -89,14 +157,9 @@ func (p *StatsRepo) CountTotalChecks(ctx context.Context, minutes int) (int64, e
 		return 0, fmt.Errorf("%s: %w", op, e.ErrInvalidInput)
 	}
 
-	const query = `
-SELECT COUNT(*)
-FROM location_checks
-WHERE checked_at >= NOW() - make_interval(mins => $1)
-
-`
-	var cnt int64
-	if err := p.pool.QueryRow(ctx, query, minutes).Scan(&cnt); err != nil {
+	now := time.Now().UTC()
+	pt, err := p.countPlanned(ctx, now.Add(-time.Duration(minutes)*time.Minute), now)
+	if err != nil {
 		p.logger.Error("db queryrow scan failed",
 			slog.String("op", op),
 			slog.Any("error", err),
@@ -104,5 +167,288 @@ WHERE checked_at >= NOW() - make_interval(mins => $1)
 		)
 		return 0, e.WrapError(ctx, op, err)
 	}
-	return cnt, nil
+	return pt.TotalChecks, nil
+}
+
+// CountRange — итоги за [from, to): всего проверок, с попаданиями и уникальные пользователи.
+// Старая часть диапазона берётся из агрегатов, свежий хвост — из сырых данных.
+func (p *StatsRepo) CountRange(ctx context.Context, from, to time.Time) (domain.StatsPoint, error) {
+	const op = "postgres.LocationCheck.CountRange"
+
+	pt, err := p.countPlanned(ctx, from, to)
+	if err != nil {
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err))
+		return domain.StatsPoint{}, e.WrapError(ctx, op, err)
+	}
+
+	return pt, nil
+}
+
+// CheckSeries — ряд по корзинам bucket; пустые корзины тоже возвращаются, с нулями.
+// Как и CountRange, старая часть диапазона берётся из агрегатов, свежий хвост —
+// из сырых данных, так что сумма ряда совпадает с итогами.
+func (p *StatsRepo) CheckSeries(ctx context.Context, from, to time.Time, bucket domain.StatsBucket) ([]domain.StatsPoint, error) {
+	const op = "postgres.LocationCheck.CheckSeries"
+
+	watermark, err := p.rollupWatermark(ctx)
+	if err != nil {
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+	plan := seriesPlan(from, to, watermark, bucket)
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	unit := arg(string(bucket)) + "::text"
+	fromArg, toArg := arg(from)+"::timestamptz", arg(to)+"::timestamptz"
+	hours := rangeCond("bucket", plan.Hours, arg)
+	minutes := rangeCond("bucket", plan.Minutes, arg)
+	raw := rangeCond("checked_at", plan.Raw, arg)
+	usersRolled := rangeCond("bucket", append(append([]timeRange{}, plan.Hours...), plan.Minutes...), arg)
+	usersRaw := rangeCond("checked_at", plan.Raw, arg)
+
+	query := `
+WITH s AS (
+    SELECT date_trunc(` + unit + `, bucket) AS b, total_checks AS t, checks_with_hits AS h
+    FROM location_checks_rollup_hour WHERE ` + hours + `
+    UNION ALL
+    SELECT date_trunc(` + unit + `, bucket), total_checks, checks_with_hits
+    FROM location_checks_rollup_minute WHERE ` + minutes + `
+    UNION ALL
+    SELECT date_trunc(` + unit + `, checked_at), COUNT(*), COUNT(*) FILTER (WHERE cardinality(incident_ids) > 0)
+    FROM location_checks WHERE ` + raw + `
+    GROUP BY 1
+), u AS (
+    SELECT date_trunc(` + unit + `, bucket) AS b, user_id
+    FROM location_checks_rollup_users WHERE ` + usersRolled + `
+    UNION
+    SELECT date_trunc(` + unit + `, checked_at), user_id
+    FROM location_checks WHERE ` + usersRaw + `
+)
+SELECT g.bucket, COALESCE(st.t, 0), COALESCE(st.h, 0), COALESCE(ut.n, 0)
+FROM generate_series(date_trunc(` + unit + `, ` + fromArg + `), ` + toArg + `, ('1 ' || ` + unit + `)::interval) AS g(bucket)
+LEFT JOIN (SELECT b, SUM(t)::bigint AS t, SUM(h)::bigint AS h FROM s GROUP BY b) st ON st.b = g.bucket
+LEFT JOIN (SELECT b, COUNT(*) AS n FROM u GROUP BY b) ut ON ut.b = g.bucket
+WHERE g.bucket < ` + toArg + `
+ORDER BY g.bucket
+`
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	var series []domain.StatsPoint
+	for rows.Next() {
+		var pt domain.StatsPoint
+		if err := rows.Scan(&pt.Bucket, &pt.TotalChecks, &pt.ChecksWithHits, &pt.UniqueUsers); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return nil, e.WrapError(ctx, op, err)
+		}
+		pt.ChecksWithoutHits = pt.TotalChecks - pt.ChecksWithHits
+		series = append(series, pt)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	return series, nil
+}
+
+// HitsByIncident — попадания по инцидентам за [from, to), самые частые первыми.
+// Свёрнутая часть диапазона — из минутных агрегатов по инцидентам, хвост — из
+// сырых данных.
+func (p *StatsRepo) HitsByIncident(ctx context.Context, from, to time.Time, limit int) ([]domain.IncidentHits, error) {
+	const op = "postgres.LocationCheck.HitsByIncident"
+
+	watermark, err := p.rollupWatermark(ctx)
+	if err != nil {
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+	plan := planRange(from, to, watermark)
+	rolled := append(append([]timeRange{}, plan.Hours...), plan.Minutes...)
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	hitsRolled := rangeCond("bucket", rolled, arg)
+	usersRolled := rangeCond("bucket", rolled, arg)
+	raw := rangeCond("c.checked_at", plan.Raw, arg)
+
+	query := `
+WITH raw AS (
+    SELECT h.incident_id, c.user_id
+    FROM location_checks c
+    CROSS JOIN LATERAL unnest(c.incident_ids) AS h(incident_id)
+    WHERE ` + raw + `
+), hits AS (
+    SELECT incident_id, SUM(hits)::bigint AS n FROM (
+        SELECT incident_id, hits FROM location_checks_rollup_incident_minute WHERE ` + hitsRolled + `
+        UNION ALL
+        SELECT incident_id, COUNT(*) FROM raw GROUP BY incident_id
+    ) x
+    GROUP BY incident_id
+), users AS (
+    SELECT incident_id, COUNT(DISTINCT user_id) AS n FROM (
+        SELECT incident_id, user_id FROM location_checks_rollup_incident_users WHERE ` + usersRolled + `
+        UNION ALL
+        SELECT incident_id, user_id FROM raw
+    ) x
+    GROUP BY incident_id
+)
+SELECT h.incident_id, COALESCE(i.title, ''), h.n, COALESCE(u.n, 0)
+FROM hits h
+LEFT JOIN users u ON u.incident_id = h.incident_id
+LEFT JOIN incidents i ON i.id = h.incident_id
+ORDER BY h.n DESC, h.incident_id
+LIMIT ` + arg(limit)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	var hits []domain.IncidentHits
+	for rows.Next() {
+		var h domain.IncidentHits
+		if err := rows.Scan(&h.IncidentID, &h.Title, &h.Hits, &h.UniqueUsers); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return nil, e.WrapError(ctx, op, err)
+		}
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	return hits, nil
+}
+
+// openRangeEnd заменяет открытую правую границу: проверки из будущего
+// дальше этого момента не сохраняются.
+var openRangeEnd = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// closeRange подставляет конкретные значения вместо открытых (нулевых) границ.
+func closeRange(from, to time.Time) (time.Time, time.Time) {
+	if from.IsZero() {
+		from = time.Unix(0, 0).UTC()
+	}
+	if to.IsZero() {
+		to = openRangeEnd
+	}
+	return from, to
+}
+
+// IncidentHitStats — попадания в инцидент за [from, to). Нули в from/to
+// означают открытую границу. Свёрнутая часть — из минутных агрегатов по
+// инцидентам, хвост — из сырых данных, так что статистика переживает
+// удаление старых проверок; первое и последнее попадание в свёрнутой части
+// известны с точностью до минуты.
+func (p *StatsRepo) IncidentHitStats(ctx context.Context, id uuid.UUID, from, to time.Time) (domain.IncidentHitStats, error) {
+	const op = "postgres.LocationCheck.IncidentHitStats"
+
+	var exists bool
+	if err := p.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM incidents WHERE id = $1)`, id).Scan(&exists); err != nil {
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err))
+		return domain.IncidentHitStats{}, e.WrapError(ctx, op, err)
+	}
+	if !exists {
+		return domain.IncidentHitStats{}, fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	}
+
+	watermark, err := p.rollupWatermark(ctx)
+	if err != nil {
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err))
+		return domain.IncidentHitStats{}, e.WrapError(ctx, op, err)
+	}
+	from, to = closeRange(from, to)
+	plan := planRange(from, to, watermark)
+	rolled := append(append([]timeRange{}, plan.Hours...), plan.Minutes...)
+
+	args := []any{id}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	hitsRolled := rangeCond("bucket", rolled, arg)
+	usersRolled := rangeCond("bucket", rolled, arg)
+	raw := rangeCond("checked_at", plan.Raw, arg)
+
+	cte := `
+WITH raw AS (
+    SELECT checked_at, user_id
+    FROM location_checks
+    WHERE incident_ids @> ARRAY[$1::uuid] AND ` + raw + `
+), hits AS (
+    SELECT date_trunc('hour', bucket) AS hour, SUM(hits)::bigint AS n, MIN(bucket) AS first, MAX(bucket) AS last
+    FROM location_checks_rollup_incident_minute
+    WHERE incident_id = $1 AND ` + hitsRolled + `
+    GROUP BY 1
+    UNION ALL
+    SELECT date_trunc('hour', checked_at), COUNT(*), MIN(checked_at), MAX(checked_at)
+    FROM raw
+    GROUP BY 1
+), users AS (
+    SELECT date_trunc('hour', bucket) AS hour, user_id
+    FROM location_checks_rollup_incident_users
+    WHERE incident_id = $1 AND ` + usersRolled + `
+    UNION
+    SELECT date_trunc('hour', checked_at), user_id FROM raw
+)`
+
+	totalsQuery := cte + `
+SELECT COALESCE(SUM(n), 0)::bigint, (SELECT COUNT(DISTINCT user_id) FROM users), MIN(first), MAX(last)
+FROM hits
+`
+
+	res := domain.IncidentHitStats{IncidentID: id, Hourly: []domain.HourlyHits{}}
+	if err := p.pool.QueryRow(ctx, totalsQuery, args...).
+		Scan(&res.TotalHits, &res.UniqueUsers, &res.FirstHitAt, &res.LastHitAt); err != nil {
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err))
+		return domain.IncidentHitStats{}, e.WrapError(ctx, op, err)
+	}
+
+	hourlyQuery := cte + `
+SELECT h.hour, SUM(h.n)::bigint, (SELECT COUNT(*) FROM users u WHERE u.hour = h.hour)
+FROM hits h
+GROUP BY h.hour
+ORDER BY h.hour
+`
+
+	rows, err := p.pool.Query(ctx, hourlyQuery, args...)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return domain.IncidentHitStats{}, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var h domain.HourlyHits
+		if err := rows.Scan(&h.Hour, &h.Hits, &h.UniqueUsers); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return domain.IncidentHitStats{}, e.WrapError(ctx, op, err)
+		}
+		res.Hourly = append(res.Hourly, h)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return domain.IncidentHitStats{}, e.WrapError(ctx, op, err)
+	}
+
+	return res, nil
 }
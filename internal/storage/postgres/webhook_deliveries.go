@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/cursor"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookDeliveries struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewWebhookDeliveries(pool *pgxpool.Pool, logger *slog.Logger) *WebhookDeliveries {
+	return &WebhookDeliveries{pool: pool, logger: logger}
+}
+
+func (p *WebhookDeliveries) Save(ctx context.Context, a *domain.WebhookDeliveryAttempt) error {
+	const op = "postgres.WebhookDelivery.Save"
+
+	const query = `
+		INSERT INTO webhook_deliveries (id, delivery_id, event_id, subscription_id, url, user_id, event, attempt, status,
+		                                payload_hash, status_code, latency_ms, error, response_snippet, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, 0), $12, $13, $14, $15)
+	`
+
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	if a.AttemptedAt.IsZero() {
+		a.AttemptedAt = time.Now().UTC()
+	}
+
+	_, err := p.pool.Exec(ctx, query,
+		a.ID,
+		a.DeliveryID,
+		a.EventID,
+		a.SubscriptionID,
+		a.URL,
+		a.UserID,
+		string(a.Event),
+		a.Attempt,
+		string(a.Status),
+		a.PayloadHash,
+		a.StatusCode,
+		a.LatencyMs,
+		a.Error,
+		a.ResponseSnippet,
+		a.AttemptedAt,
+	)
+	if err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+
+	return nil
+}
+
+func (p *WebhookDeliveries) List(ctx context.Context, req domain.ListWebhookDeliveriesRequest) (domain.WebhookDeliveryPage, error) {
+	const op = "postgres.WebhookDelivery.List"
+
+	limit := req.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if req.Status != "" {
+		conds = append(conds, "status = "+arg(string(req.Status)))
+	}
+	if req.UserID != uuid.Nil {
+		conds = append(conds, "user_id = "+arg(req.UserID.String()))
+	}
+	if req.SubscriptionID != nil {
+		conds = append(conds, "subscription_id = "+arg(*req.SubscriptionID))
+	}
+	if req.DeliveryID != uuid.Nil {
+		conds = append(conds, "delivery_id = "+arg(req.DeliveryID))
+	}
+	if !req.From.IsZero() {
+		conds = append(conds, "attempted_at >= "+arg(req.From))
+	}
+	if !req.To.IsZero() {
+		conds = append(conds, "attempted_at < "+arg(req.To))
+	}
+	if c := req.After; c != nil {
+		conds = append(conds, fmt.Sprintf("(attempted_at, id) < (%s, %s)", arg(c.At), arg(c.ID)))
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = "\nWHERE " + strings.Join(conds, "\n  AND ")
+	}
+
+	query := `
+SELECT id, delivery_id, event_id, subscription_id, url, user_id, event, attempt, status,
+       payload_hash, COALESCE(status_code, 0), latency_ms, error, response_snippet, attempted_at
+FROM webhook_deliveries` + where + `
+ORDER BY attempted_at DESC, id DESC
+LIMIT ` + arg(limit+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return domain.WebhookDeliveryPage{}, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*domain.WebhookDeliveryAttempt, 0, limit+1)
+	for rows.Next() {
+		var a domain.WebhookDeliveryAttempt
+		if err := rows.Scan(
+			&a.ID,
+			&a.DeliveryID,
+			&a.EventID,
+			&a.SubscriptionID,
+			&a.URL,
+			&a.UserID,
+			&a.Event,
+			&a.Attempt,
+			&a.Status,
+			&a.PayloadHash,
+			&a.StatusCode,
+			&a.LatencyMs,
+			&a.Error,
+			&a.ResponseSnippet,
+			&a.AttemptedAt,
+		); err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return domain.WebhookDeliveryPage{}, e.WrapError(ctx, op, err)
+		}
+		items = append(items, &a)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return domain.WebhookDeliveryPage{}, e.WrapError(ctx, op, err)
+	}
+
+	res := domain.WebhookDeliveryPage{Items: items}
+	if len(items) > limit {
+		res.Items = items[:limit]
+		last := res.Items[limit-1]
+		res.NextCursor = cursor.New(last.AttemptedAt, last.ID).Encode()
+	}
+
+	return res, nil
+}
+
+// PurgeBefore удаляет до limit записей журнала старше cutoff, самые старые первыми.
+func (p *WebhookDeliveries) PurgeBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	const op = "postgres.WebhookDelivery.PurgeBefore"
+
+	const query = `
+		DELETE FROM webhook_deliveries
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE attempted_at < $1
+			ORDER BY attempted_at
+			LIMIT $2
+		)
+	`
+
+	cmd, err := p.pool.Exec(ctx, query, cutoff, limit)
+	if err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+		return 0, e.WrapError(ctx, op, err)
+	}
+
+	return cmd.RowsAffected(), nil
+}
@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"redCollar/internal/domain"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookSubscriptions struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func NewWebhookSubscriptions(pool *pgxpool.Pool, logger *slog.Logger) *WebhookSubscriptions {
+	return &WebhookSubscriptions{pool: pool, logger: logger}
+}
+
+const subscriptionColumns = `id, url, secret, event_types, categories, severities,
+			   ST_AsGeoJSON(
+			       CASE WHEN ST_NumGeometries(area::geometry) = 1
+			            THEN ST_GeometryN(area::geometry, 1)
+			            ELSE area::geometry
+			       END
+			   ) AS area,
+			   active, created_at, updated_at,
+			   COALESCE(previous_secret, ''), previous_secret_expires_at`
+
+func (p *WebhookSubscriptions) Create(ctx context.Context, sub *domain.WebhookSubscription) error {
+	const op = "postgres.WebhookSubscription.Create"
+
+	const query = `
+		INSERT INTO webhook_subscriptions (id, url, secret, event_types, categories, severities, area, active, created_at, updated_at,
+		                                   previous_secret, previous_secret_expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6,
+		        ST_Multi(ST_SetSRID(ST_GeomFromGeoJSON($7::text), 4326))::geography,
+		        $8, $9, $9,
+		        NULLIF($10, ''), $11)
+	`
+
+	if sub.ID == uuid.Nil {
+		sub.ID = uuid.New()
+	}
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = time.Now().UTC()
+	}
+	sub.UpdatedAt = sub.CreatedAt
+
+	area, err := geometryParam(sub.Area)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = p.pool.Exec(ctx, query,
+		sub.ID,
+		sub.URL,
+		sub.Secret,
+		toStrings(sub.EventTypes),
+		toStrings(sub.Categories),
+		toStrings(sub.Severities),
+		area,
+		sub.Active,
+		sub.CreatedAt,
+		sub.PreviousSecret,
+		sub.PreviousSecretExpiresAt,
+	)
+	if err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err))
+		return e.WrapError(ctx, op, err)
+	}
+
+	return nil
+}
+
+func (p *WebhookSubscriptions) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	const op = "postgres.WebhookSubscription.List"
+	return p.list(ctx, op, `SELECT `+subscriptionColumns+` FROM webhook_subscriptions ORDER BY created_at, id`)
+}
+
+// ListActive — подписки, которым рассылаются события.
+func (p *WebhookSubscriptions) ListActive(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	const op = "postgres.WebhookSubscription.ListActive"
+	return p.list(ctx, op, `SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE active ORDER BY created_at, id`)
+}
+
+func (p *WebhookSubscriptions) list(ctx context.Context, op, query string) ([]*domain.WebhookSubscription, error) {
+	rows, err := p.pool.Query(ctx, query)
+	if err != nil {
+		p.logger.Error("db query failed", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	subs := make([]*domain.WebhookSubscription, 0)
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			p.logger.Error("row scan failed", slog.String("op", op), slog.Any("error", err))
+			return nil, e.WrapError(ctx, op, err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("rows err", slog.String("op", op), slog.Any("error", err))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	return subs, nil
+}
+
+func (p *WebhookSubscriptions) Get(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	const op = "postgres.WebhookSubscription.Get"
+
+	const query = `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1`
+
+	sub, err := scanSubscription(p.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, e.ErrNotFound)
+		}
+		p.logger.Error("db queryrow scan failed", slog.String("op", op), slog.Any("error", err), slog.String("id", id.String()))
+		return nil, e.WrapError(ctx, op, err)
+	}
+
+	return sub, nil
+}
+
+func (p *WebhookSubscriptions) Update(ctx context.Context, sub *domain.WebhookSubscription) error {
+	const op = "postgres.WebhookSubscription.Update"
+
+	const query = `
+		UPDATE webhook_subscriptions
+		SET url                        = $2,
+		    secret                     = $3,
+		    event_types                = $4,
+		    categories                 = $5,
+		    severities                 = $6,
+		    area                       = ST_Multi(ST_SetSRID(ST_GeomFromGeoJSON($7::text), 4326))::geography,
+		    active                     = $8,
+		    previous_secret            = NULLIF($9, ''),
+		    previous_secret_expires_at = $10,
+		    updated_at                 = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	area, err := geometryParam(sub.Area)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = p.pool.QueryRow(ctx, query,
+		sub.ID,
+		sub.URL,
+		sub.Secret,
+		toStrings(sub.EventTypes),
+		toStrings(sub.Categories),
+		toStrings(sub.Severities),
+		area,
+		sub.Active,
+		sub.PreviousSecret,
+		sub.PreviousSecretExpiresAt,
+	).Scan(&sub.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+		}
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err), slog.String("id", sub.ID.String()))
+		return e.WrapError(ctx, op, err)
+	}
+
+	return nil
+}
+
+func (p *WebhookSubscriptions) Delete(ctx context.Context, id uuid.UUID) error {
+	const op = "postgres.WebhookSubscription.Delete"
+
+	cmd, err := p.pool.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		p.logger.Error("db exec failed", slog.String("op", op), slog.Any("error", err), slog.String("id", id.String()))
+		return e.WrapError(ctx, op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	}
+
+	return nil
+}
+
+func scanSubscription(row pgx.Row) (*domain.WebhookSubscription, error) {
+	var (
+		sub                            domain.WebhookSubscription
+		events, categories, severities []string
+		area                           *string
+	)
+	if err := row.Scan(
+		&sub.ID,
+		&sub.URL,
+		&sub.Secret,
+		&events,
+		&categories,
+		&severities,
+		&area,
+		&sub.Active,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+		&sub.PreviousSecret,
+		&sub.PreviousSecretExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+
+	sub.EventTypes = fromStrings[domain.WebhookEventType](events)
+	sub.Categories = fromStrings[domain.IncidentCategory](categories)
+	sub.Severities = fromStrings[domain.IncidentSeverity](severities)
+
+	if area != nil {
+		var g domain.Geometry
+		if err := json.Unmarshal([]byte(*area), &g); err != nil {
+			return nil, err
+		}
+		sub.Area = &g
+	}
+
+	return &sub, nil
+}
+
+func toStrings[T ~string](src []T) []string {
+	out := make([]string, len(src))
+	for i, v := range src {
+		out[i] = string(v)
+	}
+	return out
+}
+
+func fromStrings[T ~string](src []string) []T {
+	out := make([]T, len(src))
+	for i, v := range src {
+		out[i] = T(v)
+	}
+	return out
+}
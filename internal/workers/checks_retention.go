@@ -0,0 +1,111 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"redCollar/internal/domain"
+)
+
+type CheckPurger interface {
+	PurgeChecks(ctx context.Context, cutoff time.Time, limit int, archive func([]*domain.LocationCheck) error) (int64, error)
+}
+
+type CheckArchiver interface {
+	WriteChecks(checks []*domain.LocationCheck) (string, error)
+}
+
+// ChecksRetention удаляет проверки старше retention пачками по batch строк,
+// при заданном archiver — с предварительной выгрузкой в файл.
+type ChecksRetention struct {
+	repo      CheckPurger
+	archiver  CheckArchiver
+	logger    *slog.Logger
+	retention time.Duration
+	interval  time.Duration
+	batch     int
+}
+
+func NewChecksRetention(repo CheckPurger, archiver CheckArchiver, logger *slog.Logger, retention, interval time.Duration, batch int) *ChecksRetention {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	if batch <= 0 {
+		batch = 5000
+	}
+	return &ChecksRetention{
+		repo:      repo,
+		archiver:  archiver,
+		logger:    logger,
+		retention: retention,
+		interval:  interval,
+		batch:     batch,
+	}
+}
+
+func (w *ChecksRetention) Run(ctx context.Context) {
+	if w.retention <= 0 {
+		w.logger.Info("checksRetention DISABLED")
+		return
+	}
+	w.logger.Info("checksRetention STARTED",
+		slog.Duration("retention", w.retention),
+		slog.Duration("interval", w.interval),
+		slog.Bool("archive", w.archiver != nil),
+	)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.Purge(ctx, time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("checksRetention STOPPED", slog.String("reason", ctx.Err().Error()))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge удаляет всё, что старше now-retention, и возвращает отчёт о проходе.
+func (w *ChecksRetention) Purge(ctx context.Context, now time.Time) domain.RetentionReport {
+	report := domain.RetentionReport{Cutoff: now.Add(-w.retention)}
+
+	var archive func([]*domain.LocationCheck) error
+	if w.archiver != nil {
+		archive = func(checks []*domain.LocationCheck) error {
+			path, err := w.archiver.WriteChecks(checks)
+			if err != nil {
+				return err
+			}
+			report.Files = append(report.Files, path)
+			return nil
+		}
+	}
+
+	for ctx.Err() == nil {
+		n, err := w.repo.PurgeChecks(ctx, report.Cutoff, w.batch, archive)
+		if err != nil {
+			w.logger.Error("PurgeChecks failed", slog.Any("error", err))
+			report.Err = err
+			break
+		}
+		report.Deleted += n
+		if n < int64(w.batch) {
+			break
+		}
+	}
+
+	if report.Deleted > 0 || report.Err != nil {
+		w.logger.Info("location checks purged",
+			slog.Time("cutoff", report.Cutoff),
+			slog.Int64("deleted", report.Deleted),
+			slog.Any("files", report.Files),
+		)
+	}
+
+	return report
+}
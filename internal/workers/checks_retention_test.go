@@ -0,0 +1,74 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"redCollar/internal/domain"
+)
+
+type fakePurger struct {
+	batches []int64
+	cutoffs []time.Time
+	err     error
+}
+
+func (f *fakePurger) PurgeChecks(_ context.Context, cutoff time.Time, _ int, archive func([]*domain.LocationCheck) error) (int64, error) {
+	f.cutoffs = append(f.cutoffs, cutoff)
+	if len(f.batches) == 0 {
+		return 0, f.err
+	}
+	n := f.batches[0]
+	f.batches = f.batches[1:]
+	if archive != nil && n > 0 {
+		if err := archive(make([]*domain.LocationCheck, n)); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+type fakeArchiver struct{ files int }
+
+func (a *fakeArchiver) WriteChecks(_ []*domain.LocationCheck) (string, error) {
+	a.files++
+	return "archive.ndjson.gz", nil
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+}
+
+func TestChecksRetention_Purge_LoopsUntilShortBatch(t *testing.T) {
+	repo := &fakePurger{batches: []int64{100, 100, 30}}
+	arch := &fakeArchiver{}
+	w := NewChecksRetention(repo, arch, testLogger(), 24*time.Hour, time.Hour, 100)
+
+	now := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	report := w.Purge(context.Background(), now)
+
+	if report.Deleted != 230 {
+		t.Fatalf("expected 230 deleted, got %d", report.Deleted)
+	}
+	if len(repo.cutoffs) != 3 || !repo.cutoffs[0].Equal(now.Add(-24*time.Hour)) {
+		t.Fatalf("unexpected calls: %v", repo.cutoffs)
+	}
+	if arch.files != 3 || len(report.Files) != 3 {
+		t.Fatalf("expected 3 archive files, got %d / %v", arch.files, report.Files)
+	}
+}
+
+func TestChecksRetention_Purge_StopsOnError(t *testing.T) {
+	repo := &fakePurger{err: errors.New("db down")}
+	w := NewChecksRetention(repo, nil, testLogger(), time.Hour, time.Hour, 10)
+
+	report := w.Purge(context.Background(), time.Now())
+
+	if report.Err == nil || report.Deleted != 0 || len(repo.cutoffs) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
@@ -0,0 +1,81 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type DeliveryLogPurger interface {
+	PurgeBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
+
+const (
+	// deliveryLogPurgeInterval — как часто чистится журнал доставок.
+	deliveryLogPurgeInterval = time.Hour
+	// deliveryLogPurgeBatch — сколько строк удаляется за один запрос.
+	deliveryLogPurgeBatch = 5000
+)
+
+// DeliveryLogRetention удаляет из журнала доставок вебхуков записи старше
+// retention пачками, чтобы не держать долгую блокировку.
+type DeliveryLogRetention struct {
+	repo      DeliveryLogPurger
+	logger    *slog.Logger
+	retention time.Duration
+	batch     int
+}
+
+func NewDeliveryLogRetention(repo DeliveryLogPurger, logger *slog.Logger, retention time.Duration) *DeliveryLogRetention {
+	return &DeliveryLogRetention{
+		repo:      repo,
+		logger:    logger,
+		retention: retention,
+		batch:     deliveryLogPurgeBatch,
+	}
+}
+
+func (w *DeliveryLogRetention) Run(ctx context.Context) {
+	if w.retention <= 0 {
+		w.logger.Info("deliveryLogRetention DISABLED")
+		return
+	}
+	w.logger.Info("deliveryLogRetention STARTED", slog.Duration("retention", w.retention))
+
+	ticker := time.NewTicker(deliveryLogPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		w.Purge(ctx, time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("deliveryLogRetention STOPPED", slog.String("reason", ctx.Err().Error()))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge удаляет записи старше now-retention и возвращает их число.
+func (w *DeliveryLogRetention) Purge(ctx context.Context, now time.Time) int64 {
+	cutoff := now.Add(-w.retention)
+
+	var total int64
+	for ctx.Err() == nil {
+		n, err := w.repo.PurgeBefore(ctx, cutoff, w.batch)
+		if err != nil {
+			w.logger.Error("delivery log PurgeBefore failed", slog.Any("error", err))
+			break
+		}
+		total += n
+		if n < int64(w.batch) {
+			break
+		}
+	}
+
+	if total > 0 {
+		w.logger.Info("webhook delivery log purged", slog.Time("cutoff", cutoff), slog.Int64("deleted", total))
+	}
+	return total
+}
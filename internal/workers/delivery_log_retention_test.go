@@ -0,0 +1,49 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeDeliveryPurger struct {
+	batches []int64
+	cutoffs []time.Time
+	err     error
+}
+
+func (f *fakeDeliveryPurger) PurgeBefore(_ context.Context, cutoff time.Time, _ int) (int64, error) {
+	f.cutoffs = append(f.cutoffs, cutoff)
+	if len(f.batches) == 0 {
+		return 0, f.err
+	}
+	n := f.batches[0]
+	f.batches = f.batches[1:]
+	return n, nil
+}
+
+func TestDeliveryLogRetention_Purge_LoopsUntilShortBatch(t *testing.T) {
+	repo := &fakeDeliveryPurger{batches: []int64{deliveryLogPurgeBatch, deliveryLogPurgeBatch, 7}}
+	w := NewDeliveryLogRetention(repo, testLogger(), 30*24*time.Hour)
+
+	now := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	if n := w.Purge(context.Background(), now); n != 2*deliveryLogPurgeBatch+7 {
+		t.Fatalf("unexpected deleted count: %d", n)
+	}
+	if len(repo.cutoffs) != 3 || !repo.cutoffs[0].Equal(now.Add(-30*24*time.Hour)) {
+		t.Fatalf("unexpected calls: %v", repo.cutoffs)
+	}
+}
+
+func TestDeliveryLogRetention_Purge_StopsOnError(t *testing.T) {
+	repo := &fakeDeliveryPurger{err: errors.New("db down")}
+	w := NewDeliveryLogRetention(repo, testLogger(), time.Hour)
+
+	if n := w.Purge(context.Background(), time.Now()); n != 0 {
+		t.Fatalf("expected 0 deleted, got %d", n)
+	}
+	if len(repo.cutoffs) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(repo.cutoffs))
+	}
+}
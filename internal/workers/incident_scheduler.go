@@ -0,0 +1,91 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"redCollar/internal/domain"
+)
+
+type IncidentScheduleService interface {
+	ApplySchedule(ctx context.Context, now time.Time) (domain.ScheduleResult, error)
+	NextScheduleBoundary(ctx context.Context, now time.Time) (*time.Time, error)
+}
+
+// IncidentScheduler переключает статусы инцидентов по starts_at/ends_at.
+// Спит до ближайшей границы расписания, но не дольше maxWait — заодно
+// держит кэш incidents:active свежим.
+type IncidentScheduler struct {
+	svc     IncidentScheduleService
+	logger  *slog.Logger
+	maxWait time.Duration
+}
+
+const minScheduleWait = time.Second
+
+func NewIncidentScheduler(svc IncidentScheduleService, logger *slog.Logger, maxWait time.Duration) *IncidentScheduler {
+	if maxWait <= 0 {
+		maxWait = time.Minute
+	}
+	return &IncidentScheduler{
+		svc:     svc,
+		logger:  logger,
+		maxWait: maxWait,
+	}
+}
+
+func (w *IncidentScheduler) Run(ctx context.Context) {
+	w.logger.Info("incidentScheduler STARTED", slog.Duration("max_wait", w.maxWait))
+
+	for {
+		wait := w.tick(ctx)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			w.logger.Info("incidentScheduler STOPPED", slog.String("reason", ctx.Err().Error()))
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *IncidentScheduler) tick(ctx context.Context) time.Duration {
+	now := time.Now().UTC()
+
+	res, err := w.svc.ApplySchedule(ctx, now)
+	if err != nil {
+		w.logger.Error("ApplySchedule failed", slog.Any("error", err))
+		return w.maxWait
+	}
+	if res.Changed() {
+		w.logger.Info("incident schedule applied",
+			slog.Int("activated", len(res.Activated)),
+			slog.Int("expired", len(res.Expired)),
+		)
+	}
+
+	next, err := w.svc.NextScheduleBoundary(ctx, now)
+	if err != nil {
+		w.logger.Error("NextScheduleBoundary failed", slog.Any("error", err))
+		return w.maxWait
+	}
+
+	return nextWait(now, next, w.maxWait)
+}
+
+func nextWait(now time.Time, next *time.Time, maxWait time.Duration) time.Duration {
+	if next == nil {
+		return maxWait
+	}
+	wait := next.Sub(now)
+	if wait < minScheduleWait {
+		return minScheduleWait
+	}
+	if wait > maxWait {
+		return maxWait
+	}
+	return wait
+}
@@ -0,0 +1,115 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"redCollar/internal/domain"
+)
+
+type OutboxStore interface {
+	Relay(ctx context.Context, limit int, publish func(domain.WebhookEvent) error) (int, error)
+	PurgeSent(ctx context.Context, before time.Time) (int64, error)
+}
+
+type WebhookEventPublisher interface {
+	Publish(ctx context.Context, ev domain.WebhookEvent) error
+}
+
+// outboxPurgeInterval — как часто удаляются отправленные события.
+const outboxPurgeInterval = time.Hour
+
+// OutboxRelay переносит события из outbox в очередь вебхуков. Событие
+// помечается отправленным только после успешной публикации, так что при
+// сбое оно уйдёт повторно с тем же id, но не потеряется.
+type OutboxRelay struct {
+	store     OutboxStore
+	queue     WebhookEventPublisher
+	logger    *slog.Logger
+	interval  time.Duration
+	batch     int
+	retention time.Duration
+	lastPurge time.Time
+}
+
+func NewOutboxRelay(store OutboxStore, queue WebhookEventPublisher, logger *slog.Logger, interval time.Duration, batch int, retention time.Duration) *OutboxRelay {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if batch <= 0 {
+		batch = 100
+	}
+	return &OutboxRelay{
+		store:     store,
+		queue:     queue,
+		logger:    logger,
+		interval:  interval,
+		batch:     batch,
+		retention: retention,
+	}
+}
+
+func (w *OutboxRelay) Run(ctx context.Context) {
+	w.logger.Info("outboxRelay STARTED",
+		slog.Duration("interval", w.interval),
+		slog.Duration("retention", w.retention),
+	)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("outboxRelay STOPPED", slog.String("reason", ctx.Err().Error()))
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			w.Relay(ctx)
+			w.Purge(ctx, now)
+		}
+	}
+}
+
+// Relay публикует накопившиеся события пачками, пока пачка не окажется неполной
+// или публикация не вернёт ошибку.
+func (w *OutboxRelay) Relay(ctx context.Context) int {
+	publish := func(ev domain.WebhookEvent) error {
+		return w.queue.Publish(ctx, ev)
+	}
+
+	total := 0
+	for ctx.Err() == nil {
+		n, err := w.store.Relay(ctx, w.batch, publish)
+		total += n
+		if err != nil {
+			w.logger.Error("outbox relay failed", slog.Any("error", err))
+			break
+		}
+		if n < w.batch {
+			break
+		}
+	}
+	if total > 0 {
+		w.logger.Debug("outbox events relayed", slog.Int("count", total))
+	}
+	return total
+}
+
+// Purge раз в outboxPurgeInterval удаляет события, отправленные раньше now-retention.
+func (w *OutboxRelay) Purge(ctx context.Context, now time.Time) {
+	if w.retention <= 0 || now.Sub(w.lastPurge) < outboxPurgeInterval {
+		return
+	}
+	w.lastPurge = now
+
+	n, err := w.store.PurgeSent(ctx, now.Add(-w.retention))
+	if err != nil {
+		w.logger.Error("outbox PurgeSent failed", slog.Any("error", err))
+		return
+	}
+	if n > 0 {
+		w.logger.Info("outbox purged", slog.Int64("deleted", n))
+	}
+}
@@ -0,0 +1,109 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"redCollar/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type fakeOutbox struct {
+	pending []domain.WebhookEvent
+	sent    []uuid.UUID
+	calls   int
+	purged  []time.Time
+}
+
+func (f *fakeOutbox) Relay(_ context.Context, limit int, publish func(domain.WebhookEvent) error) (int, error) {
+	f.calls++
+	n := 0
+	for len(f.pending) > 0 && n < limit {
+		ev := f.pending[0]
+		if err := publish(ev); err != nil {
+			return n, err
+		}
+		f.pending = f.pending[1:]
+		f.sent = append(f.sent, ev.ID)
+		n++
+	}
+	return n, nil
+}
+
+func (f *fakeOutbox) PurgeSent(_ context.Context, before time.Time) (int64, error) {
+	f.purged = append(f.purged, before)
+	return 0, nil
+}
+
+type fakePublisher struct {
+	published []domain.WebhookEvent
+	failAfter int
+}
+
+func (p *fakePublisher) Publish(_ context.Context, ev domain.WebhookEvent) error {
+	if p.failAfter >= 0 && len(p.published) >= p.failAfter {
+		return errors.New("redis down")
+	}
+	p.published = append(p.published, ev)
+	return nil
+}
+
+func outboxEvents(n int) []domain.WebhookEvent {
+	events := make([]domain.WebhookEvent, n)
+	for i := range events {
+		events[i] = domain.WebhookEvent{ID: uuid.New(), Type: domain.WebhookEventIncidentCreated}
+	}
+	return events
+}
+
+func TestOutboxRelay_Relay_LoopsUntilShortBatch(t *testing.T) {
+	store := &fakeOutbox{pending: outboxEvents(25)}
+	q := &fakePublisher{failAfter: -1}
+	w := NewOutboxRelay(store, q, testLogger(), time.Second, 10, 0)
+
+	if n := w.Relay(context.Background()); n != 25 {
+		t.Fatalf("expected 25 relayed, got %d", n)
+	}
+	if store.calls != 3 || len(q.published) != 25 || len(store.pending) != 0 {
+		t.Fatalf("unexpected state: calls=%d published=%d pending=%d", store.calls, len(q.published), len(store.pending))
+	}
+	for i, ev := range q.published {
+		if ev.ID != store.sent[i] {
+			t.Fatalf("event %d published out of order", i)
+		}
+	}
+}
+
+func TestOutboxRelay_Relay_StopsOnPublishError(t *testing.T) {
+	store := &fakeOutbox{pending: outboxEvents(25)}
+	q := &fakePublisher{failAfter: 12}
+	w := NewOutboxRelay(store, q, testLogger(), time.Second, 10, 0)
+
+	if n := w.Relay(context.Background()); n != 12 {
+		t.Fatalf("expected 12 relayed, got %d", n)
+	}
+	// Неопубликованное остаётся в outbox до следующего прохода.
+	if store.calls != 2 || len(store.pending) != 13 {
+		t.Fatalf("unexpected state: calls=%d pending=%d", store.calls, len(store.pending))
+	}
+}
+
+func TestOutboxRelay_Purge_Throttled(t *testing.T) {
+	store := &fakeOutbox{}
+	w := NewOutboxRelay(store, &fakePublisher{failAfter: -1}, testLogger(), time.Second, 10, 24*time.Hour)
+
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	w.Purge(context.Background(), now)
+	w.Purge(context.Background(), now.Add(time.Minute))
+	w.Purge(context.Background(), now.Add(outboxPurgeInterval))
+
+	if len(store.purged) != 2 {
+		t.Fatalf("expected 2 purges, got %d", len(store.purged))
+	}
+	if !store.purged[0].Equal(now.Add(-24 * time.Hour)) {
+		t.Fatalf("unexpected cutoff: %v", store.purged[0])
+	}
+}
@@ -0,0 +1,77 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"redCollar/internal/domain"
+)
+
+type CheckRollupRepository interface {
+	RollupChecks(ctx context.Context, upTo time.Time) (domain.RollupResult, error)
+}
+
+// StatsRollup периодически сворачивает location_checks в минутные/часовые
+// агрегаты. После простоя догоняет порциями, пока не дойдёт до now-lag.
+type StatsRollup struct {
+	repo     CheckRollupRepository
+	logger   *slog.Logger
+	interval time.Duration
+	lag      time.Duration
+}
+
+func NewStatsRollup(repo CheckRollupRepository, logger *slog.Logger, interval, lag time.Duration) *StatsRollup {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if lag < 0 {
+		lag = 0
+	}
+	return &StatsRollup{
+		repo:     repo,
+		logger:   logger,
+		interval: interval,
+		lag:      lag,
+	}
+}
+
+func (w *StatsRollup) Run(ctx context.Context) {
+	w.logger.Info("statsRollup STARTED", slog.Duration("interval", w.interval), slog.Duration("lag", w.lag))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("statsRollup STOPPED", slog.String("reason", ctx.Err().Error()))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *StatsRollup) tick(ctx context.Context) {
+	upTo := time.Now().UTC().Add(-w.lag).Truncate(time.Minute)
+
+	for ctx.Err() == nil {
+		res, err := w.repo.RollupChecks(ctx, upTo)
+		if err != nil {
+			w.logger.Error("RollupChecks failed", slog.Any("error", err))
+			return
+		}
+		if res.Checks > 0 {
+			w.logger.Info("location checks rolled up",
+				slog.Time("from", res.From),
+				slog.Time("to", res.To),
+				slog.Int64("checks", res.Checks),
+			)
+		}
+		if !res.To.Before(upTo) {
+			return
+		}
+	}
+}
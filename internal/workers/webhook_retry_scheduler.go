@@ -0,0 +1,94 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type DueWebhookPromoter interface {
+	PromoteDue(ctx context.Context, now time.Time, limit int) (int, error)
+	RecoverProcessing(ctx context.Context, now time.Time, visibility time.Duration) (int, error)
+}
+
+// WebhookRetryScheduler возвращает в очередь вебхуки, у которых наступило
+// время повторной попытки, и брошенные упавшими отправителями. Отправитель
+// при этом никогда не ждёт сам.
+type WebhookRetryScheduler struct {
+	queue      DueWebhookPromoter
+	logger     *slog.Logger
+	interval   time.Duration
+	batch      int
+	visibility time.Duration
+}
+
+func NewWebhookRetryScheduler(queue DueWebhookPromoter, logger *slog.Logger, interval time.Duration, batch int, visibility time.Duration) *WebhookRetryScheduler {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if batch <= 0 {
+		batch = 100
+	}
+	if visibility <= 0 {
+		visibility = 5 * time.Minute
+	}
+	return &WebhookRetryScheduler{
+		queue:      queue,
+		logger:     logger,
+		interval:   interval,
+		batch:      batch,
+		visibility: visibility,
+	}
+}
+
+func (w *WebhookRetryScheduler) Run(ctx context.Context) {
+	w.logger.Info("webhookRetryScheduler STARTED", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("webhookRetryScheduler STOPPED", slog.String("reason", ctx.Err().Error()))
+			return
+		case <-ticker.C:
+			now := time.Now()
+			w.Recover(ctx, now)
+			w.Promote(ctx, now)
+		}
+	}
+}
+
+// Promote переносит созревшие повторы пачками, пока пачка не окажется неполной.
+func (w *WebhookRetryScheduler) Promote(ctx context.Context, now time.Time) int {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := w.queue.PromoteDue(ctx, now, w.batch)
+		if err != nil {
+			w.logger.Error("PromoteDue failed", slog.Any("error", err))
+			break
+		}
+		total += n
+		if n < w.batch {
+			break
+		}
+	}
+	if total > 0 {
+		w.logger.Debug("webhook retries promoted", slog.Int("count", total))
+	}
+	return total
+}
+
+// Recover возвращает в очередь сообщения, взятые дольше visibility назад.
+func (w *WebhookRetryScheduler) Recover(ctx context.Context, now time.Time) int {
+	n, err := w.queue.RecoverProcessing(ctx, now, w.visibility)
+	if err != nil {
+		w.logger.Error("RecoverProcessing failed", slog.Any("error", err))
+		return 0
+	}
+	if n > 0 {
+		w.logger.Warn("requeued abandoned webhooks", slog.Int("count", n))
+	}
+	return n
+}
@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakePromoter struct {
+	batches []int
+	calls   int
+	err     error
+
+	recovered  int
+	visibility time.Duration
+}
+
+func (f *fakePromoter) RecoverProcessing(_ context.Context, _ time.Time, visibility time.Duration) (int, error) {
+	f.visibility = visibility
+	return f.recovered, f.err
+}
+
+func (f *fakePromoter) PromoteDue(_ context.Context, _ time.Time, _ int) (int, error) {
+	f.calls++
+	if len(f.batches) == 0 {
+		return 0, f.err
+	}
+	n := f.batches[0]
+	f.batches = f.batches[1:]
+	return n, nil
+}
+
+func TestWebhookRetryScheduler_Promote_LoopsUntilShortBatch(t *testing.T) {
+	q := &fakePromoter{batches: []int{10, 10, 3}}
+	w := NewWebhookRetryScheduler(q, testLogger(), time.Second, 10, time.Minute)
+
+	if n := w.Promote(context.Background(), time.Now()); n != 23 {
+		t.Fatalf("expected 23 promoted, got %d", n)
+	}
+	if q.calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", q.calls)
+	}
+}
+
+func TestWebhookRetryScheduler_Promote_StopsOnError(t *testing.T) {
+	q := &fakePromoter{err: errors.New("redis down")}
+	w := NewWebhookRetryScheduler(q, testLogger(), time.Second, 10, time.Minute)
+
+	if n := w.Promote(context.Background(), time.Now()); n != 0 {
+		t.Fatalf("expected 0 promoted, got %d", n)
+	}
+	if q.calls != 1 {
+		t.Fatalf("expected 1 call, got %d", q.calls)
+	}
+}
+
+func TestWebhookRetryScheduler_Recover_UsesVisibilityTimeout(t *testing.T) {
+	q := &fakePromoter{recovered: 2}
+	w := NewWebhookRetryScheduler(q, testLogger(), time.Second, 10, 3*time.Minute)
+
+	if n := w.Recover(context.Background(), time.Now()); n != 2 {
+		t.Fatalf("expected 2 recovered, got %d", n)
+	}
+	if q.visibility != 3*time.Minute {
+		t.Fatalf("expected visibility 3m, got %v", q.visibility)
+	}
+}
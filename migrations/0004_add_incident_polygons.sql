@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE incidents
+    ADD COLUMN IF NOT EXISTS geo_area GEOGRAPHY(MULTIPOLYGON, 4326);
+
+-- У полигональных инцидентов радиуса нет, geo_point хранит точку внутри полигона
+ALTER TABLE incidents
+    ALTER COLUMN radius_km DROP NOT NULL;
+
+ALTER TABLE incidents
+    ADD CONSTRAINT incidents_shape_chk CHECK ((geo_area IS NULL) <> (radius_km IS NULL));
+
+CREATE INDEX IF NOT EXISTS incidents_geo_area_idx
+    ON incidents
+        USING GIST (geo_area);
+
+-- +goose Down
+DROP INDEX IF EXISTS incidents_geo_area_idx;
+DELETE FROM incidents WHERE geo_area IS NOT NULL;
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_shape_chk;
+ALTER TABLE incidents ALTER COLUMN radius_km SET NOT NULL;
+ALTER TABLE incidents DROP COLUMN IF EXISTS geo_area;
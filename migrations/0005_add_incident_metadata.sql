@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE incidents
+    ADD COLUMN IF NOT EXISTS title       VARCHAR(200) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS description TEXT         NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS severity    VARCHAR(20)  NOT NULL DEFAULT 'medium'
+        CHECK (severity IN ('low', 'medium', 'high', 'critical')),
+    ADD COLUMN IF NOT EXISTS category    VARCHAR(32)  NOT NULL DEFAULT 'other'
+        CHECK (category IN ('fire', 'flood', 'gas_leak', 'road_closure', 'industrial', 'public_safety', 'weather', 'other'));
+
+CREATE INDEX IF NOT EXISTS incidents_severity_category_idx
+    ON incidents (severity, category);
+
+-- +goose Down
+DROP INDEX IF EXISTS incidents_severity_category_idx;
+ALTER TABLE incidents
+    DROP COLUMN IF EXISTS category,
+    DROP COLUMN IF EXISTS severity,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS title;
@@ -0,0 +1,32 @@
+-- +goose Up
+ALTER TABLE incidents
+    ADD COLUMN IF NOT EXISTS starts_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS ends_at   TIMESTAMPTZ;
+
+ALTER TABLE incidents
+    ADD CONSTRAINT incidents_schedule_chk CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at);
+
+-- scheduled — ждёт starts_at, expired — завершён по ends_at
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_status_check;
+ALTER TABLE incidents
+    ADD CONSTRAINT incidents_status_check CHECK (status IN ('active', 'inactive', 'scheduled', 'expired'));
+
+CREATE INDEX IF NOT EXISTS incidents_starts_at_idx
+    ON incidents (starts_at)
+    WHERE status = 'scheduled';
+
+CREATE INDEX IF NOT EXISTS incidents_ends_at_idx
+    ON incidents (ends_at)
+    WHERE status IN ('active', 'scheduled') AND ends_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS incidents_ends_at_idx;
+DROP INDEX IF EXISTS incidents_starts_at_idx;
+UPDATE incidents SET status = 'inactive' WHERE status IN ('scheduled', 'expired');
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_status_check;
+ALTER TABLE incidents
+    ADD CONSTRAINT incidents_status_check CHECK (status IN ('active', 'inactive'));
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_schedule_chk;
+ALTER TABLE incidents
+    DROP COLUMN IF EXISTS ends_at,
+    DROP COLUMN IF EXISTS starts_at;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Поиск проверок по инциденту: incident_ids @> ARRAY[id]
+CREATE INDEX IF NOT EXISTS location_checks_incident_ids_gin_idx
+    ON location_checks
+        USING GIN (incident_ids);
+
+-- +goose Down
+DROP INDEX IF EXISTS location_checks_incident_ids_gin_idx;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Предагрегаты location_checks. Корзины считаются через date_bin от UTC-эпохи,
+-- чтобы границы не зависели от часового пояса сессии.
+CREATE TABLE IF NOT EXISTS location_checks_rollup_minute (
+    bucket           TIMESTAMPTZ PRIMARY KEY,
+    total_checks     BIGINT NOT NULL DEFAULT 0,
+    checks_with_hits BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS location_checks_rollup_hour (
+    bucket           TIMESTAMPTZ PRIMARY KEY,
+    total_checks     BIGINT NOT NULL DEFAULT 0,
+    checks_with_hits BIGINT NOT NULL DEFAULT 0
+);
+
+-- Точный «скетч» уникальных пользователей: множество (минута, user_id).
+CREATE TABLE IF NOT EXISTS location_checks_rollup_users (
+    bucket  TIMESTAMPTZ NOT NULL,
+    user_id UUID        NOT NULL,
+    PRIMARY KEY (bucket, user_id)
+);
+
+-- До какого момента (не включительно) сырые проверки уже свёрнуты.
+CREATE TABLE IF NOT EXISTS location_checks_rollup_state (
+    name         TEXT PRIMARY KEY,
+    rolled_up_to TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS location_checks_rollup_state;
+DROP TABLE IF EXISTS location_checks_rollup_users;
+DROP TABLE IF EXISTS location_checks_rollup_hour;
+DROP TABLE IF EXISTS location_checks_rollup_minute;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    url         TEXT        NOT NULL,
+    secret      TEXT        NOT NULL,
+    -- пустой массив — без фильтра
+    event_types TEXT[]      NOT NULL DEFAULT '{}',
+    categories  TEXT[]      NOT NULL DEFAULT '{}',
+    severities  TEXT[]      NOT NULL DEFAULT '{}',
+    area        GEOGRAPHY(MULTIPOLYGON, 4326),
+    active      BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_active_idx
+    ON webhook_subscriptions (created_at)
+    WHERE active;
+
+-- +goose Down
+DROP INDEX IF EXISTS webhook_subscriptions_active_idx;
+DROP TABLE IF EXISTS webhook_subscriptions;
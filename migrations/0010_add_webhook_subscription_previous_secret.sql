@@ -0,0 +1,10 @@
+-- +goose Up
+-- Ротация: пока previous_secret не истёк, доставки подписываются обоими секретами.
+ALTER TABLE webhook_subscriptions
+    ADD COLUMN IF NOT EXISTS previous_secret            TEXT,
+    ADD COLUMN IF NOT EXISTS previous_secret_expires_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE webhook_subscriptions
+    DROP COLUMN IF EXISTS previous_secret_expires_at,
+    DROP COLUMN IF EXISTS previous_secret;
@@ -0,0 +1,40 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- X-Delivery-ID; общий для всех попыток одной доставки
+    delivery_id      UUID        NOT NULL,
+    event_id         UUID        NOT NULL,
+    -- без FK: журнал переживает удаление подписки; nil-uuid — WEBHOOK_URL
+    subscription_id  UUID        NOT NULL,
+    url              TEXT        NOT NULL,
+    user_id          TEXT        NOT NULL DEFAULT '',
+    event            TEXT        NOT NULL,
+    attempt          INTEGER     NOT NULL,
+    status           TEXT        NOT NULL CHECK (status IN ('succeeded', 'failed', 'dead')),
+    payload_hash     TEXT        NOT NULL,
+    status_code      INTEGER,
+    latency_ms       BIGINT      NOT NULL,
+    error            TEXT        NOT NULL DEFAULT '',
+    response_snippet TEXT        NOT NULL DEFAULT '',
+    attempted_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_time_idx
+    ON webhook_deliveries (attempted_at);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_user_time_idx
+    ON webhook_deliveries (user_id, attempted_at);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_delivery_idx
+    ON webhook_deliveries (delivery_id);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_not_succeeded_idx
+    ON webhook_deliveries (attempted_at)
+    WHERE status <> 'succeeded';
+
+-- +goose Down
+DROP INDEX IF EXISTS webhook_deliveries_not_succeeded_idx;
+DROP INDEX IF EXISTS webhook_deliveries_delivery_idx;
+DROP INDEX IF EXISTS webhook_deliveries_user_time_idx;
+DROP INDEX IF EXISTS webhook_deliveries_time_idx;
+DROP TABLE IF EXISTS webhook_deliveries;
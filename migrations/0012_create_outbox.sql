@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS outbox (
+    -- id события (WebhookEvent.ID): повторная публикация после сбоя
+    -- отдаёт тот же конверт, получатель может дедуплицировать
+    id          UUID PRIMARY KEY,
+    event_type  TEXT        NOT NULL,
+    payload     JSONB       NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at     TIMESTAMPTZ,
+    attempts    INTEGER     NOT NULL DEFAULT 0,
+    last_error  TEXT        NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx
+    ON outbox (created_at)
+    WHERE sent_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS outbox_sent_idx
+    ON outbox (sent_at)
+    WHERE sent_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS outbox_sent_idx;
+DROP INDEX IF EXISTS outbox_pending_idx;
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- Текущее присутствие пользователей внутри инцидентов: строка живёт,
+-- пока проверки пользователя попадают в инцидент.
+CREATE TABLE IF NOT EXISTS incident_occupancy (
+    user_id      UUID        NOT NULL,
+    incident_id  UUID        NOT NULL,
+    entered_at   TIMESTAMPTZ NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, incident_id)
+);
+
+CREATE INDEX IF NOT EXISTS incident_occupancy_incident_idx
+    ON incident_occupancy (incident_id, entered_at DESC, user_id DESC);
+
+-- История входов и выходов. Без FK: проверки удаляются по retention,
+-- а история должна остаться.
+CREATE TABLE IF NOT EXISTS incident_transitions (
+    id          UUID PRIMARY KEY,
+    user_id     UUID             NOT NULL,
+    incident_id UUID             NOT NULL,
+    kind        TEXT             NOT NULL CHECK (kind IN ('entered', 'exited')),
+    check_id    UUID             NOT NULL,
+    lat         DOUBLE PRECISION NOT NULL,
+    lng         DOUBLE PRECISION NOT NULL,
+    at          TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS incident_transitions_user_time_idx
+    ON incident_transitions (user_id, at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS incident_transitions_user_time_idx;
+DROP TABLE IF EXISTS incident_transitions;
+DROP INDEX IF EXISTS incident_occupancy_incident_idx;
+DROP TABLE IF EXISTS incident_occupancy;
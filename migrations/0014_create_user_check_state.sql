@@ -0,0 +1,11 @@
+-- +goose Up
+-- Время последней проверки пользователя, применённой к присутствию. Более
+-- старые точки (офлайн-пакеты) только сохраняются: переходов и событий по
+-- ним нет, иначе они откатили бы присутствие к устаревшей позиции.
+CREATE TABLE IF NOT EXISTS user_check_state (
+    user_id    UUID PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_check_state;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Попадания по инцидентам по минутам и множество (минута, инцидент, user_id)
+-- для уникальных пользователей. Сворачиваются вместе с остальными агрегатами.
+CREATE TABLE IF NOT EXISTS location_checks_rollup_incident_minute (
+    bucket      TIMESTAMPTZ NOT NULL,
+    incident_id UUID        NOT NULL,
+    hits        BIGINT      NOT NULL DEFAULT 0,
+    PRIMARY KEY (bucket, incident_id)
+);
+
+CREATE TABLE IF NOT EXISTS location_checks_rollup_incident_users (
+    bucket      TIMESTAMPTZ NOT NULL,
+    incident_id UUID        NOT NULL,
+    user_id     UUID        NOT NULL,
+    PRIMARY KEY (bucket, incident_id, user_id)
+);
+
+-- То, что уже свёрнуто до водяного знака, досчитываем из сырых проверок.
+INSERT INTO location_checks_rollup_incident_minute (bucket, incident_id, hits)
+SELECT date_bin('1 minute', c.checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'), h.incident_id, COUNT(*)
+FROM location_checks c
+CROSS JOIN LATERAL unnest(c.incident_ids) AS h(incident_id)
+JOIN location_checks_rollup_state s ON s.name = 'location_checks'
+WHERE c.checked_at < s.rolled_up_to
+GROUP BY 1, 2;
+
+INSERT INTO location_checks_rollup_incident_users (bucket, incident_id, user_id)
+SELECT DISTINCT date_bin('1 minute', c.checked_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'), h.incident_id, c.user_id
+FROM location_checks c
+CROSS JOIN LATERAL unnest(c.incident_ids) AS h(incident_id)
+JOIN location_checks_rollup_state s ON s.name = 'location_checks'
+WHERE c.checked_at < s.rolled_up_to;
+
+-- +goose Down
+DROP TABLE IF EXISTS location_checks_rollup_incident_users;
+DROP TABLE IF EXISTS location_checks_rollup_incident_minute;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Событие, которое нельзя разобрать или не удалось опубликовать за
+-- maxOutboxAttempts попыток, помечается failed_at и больше не берётся relay:
+-- иначе одна такая строка навсегда держала бы голову очереди.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS failed_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS outbox_pending_idx;
+CREATE INDEX IF NOT EXISTS outbox_pending_idx
+    ON outbox (created_at)
+    WHERE sent_at IS NULL AND failed_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS outbox_pending_idx;
+CREATE INDEX IF NOT EXISTS outbox_pending_idx
+    ON outbox (created_at)
+    WHERE sent_at IS NULL;
+ALTER TABLE outbox DROP COLUMN IF EXISTS failed_at;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Статистика одного инцидента читает агрегаты по incident_id; первичный
+-- ключ начинается с bucket и для этого не подходит.
+CREATE INDEX IF NOT EXISTS location_checks_rollup_incident_minute_incident_idx
+    ON location_checks_rollup_incident_minute (incident_id, bucket);
+
+CREATE INDEX IF NOT EXISTS location_checks_rollup_incident_users_incident_idx
+    ON location_checks_rollup_incident_users (incident_id, bucket);
+
+-- +goose Down
+DROP INDEX IF EXISTS location_checks_rollup_incident_users_incident_idx;
+DROP INDEX IF EXISTS location_checks_rollup_incident_minute_incident_idx;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Выход из-за закрытия инцидента не связан ни с проверкой, ни с точкой:
+-- check_id и координаты у него NULL, а причину перехода хранит reason.
+ALTER TABLE incident_transitions
+    ADD COLUMN IF NOT EXISTS reason TEXT NOT NULL DEFAULT 'check'
+        CHECK (reason IN ('check', 'incident_closed'));
+
+ALTER TABLE incident_transitions
+    ALTER COLUMN check_id DROP NOT NULL,
+    ALTER COLUMN lat DROP NOT NULL,
+    ALTER COLUMN lng DROP NOT NULL;
+
+-- Выходы по закрытию, записанные раньше с нулевым check_id и точкой инцидента.
+UPDATE incident_transitions
+SET reason = 'incident_closed', check_id = NULL, lat = NULL, lng = NULL
+WHERE check_id = '00000000-0000-0000-0000-000000000000';
+
+-- +goose Down
+DELETE FROM incident_transitions WHERE check_id IS NULL OR lat IS NULL OR lng IS NULL;
+
+ALTER TABLE incident_transitions
+    ALTER COLUMN check_id SET NOT NULL,
+    ALTER COLUMN lat SET NOT NULL,
+    ALTER COLUMN lng SET NOT NULL;
+
+ALTER TABLE incident_transitions DROP COLUMN IF EXISTS reason;
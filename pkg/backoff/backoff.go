@@ -0,0 +1,42 @@
+package backoff
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Exponential — задержка Base·2^(attempt-1), не больше Max, со «equal jitter»:
+// половина задержки фиксирована, половина случайна. Так повторы не
+// приходят к получателю одной волной, но и не становятся почти мгновенными.
+type Exponential struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay — пауза перед попыткой attempt+1 после attempt неудачных (attempt >= 1).
+func (b Exponential) Delay(attempt int) time.Duration {
+	d := b.Ceil(attempt)
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// Ceil — задержка без джиттера, верхняя граница Delay.
+func (b Exponential) Ceil(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := b.Base
+	for i := 1; i < attempt; i++ {
+		if b.Max > 0 && d >= b.Max {
+			break
+		}
+		d *= 2
+	}
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	return d
+}
@@ -0,0 +1,44 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponential_Ceil(t *testing.T) {
+	b := Exponential{Base: time.Second, Max: 30 * time.Second}
+
+	for attempt, want := range map[int]time.Duration{
+		0:   time.Second,
+		1:   time.Second,
+		2:   2 * time.Second,
+		4:   8 * time.Second,
+		5:   16 * time.Second,
+		6:   30 * time.Second,
+		100: 30 * time.Second,
+	} {
+		if got := b.Ceil(attempt); got != want {
+			t.Fatalf("attempt %d: expected %v got %v", attempt, want, got)
+		}
+	}
+}
+
+func TestExponential_Delay_WithinJitterBounds(t *testing.T) {
+	b := Exponential{Base: time.Second, Max: time.Minute}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		ceil := b.Ceil(attempt)
+		for range 100 {
+			d := b.Delay(attempt)
+			if d < ceil/2 || d > ceil {
+				t.Fatalf("attempt %d: delay %v outside [%v, %v]", attempt, d, ceil/2, ceil)
+			}
+		}
+	}
+}
+
+func TestExponential_Delay_ZeroBase(t *testing.T) {
+	if d := (Exponential{}).Delay(3); d != 0 {
+		t.Fatalf("expected 0 got %v", d)
+	}
+}
@@ -0,0 +1,51 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+)
+
+// Cursor — позиция keyset-пагинации: последняя отданная строка по (time, id).
+// Клиенту уходит непрозрачной base64-строкой.
+type Cursor struct {
+	At time.Time
+	ID uuid.UUID
+}
+
+func New(at time.Time, id uuid.UUID) *Cursor {
+	return &Cursor{At: at, ID: id}
+}
+
+func (c Cursor) Encode() string {
+	raw := c.At.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func Decode(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", e.ErrInvalidInput)
+	}
+
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed cursor", e.ErrInvalidInput)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", e.ErrInvalidInput)
+	}
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", e.ErrInvalidInput)
+	}
+
+	return &Cursor{At: t, ID: u}, nil
+}
@@ -0,0 +1,32 @@
+package cursor_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"redCollar/pkg/cursor"
+	"redCollar/pkg/e"
+
+	"github.com/google/uuid"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := cursor.New(time.Date(2025, 3, 1, 12, 30, 0, 123456000, time.UTC), uuid.New())
+
+	got, err := cursor.Decode(c.Encode())
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !got.At.Equal(c.At) || got.ID != c.ID {
+		t.Fatalf("round trip mismatch: %+v != %+v", got, c)
+	}
+}
+
+func TestCursor_DecodeMalformed(t *testing.T) {
+	for _, s := range []string{"", "!!!", "bm9waXBl", "MjAyNS0wMS0wMXxub3QtYS11dWlk"} {
+		if _, err := cursor.Decode(s); !errors.Is(err, e.ErrInvalidInput) {
+			t.Fatalf("Decode(%q): expected ErrInvalidInput, got %v", s, err)
+		}
+	}
+}
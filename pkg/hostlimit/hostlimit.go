@@ -0,0 +1,107 @@
+package hostlimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter ограничивает число одновременных запросов к одному хосту и
+// держит circuit breaker на каждый хост: после Threshold неудач подряд хост
+// закрывается на Cooldown, затем пропускается одна пробная попытка —
+// успех снимает паузу, неудача продлевает её.
+type Limiter struct {
+	limit     int
+	threshold int
+	cooldown  time.Duration
+	// busyDelay — через сколько пробовать снова, если все слоты хоста заняты.
+	busyDelay time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+type host struct {
+	inFlight  int
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func New(limit, threshold int, cooldown, busyDelay time.Duration) *Limiter {
+	if limit <= 0 {
+		limit = 1
+	}
+	return &Limiter{
+		limit:     limit,
+		threshold: threshold,
+		cooldown:  cooldown,
+		busyDelay: busyDelay,
+		hosts:     make(map[string]*host),
+	}
+}
+
+// Acquire занимает слот хоста. Если нельзя, возвращает время, когда стоит
+// попробовать снова. Занятый слот обязательно отпускается через Release.
+func (l *Limiter) Acquire(name string, now time.Time) (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	h := l.hosts[name]
+	if h == nil {
+		h = &host{}
+		l.hosts[name] = h
+	}
+
+	if l.open(h) {
+		if now.Before(h.openUntil) {
+			return h.openUntil, false
+		}
+		// Пауза кончилась: пропускаем одну пробную попытку.
+		if h.probing || h.inFlight > 0 {
+			return now.Add(l.busyDelay), false
+		}
+		h.probing = true
+	} else if h.inFlight >= l.limit {
+		return now.Add(l.busyDelay), false
+	}
+
+	h.inFlight++
+	return time.Time{}, true
+}
+
+// Release отпускает слот и учитывает исход запроса. true — хост только что
+// поставлен на паузу.
+func (l *Limiter) Release(name string, ok bool, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	h := l.hosts[name]
+	if h == nil {
+		return false
+	}
+	if h.inFlight > 0 {
+		h.inFlight--
+	}
+	wasProbe := h.probing
+	h.probing = false
+
+	if ok {
+		h.failures = 0
+		h.openUntil = time.Time{}
+		if h.inFlight == 0 {
+			delete(l.hosts, name)
+		}
+		return false
+	}
+
+	h.failures++
+	if l.open(h) && (wasProbe || h.failures == l.threshold) {
+		h.openUntil = now.Add(l.cooldown)
+		return true
+	}
+	return false
+}
+
+func (l *Limiter) open(h *host) bool {
+	return l.threshold > 0 && h.failures >= l.threshold
+}
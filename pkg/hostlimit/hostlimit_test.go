@@ -0,0 +1,78 @@
+package hostlimit
+
+import (
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestLimiter_PerHostLimit(t *testing.T) {
+	l := New(2, 0, time.Minute, time.Second)
+
+	for i := 0; i < 2; i++ {
+		if _, ok := l.Acquire("a.example", t0); !ok {
+			t.Fatalf("acquire %d: expected ok", i)
+		}
+	}
+	at, ok := l.Acquire("a.example", t0)
+	if ok || !at.Equal(t0.Add(time.Second)) {
+		t.Fatalf("expected busy until %v, got %v ok=%v", t0.Add(time.Second), at, ok)
+	}
+	if _, ok := l.Acquire("b.example", t0); !ok {
+		t.Fatalf("other host must not be limited")
+	}
+
+	l.Release("a.example", true, t0)
+	if _, ok := l.Acquire("a.example", t0); !ok {
+		t.Fatalf("expected free slot after release")
+	}
+}
+
+func TestLimiter_BreakerOpensAndProbes(t *testing.T) {
+	l := New(5, 3, time.Minute, time.Second)
+
+	for i := 0; i < 3; i++ {
+		if _, ok := l.Acquire("a.example", t0); !ok {
+			t.Fatalf("acquire %d: expected ok", i)
+		}
+		opened := l.Release("a.example", false, t0)
+		if opened != (i == 2) {
+			t.Fatalf("release %d: opened=%v", i, opened)
+		}
+	}
+
+	at, ok := l.Acquire("a.example", t0.Add(30*time.Second))
+	if ok || !at.Equal(t0.Add(time.Minute)) {
+		t.Fatalf("expected paused until %v, got %v ok=%v", t0.Add(time.Minute), at, ok)
+	}
+
+	// После паузы — ровно одна пробная попытка.
+	after := t0.Add(2 * time.Minute)
+	if _, ok := l.Acquire("a.example", after); !ok {
+		t.Fatalf("expected probe to pass")
+	}
+	if _, ok := l.Acquire("a.example", after); ok {
+		t.Fatalf("expected second request to wait for probe")
+	}
+
+	// Неудачная проба снова ставит хост на паузу.
+	if !l.Release("a.example", false, after) {
+		t.Fatalf("expected failed probe to reopen breaker")
+	}
+	if _, ok := l.Acquire("a.example", after.Add(time.Second)); ok {
+		t.Fatalf("expected host paused after failed probe")
+	}
+
+	// Удачная проба закрывает breaker.
+	later := after.Add(2 * time.Minute)
+	if _, ok := l.Acquire("a.example", later); !ok {
+		t.Fatalf("expected probe to pass")
+	}
+	l.Release("a.example", true, later)
+	for i := 0; i < 5; i++ {
+		if _, ok := l.Acquire("a.example", later); !ok {
+			t.Fatalf("acquire %d: expected closed breaker", i)
+		}
+	}
+}
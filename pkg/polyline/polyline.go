@@ -0,0 +1,59 @@
+package polyline
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrInvalid = errors.New("invalid polyline")
+
+// Point — вершина ломаной в градусах.
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Decode разбирает Encoded Polyline Algorithm Format: точность 5 — Google,
+// 6 — OSRM/Valhalla. Каждая координата — дельта от предыдущей, zigzag и
+// блоки по 5 бит со старшим битом продолжения.
+func Decode(s string, precision int) ([]Point, error) {
+	factor := math.Pow10(precision)
+
+	var (
+		points   []Point
+		lat, lng int64
+	)
+	for i := 0; i < len(s); {
+		var delta [2]int64
+		for k := range delta {
+			var (
+				result int64
+				shift  uint
+			)
+			for {
+				if i >= len(s) {
+					return nil, ErrInvalid
+				}
+				b := int64(s[i]) - 63
+				i++
+				if b < 0 || b > 63 || shift > 60 {
+					return nil, ErrInvalid
+				}
+				result |= (b & 0x1f) << shift
+				shift += 5
+				if b < 0x20 {
+					break
+				}
+			}
+			if result&1 != 0 {
+				delta[k] = ^(result >> 1)
+			} else {
+				delta[k] = result >> 1
+			}
+		}
+		lat += delta[0]
+		lng += delta[1]
+		points = append(points, Point{Lat: float64(lat) / factor, Lng: float64(lng) / factor})
+	}
+	return points, nil
+}
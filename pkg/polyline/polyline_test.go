@@ -0,0 +1,41 @@
+package polyline
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecode_GoogleExample(t *testing.T) {
+	got, err := Decode("_p~iF~ps|U_ulLnnqC_mqNvxq`@", 5)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	want := []Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d points got %d", len(want), len(got))
+	}
+	for i := range want {
+		if math.Abs(got[i].Lat-want[i].Lat) > 1e-9 || math.Abs(got[i].Lng-want[i].Lng) > 1e-9 {
+			t.Fatalf("point %d: expected %v got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestDecode_Precision6(t *testing.T) {
+	got, err := Decode("_izlhA~rlgdF", 6)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(got) != 1 || math.Abs(got[0].Lat-38.5) > 1e-9 || math.Abs(got[0].Lng+120.2) > 1e-9 {
+		t.Fatalf("unexpected points: %v", got)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for _, s := range []string{"_p~iF", "_p~iF~ps|", "\x01\x02"} {
+		if _, err := Decode(s, 5); err != ErrInvalid {
+			t.Fatalf("%q: expected ErrInvalid, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Package webhooksig подписывает и проверяет вебхуки redCollar.
+//
+// Подпись — HMAC-SHA256 по строке "<timestamp>.<body>", где timestamp —
+// unix-секунды из заголовка X-Timestamp. В X-Signature может быть несколько
+// подписей через запятую ("v1=<hex>,v1=<hex>") — на время ротации секрета
+// запрос подписывается и старым, и новым. Получателю достаточно:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	if err := webhooksig.Verify(r.Header, body, 5*time.Minute, secret); err != nil {
+//		http.Error(w, "bad signature", http.StatusUnauthorized)
+//		return
+//	}
+//
+// Окно Tolerance отсекает повтор старых запросов; X-Delivery-ID одинаков
+// у всех попыток одной доставки, по нему стоит отбрасывать дубли.
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderSignature  = "X-Signature"
+	HeaderTimestamp  = "X-Timestamp"
+	HeaderDeliveryID = "X-Delivery-ID"
+
+	scheme = "v1="
+)
+
+var (
+	ErrMissingHeader     = errors.New("webhooksig: missing signature or timestamp header")
+	ErrInvalidTimestamp  = errors.New("webhooksig: invalid timestamp")
+	ErrTimestampExpired  = errors.New("webhooksig: timestamp outside tolerance")
+	ErrSignatureMismatch = errors.New("webhooksig: signature mismatch")
+)
+
+// Sign возвращает подпись "v1=<hex>" для одного секрета.
+func Sign(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return scheme + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader — значение X-Signature: подписи всеми переданными секретами.
+func SignatureHeader(secrets []string, ts int64, body []byte) string {
+	sigs := make([]string, 0, len(secrets))
+	for _, s := range secrets {
+		if s != "" {
+			sigs = append(sigs, Sign(s, ts, body))
+		}
+	}
+	return strings.Join(sigs, ",")
+}
+
+// SetHeaders проставляет X-Timestamp, X-Delivery-ID и, если есть секреты, X-Signature.
+func SetHeaders(h http.Header, deliveryID string, secrets []string, now time.Time, body []byte) {
+	ts := now.Unix()
+	h.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	h.Set(HeaderDeliveryID, deliveryID)
+	if sig := SignatureHeader(secrets, ts, body); sig != "" {
+		h.Set(HeaderSignature, sig)
+	}
+}
+
+// Verify проверяет подпись запроса. Подходит любая пара (подпись, секрет),
+// поэтому получатель тоже может держать два секрета на время ротации.
+// tolerance <= 0 отключает проверку времени.
+func Verify(h http.Header, body []byte, tolerance time.Duration, secrets ...string) error {
+	return VerifyAt(h, body, tolerance, time.Now(), secrets...)
+}
+
+// VerifyAt — Verify с явным текущим временем.
+func VerifyAt(h http.Header, body []byte, tolerance time.Duration, now time.Time, secrets ...string) error {
+	sigHeader, tsHeader := h.Get(HeaderSignature), h.Get(HeaderTimestamp)
+	if sigHeader == "" || tsHeader == "" {
+		return ErrMissingHeader
+	}
+
+	ts, err := strconv.ParseInt(tsHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if tolerance > 0 {
+		d := now.Sub(time.Unix(ts, 0))
+		if d < -tolerance || d > tolerance {
+			return ErrTimestampExpired
+		}
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		want := Sign(secret, ts, body)
+		for _, got := range strings.Split(sigHeader, ",") {
+			if hmac.Equal([]byte(strings.TrimSpace(got)), []byte(want)) {
+				return nil
+			}
+		}
+	}
+	return ErrSignatureMismatch
+}
@@ -0,0 +1,69 @@
+package webhooksig_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"redCollar/pkg/webhooksig"
+)
+
+func TestVerify_RoundTrip(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"user_id":"u1"}`)
+
+	h := http.Header{}
+	webhooksig.SetHeaders(h, "d1", []string{"new-secret", "old-secret"}, now, body)
+
+	if h.Get(webhooksig.HeaderDeliveryID) != "d1" {
+		t.Fatalf("delivery id not set")
+	}
+	for _, secret := range []string{"new-secret", "old-secret"} {
+		if err := webhooksig.VerifyAt(h, body, 5*time.Minute, now.Add(time.Minute), secret); err != nil {
+			t.Fatalf("VerifyAt(%s): %v", secret, err)
+		}
+	}
+}
+
+func TestVerify_Failures(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"user_id":"u1"}`)
+
+	h := http.Header{}
+	webhooksig.SetHeaders(h, "d1", []string{"secret"}, now, body)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		now    time.Time
+		secret string
+		want   error
+	}{
+		{"wrong secret", h, body, now, "other", webhooksig.ErrSignatureMismatch},
+		{"tampered body", h, []byte(`{"user_id":"u2"}`), now, "secret", webhooksig.ErrSignatureMismatch},
+		{"replayed late", h, body, now.Add(10 * time.Minute), "secret", webhooksig.ErrTimestampExpired},
+		{"no headers", http.Header{}, body, now, "secret", webhooksig.ErrMissingHeader},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := webhooksig.VerifyAt(tt.header, tt.body, 5*time.Minute, tt.now, tt.secret)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSetHeaders_NoSecrets(t *testing.T) {
+	h := http.Header{}
+	webhooksig.SetHeaders(h, "d1", nil, time.Now(), []byte("{}"))
+
+	if h.Get(webhooksig.HeaderSignature) != "" {
+		t.Fatalf("expected no signature without secrets")
+	}
+	if h.Get(webhooksig.HeaderTimestamp) == "" {
+		t.Fatalf("expected timestamp header")
+	}
+}